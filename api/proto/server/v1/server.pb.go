@@ -0,0 +1,1200 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: server/v1/server.proto
+
+package serverv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ServerStatus int32
+
+const (
+	ServerStatus_SERVER_STATUS_UNSPECIFIED ServerStatus = 0
+	ServerStatus_SERVER_STATUS_UNKNOWN     ServerStatus = 1
+	ServerStatus_SERVER_STATUS_ONLINE      ServerStatus = 2
+	ServerStatus_SERVER_STATUS_OFFLINE     ServerStatus = 3
+)
+
+// Enum value maps for ServerStatus.
+var (
+	ServerStatus_name = map[int32]string{
+		0: "SERVER_STATUS_UNSPECIFIED",
+		1: "SERVER_STATUS_UNKNOWN",
+		2: "SERVER_STATUS_ONLINE",
+		3: "SERVER_STATUS_OFFLINE",
+	}
+	ServerStatus_value = map[string]int32{
+		"SERVER_STATUS_UNSPECIFIED": 0,
+		"SERVER_STATUS_UNKNOWN":     1,
+		"SERVER_STATUS_ONLINE":      2,
+		"SERVER_STATUS_OFFLINE":     3,
+	}
+)
+
+func (x ServerStatus) Enum() *ServerStatus {
+	p := new(ServerStatus)
+	*p = x
+	return p
+}
+
+func (x ServerStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ServerStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_server_v1_server_proto_enumTypes[0].Descriptor()
+}
+
+func (ServerStatus) Type() protoreflect.EnumType {
+	return &file_server_v1_server_proto_enumTypes[0]
+}
+
+func (x ServerStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ServerStatus.Descriptor instead.
+func (ServerStatus) EnumDescriptor() ([]byte, []int) {
+	return file_server_v1_server_proto_rawDescGZIP(), []int{0}
+}
+
+type Server struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	ServerId     string                 `protobuf:"bytes,1,opt,name=server_id,json=serverId,proto3" json:"server_id,omitempty"`
+	ServerName   string                 `protobuf:"bytes,2,opt,name=server_name,json=serverName,proto3" json:"server_name,omitempty"`
+	Ipv4         string                 `protobuf:"bytes,3,opt,name=ipv4,proto3" json:"ipv4,omitempty"`
+	Status       ServerStatus           `protobuf:"varint,4,opt,name=status,proto3,enum=server.v1.ServerStatus" json:"status,omitempty"`
+	Location     string                 `protobuf:"bytes,5,opt,name=location,proto3" json:"location,omitempty"`
+	Os           string                 `protobuf:"bytes,6,opt,name=os,proto3" json:"os,omitempty"`
+	Group        string                 `protobuf:"bytes,7,opt,name=group,proto3" json:"group,omitempty"`
+	Labels       map[string]string      `protobuf:"bytes,8,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	IntervalTime int32                  `protobuf:"varint,9,opt,name=interval_time,json=intervalTime,proto3" json:"interval_time,omitempty"`
+	// Set only for soft-deleted servers.
+	DeletedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Server) Reset() {
+	*x = Server{}
+	mi := &file_server_v1_server_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Server) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Server) ProtoMessage() {}
+
+func (x *Server) ProtoReflect() protoreflect.Message {
+	mi := &file_server_v1_server_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Server.ProtoReflect.Descriptor instead.
+func (*Server) Descriptor() ([]byte, []int) {
+	return file_server_v1_server_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Server) GetServerId() string {
+	if x != nil {
+		return x.ServerId
+	}
+	return ""
+}
+
+func (x *Server) GetServerName() string {
+	if x != nil {
+		return x.ServerName
+	}
+	return ""
+}
+
+func (x *Server) GetIpv4() string {
+	if x != nil {
+		return x.Ipv4
+	}
+	return ""
+}
+
+func (x *Server) GetStatus() ServerStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ServerStatus_SERVER_STATUS_UNSPECIFIED
+}
+
+func (x *Server) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *Server) GetOs() string {
+	if x != nil {
+		return x.Os
+	}
+	return ""
+}
+
+func (x *Server) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *Server) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Server) GetIntervalTime() int32 {
+	if x != nil {
+		return x.IntervalTime
+	}
+	return 0
+}
+
+func (x *Server) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+type ServerFilter struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ServerName     *string                `protobuf:"bytes,1,opt,name=server_name,json=serverName,proto3,oneof" json:"server_name,omitempty"`
+	Status         ServerStatus           `protobuf:"varint,2,opt,name=status,proto3,enum=server.v1.ServerStatus" json:"status,omitempty"`
+	IncludeDeleted bool                   `protobuf:"varint,3,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ServerFilter) Reset() {
+	*x = ServerFilter{}
+	mi := &file_server_v1_server_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerFilter) ProtoMessage() {}
+
+func (x *ServerFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_server_v1_server_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerFilter.ProtoReflect.Descriptor instead.
+func (*ServerFilter) Descriptor() ([]byte, []int) {
+	return file_server_v1_server_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ServerFilter) GetServerName() string {
+	if x != nil && x.ServerName != nil {
+		return *x.ServerName
+	}
+	return ""
+}
+
+func (x *ServerFilter) GetStatus() ServerStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ServerStatus_SERVER_STATUS_UNSPECIFIED
+}
+
+func (x *ServerFilter) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+type Pagination struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Defaults to 1.
+	Page int32 `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	// Defaults to 10, at most 100.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// One of server_name, ipv4, status, location, os or interval_time;
+	// defaults to server_name.
+	SortBy string `protobuf:"bytes,3,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	// asc or desc; defaults to asc.
+	SortOrder     string `protobuf:"bytes,4,opt,name=sort_order,json=sortOrder,proto3" json:"sort_order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Pagination) Reset() {
+	*x = Pagination{}
+	mi := &file_server_v1_server_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Pagination) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pagination) ProtoMessage() {}
+
+func (x *Pagination) ProtoReflect() protoreflect.Message {
+	mi := &file_server_v1_server_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pagination.ProtoReflect.Descriptor instead.
+func (*Pagination) Descriptor() ([]byte, []int) {
+	return file_server_v1_server_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Pagination) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *Pagination) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *Pagination) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *Pagination) GetSortOrder() string {
+	if x != nil {
+		return x.SortOrder
+	}
+	return ""
+}
+
+type CreateServerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServerId      string                 `protobuf:"bytes,1,opt,name=server_id,json=serverId,proto3" json:"server_id,omitempty"`
+	ServerName    string                 `protobuf:"bytes,2,opt,name=server_name,json=serverName,proto3" json:"server_name,omitempty"`
+	Ipv4          string                 `protobuf:"bytes,3,opt,name=ipv4,proto3" json:"ipv4,omitempty"`
+	Location      *string                `protobuf:"bytes,4,opt,name=location,proto3,oneof" json:"location,omitempty"`
+	Os            *string                `protobuf:"bytes,5,opt,name=os,proto3,oneof" json:"os,omitempty"`
+	Group         *string                `protobuf:"bytes,6,opt,name=group,proto3,oneof" json:"group,omitempty"`
+	Labels        map[string]string      `protobuf:"bytes,7,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	IntervalTime  int32                  `protobuf:"varint,8,opt,name=interval_time,json=intervalTime,proto3" json:"interval_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateServerRequest) Reset() {
+	*x = CreateServerRequest{}
+	mi := &file_server_v1_server_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateServerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateServerRequest) ProtoMessage() {}
+
+func (x *CreateServerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_v1_server_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateServerRequest.ProtoReflect.Descriptor instead.
+func (*CreateServerRequest) Descriptor() ([]byte, []int) {
+	return file_server_v1_server_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateServerRequest) GetServerId() string {
+	if x != nil {
+		return x.ServerId
+	}
+	return ""
+}
+
+func (x *CreateServerRequest) GetServerName() string {
+	if x != nil {
+		return x.ServerName
+	}
+	return ""
+}
+
+func (x *CreateServerRequest) GetIpv4() string {
+	if x != nil {
+		return x.Ipv4
+	}
+	return ""
+}
+
+func (x *CreateServerRequest) GetLocation() string {
+	if x != nil && x.Location != nil {
+		return *x.Location
+	}
+	return ""
+}
+
+func (x *CreateServerRequest) GetOs() string {
+	if x != nil && x.Os != nil {
+		return *x.Os
+	}
+	return ""
+}
+
+func (x *CreateServerRequest) GetGroup() string {
+	if x != nil && x.Group != nil {
+		return *x.Group
+	}
+	return ""
+}
+
+func (x *CreateServerRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *CreateServerRequest) GetIntervalTime() int32 {
+	if x != nil {
+		return x.IntervalTime
+	}
+	return 0
+}
+
+// UpdateServerRequest changes the fields that are set. Labels replace the
+// current labels when any are sent.
+type UpdateServerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServerId      string                 `protobuf:"bytes,1,opt,name=server_id,json=serverId,proto3" json:"server_id,omitempty"`
+	ServerName    *string                `protobuf:"bytes,2,opt,name=server_name,json=serverName,proto3,oneof" json:"server_name,omitempty"`
+	Ipv4          *string                `protobuf:"bytes,3,opt,name=ipv4,proto3,oneof" json:"ipv4,omitempty"`
+	Location      *string                `protobuf:"bytes,4,opt,name=location,proto3,oneof" json:"location,omitempty"`
+	Os            *string                `protobuf:"bytes,5,opt,name=os,proto3,oneof" json:"os,omitempty"`
+	Group         *string                `protobuf:"bytes,6,opt,name=group,proto3,oneof" json:"group,omitempty"`
+	Labels        map[string]string      `protobuf:"bytes,7,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	IntervalTime  *int32                 `protobuf:"varint,8,opt,name=interval_time,json=intervalTime,proto3,oneof" json:"interval_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateServerRequest) Reset() {
+	*x = UpdateServerRequest{}
+	mi := &file_server_v1_server_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateServerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateServerRequest) ProtoMessage() {}
+
+func (x *UpdateServerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_v1_server_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateServerRequest.ProtoReflect.Descriptor instead.
+func (*UpdateServerRequest) Descriptor() ([]byte, []int) {
+	return file_server_v1_server_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UpdateServerRequest) GetServerId() string {
+	if x != nil {
+		return x.ServerId
+	}
+	return ""
+}
+
+func (x *UpdateServerRequest) GetServerName() string {
+	if x != nil && x.ServerName != nil {
+		return *x.ServerName
+	}
+	return ""
+}
+
+func (x *UpdateServerRequest) GetIpv4() string {
+	if x != nil && x.Ipv4 != nil {
+		return *x.Ipv4
+	}
+	return ""
+}
+
+func (x *UpdateServerRequest) GetLocation() string {
+	if x != nil && x.Location != nil {
+		return *x.Location
+	}
+	return ""
+}
+
+func (x *UpdateServerRequest) GetOs() string {
+	if x != nil && x.Os != nil {
+		return *x.Os
+	}
+	return ""
+}
+
+func (x *UpdateServerRequest) GetGroup() string {
+	if x != nil && x.Group != nil {
+		return *x.Group
+	}
+	return ""
+}
+
+func (x *UpdateServerRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *UpdateServerRequest) GetIntervalTime() int32 {
+	if x != nil && x.IntervalTime != nil {
+		return *x.IntervalTime
+	}
+	return 0
+}
+
+type DeleteServerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServerId      string                 `protobuf:"bytes,1,opt,name=server_id,json=serverId,proto3" json:"server_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteServerRequest) Reset() {
+	*x = DeleteServerRequest{}
+	mi := &file_server_v1_server_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteServerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteServerRequest) ProtoMessage() {}
+
+func (x *DeleteServerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_v1_server_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteServerRequest.ProtoReflect.Descriptor instead.
+func (*DeleteServerRequest) Descriptor() ([]byte, []int) {
+	return file_server_v1_server_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeleteServerRequest) GetServerId() string {
+	if x != nil {
+		return x.ServerId
+	}
+	return ""
+}
+
+type DeleteServerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteServerResponse) Reset() {
+	*x = DeleteServerResponse{}
+	mi := &file_server_v1_server_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteServerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteServerResponse) ProtoMessage() {}
+
+func (x *DeleteServerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_server_v1_server_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteServerResponse.ProtoReflect.Descriptor instead.
+func (*DeleteServerResponse) Descriptor() ([]byte, []int) {
+	return file_server_v1_server_proto_rawDescGZIP(), []int{6}
+}
+
+type RestoreServerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServerId      string                 `protobuf:"bytes,1,opt,name=server_id,json=serverId,proto3" json:"server_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreServerRequest) Reset() {
+	*x = RestoreServerRequest{}
+	mi := &file_server_v1_server_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreServerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreServerRequest) ProtoMessage() {}
+
+func (x *RestoreServerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_v1_server_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreServerRequest.ProtoReflect.Descriptor instead.
+func (*RestoreServerRequest) Descriptor() ([]byte, []int) {
+	return file_server_v1_server_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RestoreServerRequest) GetServerId() string {
+	if x != nil {
+		return x.ServerId
+	}
+	return ""
+}
+
+type ListServersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *ServerFilter          `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	Pagination    *Pagination            `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListServersRequest) Reset() {
+	*x = ListServersRequest{}
+	mi := &file_server_v1_server_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListServersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListServersRequest) ProtoMessage() {}
+
+func (x *ListServersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_v1_server_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListServersRequest.ProtoReflect.Descriptor instead.
+func (*ListServersRequest) Descriptor() ([]byte, []int) {
+	return file_server_v1_server_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListServersRequest) GetFilter() *ServerFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListServersRequest) GetPagination() *Pagination {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+type ListServersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Servers       []*Server              `protobuf:"bytes,1,rep,name=servers,proto3" json:"servers,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListServersResponse) Reset() {
+	*x = ListServersResponse{}
+	mi := &file_server_v1_server_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListServersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListServersResponse) ProtoMessage() {}
+
+func (x *ListServersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_server_v1_server_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListServersResponse.ProtoReflect.Descriptor instead.
+func (*ListServersResponse) Descriptor() ([]byte, []int) {
+	return file_server_v1_server_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListServersResponse) GetServers() []*Server {
+	if x != nil {
+		return x.Servers
+	}
+	return nil
+}
+
+func (x *ListServersResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type ImportServersRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	FileName string                 `protobuf:"bytes,1,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	// The XLSX workbook.
+	Content       []byte `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportServersRequest) Reset() {
+	*x = ImportServersRequest{}
+	mi := &file_server_v1_server_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportServersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportServersRequest) ProtoMessage() {}
+
+func (x *ImportServersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_v1_server_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportServersRequest.ProtoReflect.Descriptor instead.
+func (*ImportServersRequest) Descriptor() ([]byte, []int) {
+	return file_server_v1_server_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ImportServersRequest) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *ImportServersRequest) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+type ImportServersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SuccessCount  int32                  `protobuf:"varint,1,opt,name=success_count,json=successCount,proto3" json:"success_count,omitempty"`
+	ServerIds     []string               `protobuf:"bytes,2,rep,name=server_ids,json=serverIds,proto3" json:"server_ids,omitempty"`
+	FailedCount   int32                  `protobuf:"varint,3,opt,name=failed_count,json=failedCount,proto3" json:"failed_count,omitempty"`
+	FailedServers []string               `protobuf:"bytes,4,rep,name=failed_servers,json=failedServers,proto3" json:"failed_servers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportServersResponse) Reset() {
+	*x = ImportServersResponse{}
+	mi := &file_server_v1_server_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportServersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportServersResponse) ProtoMessage() {}
+
+func (x *ImportServersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_server_v1_server_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportServersResponse.ProtoReflect.Descriptor instead.
+func (*ImportServersResponse) Descriptor() ([]byte, []int) {
+	return file_server_v1_server_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ImportServersResponse) GetSuccessCount() int32 {
+	if x != nil {
+		return x.SuccessCount
+	}
+	return 0
+}
+
+func (x *ImportServersResponse) GetServerIds() []string {
+	if x != nil {
+		return x.ServerIds
+	}
+	return nil
+}
+
+func (x *ImportServersResponse) GetFailedCount() int32 {
+	if x != nil {
+		return x.FailedCount
+	}
+	return 0
+}
+
+func (x *ImportServersResponse) GetFailedServers() []string {
+	if x != nil {
+		return x.FailedServers
+	}
+	return nil
+}
+
+type WatchStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *ServerFilter          `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchStatusRequest) Reset() {
+	*x = WatchStatusRequest{}
+	mi := &file_server_v1_server_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchStatusRequest) ProtoMessage() {}
+
+func (x *WatchStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_server_v1_server_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchStatusRequest.ProtoReflect.Descriptor instead.
+func (*WatchStatusRequest) Descriptor() ([]byte, []int) {
+	return file_server_v1_server_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *WatchStatusRequest) GetFilter() *ServerFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type StatusEvent struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Server         *Server                `protobuf:"bytes,2,opt,name=server,proto3" json:"server,omitempty"`
+	PreviousStatus ServerStatus           `protobuf:"varint,3,opt,name=previous_status,json=previousStatus,proto3,enum=server.v1.ServerStatus" json:"previous_status,omitempty"`
+	OccurredAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *StatusEvent) Reset() {
+	*x = StatusEvent{}
+	mi := &file_server_v1_server_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusEvent) ProtoMessage() {}
+
+func (x *StatusEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_server_v1_server_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusEvent.ProtoReflect.Descriptor instead.
+func (*StatusEvent) Descriptor() ([]byte, []int) {
+	return file_server_v1_server_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *StatusEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *StatusEvent) GetServer() *Server {
+	if x != nil {
+		return x.Server
+	}
+	return nil
+}
+
+func (x *StatusEvent) GetPreviousStatus() ServerStatus {
+	if x != nil {
+		return x.PreviousStatus
+	}
+	return ServerStatus_SERVER_STATUS_UNSPECIFIED
+}
+
+func (x *StatusEvent) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+var File_server_v1_server_proto protoreflect.FileDescriptor
+
+const file_server_v1_server_proto_rawDesc = "" +
+	"\n" +
+	"\x16server/v1/server.proto\x12\tserver.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x9f\x03\n" +
+	"\x06Server\x12\x1b\n" +
+	"\tserver_id\x18\x01 \x01(\tR\bserverId\x12\x1f\n" +
+	"\vserver_name\x18\x02 \x01(\tR\n" +
+	"serverName\x12\x12\n" +
+	"\x04ipv4\x18\x03 \x01(\tR\x04ipv4\x12/\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x17.server.v1.ServerStatusR\x06status\x12\x1a\n" +
+	"\blocation\x18\x05 \x01(\tR\blocation\x12\x0e\n" +
+	"\x02os\x18\x06 \x01(\tR\x02os\x12\x14\n" +
+	"\x05group\x18\a \x01(\tR\x05group\x125\n" +
+	"\x06labels\x18\b \x03(\v2\x1d.server.v1.Server.LabelsEntryR\x06labels\x12#\n" +
+	"\rinterval_time\x18\t \x01(\x05R\fintervalTime\x129\n" +
+	"\n" +
+	"deleted_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x9e\x01\n" +
+	"\fServerFilter\x12$\n" +
+	"\vserver_name\x18\x01 \x01(\tH\x00R\n" +
+	"serverName\x88\x01\x01\x12/\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x17.server.v1.ServerStatusR\x06status\x12'\n" +
+	"\x0finclude_deleted\x18\x03 \x01(\bR\x0eincludeDeletedB\x0e\n" +
+	"\f_server_name\"u\n" +
+	"\n" +
+	"Pagination\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x17\n" +
+	"\asort_by\x18\x03 \x01(\tR\x06sortBy\x12\x1d\n" +
+	"\n" +
+	"sort_order\x18\x04 \x01(\tR\tsortOrder\"\xfa\x02\n" +
+	"\x13CreateServerRequest\x12\x1b\n" +
+	"\tserver_id\x18\x01 \x01(\tR\bserverId\x12\x1f\n" +
+	"\vserver_name\x18\x02 \x01(\tR\n" +
+	"serverName\x12\x12\n" +
+	"\x04ipv4\x18\x03 \x01(\tR\x04ipv4\x12\x1f\n" +
+	"\blocation\x18\x04 \x01(\tH\x00R\blocation\x88\x01\x01\x12\x13\n" +
+	"\x02os\x18\x05 \x01(\tH\x01R\x02os\x88\x01\x01\x12\x19\n" +
+	"\x05group\x18\x06 \x01(\tH\x02R\x05group\x88\x01\x01\x12B\n" +
+	"\x06labels\x18\a \x03(\v2*.server.v1.CreateServerRequest.LabelsEntryR\x06labels\x12#\n" +
+	"\rinterval_time\x18\b \x01(\x05R\fintervalTime\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\v\n" +
+	"\t_locationB\x05\n" +
+	"\x03_osB\b\n" +
+	"\x06_group\"\xb4\x03\n" +
+	"\x13UpdateServerRequest\x12\x1b\n" +
+	"\tserver_id\x18\x01 \x01(\tR\bserverId\x12$\n" +
+	"\vserver_name\x18\x02 \x01(\tH\x00R\n" +
+	"serverName\x88\x01\x01\x12\x17\n" +
+	"\x04ipv4\x18\x03 \x01(\tH\x01R\x04ipv4\x88\x01\x01\x12\x1f\n" +
+	"\blocation\x18\x04 \x01(\tH\x02R\blocation\x88\x01\x01\x12\x13\n" +
+	"\x02os\x18\x05 \x01(\tH\x03R\x02os\x88\x01\x01\x12\x19\n" +
+	"\x05group\x18\x06 \x01(\tH\x04R\x05group\x88\x01\x01\x12B\n" +
+	"\x06labels\x18\a \x03(\v2*.server.v1.UpdateServerRequest.LabelsEntryR\x06labels\x12(\n" +
+	"\rinterval_time\x18\b \x01(\x05H\x05R\fintervalTime\x88\x01\x01\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x0e\n" +
+	"\f_server_nameB\a\n" +
+	"\x05_ipv4B\v\n" +
+	"\t_locationB\x05\n" +
+	"\x03_osB\b\n" +
+	"\x06_groupB\x10\n" +
+	"\x0e_interval_time\"2\n" +
+	"\x13DeleteServerRequest\x12\x1b\n" +
+	"\tserver_id\x18\x01 \x01(\tR\bserverId\"\x16\n" +
+	"\x14DeleteServerResponse\"3\n" +
+	"\x14RestoreServerRequest\x12\x1b\n" +
+	"\tserver_id\x18\x01 \x01(\tR\bserverId\"|\n" +
+	"\x12ListServersRequest\x12/\n" +
+	"\x06filter\x18\x01 \x01(\v2\x17.server.v1.ServerFilterR\x06filter\x125\n" +
+	"\n" +
+	"pagination\x18\x02 \x01(\v2\x15.server.v1.PaginationR\n" +
+	"pagination\"X\n" +
+	"\x13ListServersResponse\x12+\n" +
+	"\aservers\x18\x01 \x03(\v2\x11.server.v1.ServerR\aservers\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"M\n" +
+	"\x14ImportServersRequest\x12\x1b\n" +
+	"\tfile_name\x18\x01 \x01(\tR\bfileName\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\fR\acontent\"\xa5\x01\n" +
+	"\x15ImportServersResponse\x12#\n" +
+	"\rsuccess_count\x18\x01 \x01(\x05R\fsuccessCount\x12\x1d\n" +
+	"\n" +
+	"server_ids\x18\x02 \x03(\tR\tserverIds\x12!\n" +
+	"\ffailed_count\x18\x03 \x01(\x05R\vfailedCount\x12%\n" +
+	"\x0efailed_servers\x18\x04 \x03(\tR\rfailedServers\"E\n" +
+	"\x12WatchStatusRequest\x12/\n" +
+	"\x06filter\x18\x01 \x01(\v2\x17.server.v1.ServerFilterR\x06filter\"\xc7\x01\n" +
+	"\vStatusEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12)\n" +
+	"\x06server\x18\x02 \x01(\v2\x11.server.v1.ServerR\x06server\x12@\n" +
+	"\x0fprevious_status\x18\x03 \x01(\x0e2\x17.server.v1.ServerStatusR\x0epreviousStatus\x12;\n" +
+	"\voccurred_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt*}\n" +
+	"\fServerStatus\x12\x1d\n" +
+	"\x19SERVER_STATUS_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15SERVER_STATUS_UNKNOWN\x10\x01\x12\x18\n" +
+	"\x14SERVER_STATUS_ONLINE\x10\x02\x12\x19\n" +
+	"\x15SERVER_STATUS_OFFLINE\x10\x032\x95\x04\n" +
+	"\rServerService\x12A\n" +
+	"\fCreateServer\x12\x1e.server.v1.CreateServerRequest\x1a\x11.server.v1.Server\x12A\n" +
+	"\fUpdateServer\x12\x1e.server.v1.UpdateServerRequest\x1a\x11.server.v1.Server\x12O\n" +
+	"\fDeleteServer\x12\x1e.server.v1.DeleteServerRequest\x1a\x1f.server.v1.DeleteServerResponse\x12C\n" +
+	"\rRestoreServer\x12\x1f.server.v1.RestoreServerRequest\x1a\x11.server.v1.Server\x12L\n" +
+	"\vListServers\x12\x1d.server.v1.ListServersRequest\x1a\x1e.server.v1.ListServersResponse\x12R\n" +
+	"\rImportServers\x12\x1f.server.v1.ImportServersRequest\x1a .server.v1.ImportServersResponse\x12F\n" +
+	"\vWatchStatus\x12\x1d.server.v1.WatchStatusRequest\x1a\x16.server.v1.StatusEvent0\x01BIZGgithub.com/th1enq/ViettelSMS_ServerService/api/proto/server/v1;serverv1b\x06proto3"
+
+var (
+	file_server_v1_server_proto_rawDescOnce sync.Once
+	file_server_v1_server_proto_rawDescData []byte
+)
+
+func file_server_v1_server_proto_rawDescGZIP() []byte {
+	file_server_v1_server_proto_rawDescOnce.Do(func() {
+		file_server_v1_server_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_server_v1_server_proto_rawDesc), len(file_server_v1_server_proto_rawDesc)))
+	})
+	return file_server_v1_server_proto_rawDescData
+}
+
+var file_server_v1_server_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_server_v1_server_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_server_v1_server_proto_goTypes = []any{
+	(ServerStatus)(0),             // 0: server.v1.ServerStatus
+	(*Server)(nil),                // 1: server.v1.Server
+	(*ServerFilter)(nil),          // 2: server.v1.ServerFilter
+	(*Pagination)(nil),            // 3: server.v1.Pagination
+	(*CreateServerRequest)(nil),   // 4: server.v1.CreateServerRequest
+	(*UpdateServerRequest)(nil),   // 5: server.v1.UpdateServerRequest
+	(*DeleteServerRequest)(nil),   // 6: server.v1.DeleteServerRequest
+	(*DeleteServerResponse)(nil),  // 7: server.v1.DeleteServerResponse
+	(*RestoreServerRequest)(nil),  // 8: server.v1.RestoreServerRequest
+	(*ListServersRequest)(nil),    // 9: server.v1.ListServersRequest
+	(*ListServersResponse)(nil),   // 10: server.v1.ListServersResponse
+	(*ImportServersRequest)(nil),  // 11: server.v1.ImportServersRequest
+	(*ImportServersResponse)(nil), // 12: server.v1.ImportServersResponse
+	(*WatchStatusRequest)(nil),    // 13: server.v1.WatchStatusRequest
+	(*StatusEvent)(nil),           // 14: server.v1.StatusEvent
+	nil,                           // 15: server.v1.Server.LabelsEntry
+	nil,                           // 16: server.v1.CreateServerRequest.LabelsEntry
+	nil,                           // 17: server.v1.UpdateServerRequest.LabelsEntry
+	(*timestamppb.Timestamp)(nil), // 18: google.protobuf.Timestamp
+}
+var file_server_v1_server_proto_depIdxs = []int32{
+	0,  // 0: server.v1.Server.status:type_name -> server.v1.ServerStatus
+	15, // 1: server.v1.Server.labels:type_name -> server.v1.Server.LabelsEntry
+	18, // 2: server.v1.Server.deleted_at:type_name -> google.protobuf.Timestamp
+	0,  // 3: server.v1.ServerFilter.status:type_name -> server.v1.ServerStatus
+	16, // 4: server.v1.CreateServerRequest.labels:type_name -> server.v1.CreateServerRequest.LabelsEntry
+	17, // 5: server.v1.UpdateServerRequest.labels:type_name -> server.v1.UpdateServerRequest.LabelsEntry
+	2,  // 6: server.v1.ListServersRequest.filter:type_name -> server.v1.ServerFilter
+	3,  // 7: server.v1.ListServersRequest.pagination:type_name -> server.v1.Pagination
+	1,  // 8: server.v1.ListServersResponse.servers:type_name -> server.v1.Server
+	2,  // 9: server.v1.WatchStatusRequest.filter:type_name -> server.v1.ServerFilter
+	1,  // 10: server.v1.StatusEvent.server:type_name -> server.v1.Server
+	0,  // 11: server.v1.StatusEvent.previous_status:type_name -> server.v1.ServerStatus
+	18, // 12: server.v1.StatusEvent.occurred_at:type_name -> google.protobuf.Timestamp
+	4,  // 13: server.v1.ServerService.CreateServer:input_type -> server.v1.CreateServerRequest
+	5,  // 14: server.v1.ServerService.UpdateServer:input_type -> server.v1.UpdateServerRequest
+	6,  // 15: server.v1.ServerService.DeleteServer:input_type -> server.v1.DeleteServerRequest
+	8,  // 16: server.v1.ServerService.RestoreServer:input_type -> server.v1.RestoreServerRequest
+	9,  // 17: server.v1.ServerService.ListServers:input_type -> server.v1.ListServersRequest
+	11, // 18: server.v1.ServerService.ImportServers:input_type -> server.v1.ImportServersRequest
+	13, // 19: server.v1.ServerService.WatchStatus:input_type -> server.v1.WatchStatusRequest
+	1,  // 20: server.v1.ServerService.CreateServer:output_type -> server.v1.Server
+	1,  // 21: server.v1.ServerService.UpdateServer:output_type -> server.v1.Server
+	7,  // 22: server.v1.ServerService.DeleteServer:output_type -> server.v1.DeleteServerResponse
+	1,  // 23: server.v1.ServerService.RestoreServer:output_type -> server.v1.Server
+	10, // 24: server.v1.ServerService.ListServers:output_type -> server.v1.ListServersResponse
+	12, // 25: server.v1.ServerService.ImportServers:output_type -> server.v1.ImportServersResponse
+	14, // 26: server.v1.ServerService.WatchStatus:output_type -> server.v1.StatusEvent
+	20, // [20:27] is the sub-list for method output_type
+	13, // [13:20] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
+}
+
+func init() { file_server_v1_server_proto_init() }
+func file_server_v1_server_proto_init() {
+	if File_server_v1_server_proto != nil {
+		return
+	}
+	file_server_v1_server_proto_msgTypes[1].OneofWrappers = []any{}
+	file_server_v1_server_proto_msgTypes[3].OneofWrappers = []any{}
+	file_server_v1_server_proto_msgTypes[4].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_server_v1_server_proto_rawDesc), len(file_server_v1_server_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_server_v1_server_proto_goTypes,
+		DependencyIndexes: file_server_v1_server_proto_depIdxs,
+		EnumInfos:         file_server_v1_server_proto_enumTypes,
+		MessageInfos:      file_server_v1_server_proto_msgTypes,
+	}.Build()
+	File_server_v1_server_proto = out.File
+	file_server_v1_server_proto_goTypes = nil
+	file_server_v1_server_proto_depIdxs = nil
+}
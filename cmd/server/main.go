@@ -19,6 +19,8 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"os"
 
 	_ "github.com/th1enq/ViettelSMS_ServerService/docs"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/application"
@@ -27,7 +29,11 @@ import (
 func main() {
 	app, err := application.InitApp()
 	if err != nil {
-		panic("failed to initialize server: " + err.Error())
+		fmt.Fprintln(os.Stderr, "failed to initialize server:", err)
+		os.Exit(1)
+	}
+	if err := app.Start(context.Background()); err != nil {
+		fmt.Fprintln(os.Stderr, "server stopped with error:", err)
+		os.Exit(1)
 	}
-	app.Start(context.Background())
 }
@@ -2,50 +2,286 @@ package application
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os/signal"
+	"sync"
 	"syscall"
 
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/consumer"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/grpc"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/http"
-	"github.com/th1enq/ViettelSMS_ServerService/internal/utils"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/job"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/kafka/producer"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/postgres"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/tracing"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/alert"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/notification"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/server"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/stream"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/webhook"
 	"go.uber.org/zap"
 )
 
 type Application struct {
+	config       *config.Config
 	httpServer   http.Server
+	grpcServer   grpc.Server
 	rootConsumer consumer.Root
+	retentionJob job.RetentionJob
+	probeJob     job.ProbeJob
+	schedulerJob job.SchedulerJob
+	alertJob     job.AlertJob
+	reportJob    job.ReportJob
+	usecase      server.UseCase
+	alerts       alert.UseCase
+	webhooks     webhook.UseCase
+	notifier     notification.UseCase
+	stream       stream.UseCase
+	broker       producer.MessageBroker
+	db           postgres.DBEngine
+	tracer       tracing.Provider
 	logger       *zap.Logger
+
+	// jobs tracks background jobs so shutdown waits for them before closing the DB.
+	jobs sync.WaitGroup
 }
 
 func NewApplication(
+	config *config.Config,
 	httpServer http.Server,
+	grpcServer grpc.Server,
 	rootConsumer consumer.Root,
+	retentionJob job.RetentionJob,
+	probeJob job.ProbeJob,
+	schedulerJob job.SchedulerJob,
+	alertJob job.AlertJob,
+	reportJob job.ReportJob,
+	usecase server.UseCase,
+	alerts alert.UseCase,
+	webhooks webhook.UseCase,
+	notifier notification.UseCase,
+	stream stream.UseCase,
+	broker producer.MessageBroker,
+	db postgres.DBEngine,
+	tracer tracing.Provider,
 	logger *zap.Logger,
 ) *Application {
 	return &Application{
+		config:       config,
 		httpServer:   httpServer,
+		grpcServer:   grpcServer,
 		rootConsumer: rootConsumer,
+		retentionJob: retentionJob,
+		probeJob:     probeJob,
+		schedulerJob: schedulerJob,
+		alertJob:     alertJob,
+		reportJob:    reportJob,
+		usecase:      usecase,
+		alerts:       alerts,
+		webhooks:     webhooks,
+		notifier:     notifier,
+		stream:       stream,
+		broker:       broker,
+		db:           db,
+		tracer:       tracer,
 		logger:       logger,
 	}
 }
 
+// Start runs every component until SIGINT/SIGTERM, ctx cancellation or a
+// component failure, then shuts down. A non-nil error means the process
+// should exit with a failure code.
 func (app *Application) Start(ctx context.Context) error {
 	app.logger.Info("Starting application ...")
 
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	failed := make(chan error, 1)
+
 	app.logger.Info("Starting HTTP Server ...")
 	go func() {
 		if err := app.httpServer.Start(ctx); err != nil {
 			app.logger.Error("HTTP Server failed to start", zap.Error(err))
+			select {
+			case failed <- err:
+			default:
+			}
+		}
+	}()
+
+	app.logger.Info("Starting gRPC Server ...")
+	go func() {
+		if err := app.grpcServer.Start(ctx); err != nil {
+			app.logger.Error("gRPC Server failed to start", zap.Error(err))
+			select {
+			case failed <- err:
+			default:
+			}
 		}
 	}()
 
+	// The consumer is not tied to the signal: shutdown stops it explicitly once
+	// HTTP has drained.
 	app.logger.Info("Starting Kafka Consumer ...")
+	if err := app.rootConsumer.Start(context.WithoutCancel(ctx)); err != nil {
+		app.logger.Error("Kafka Consumer failed to start", zap.Error(err))
+		return errors.Join(err, app.shutdown())
+	}
+
+	jobCtx, cancelJobs := context.WithCancel(ctx)
+	defer cancelJobs()
+
+	app.logger.Info("Starting Retention Job ...")
+	app.jobs.Add(1)
+	go func() {
+		defer app.jobs.Done()
+		if err := app.retentionJob.Start(jobCtx); err != nil {
+			app.logger.Error("Retention Job failed to start", zap.Error(err))
+		}
+	}()
+
+	app.logger.Info("Starting Probe Job ...")
+	app.jobs.Add(1)
+	go func() {
+		defer app.jobs.Done()
+		if err := app.probeJob.Start(jobCtx); err != nil {
+			app.logger.Error("Probe Job failed to start", zap.Error(err))
+		}
+	}()
+
+	app.logger.Info("Starting Scheduler Job ...")
+	app.jobs.Add(1)
+	go func() {
+		defer app.jobs.Done()
+		if err := app.schedulerJob.Start(jobCtx); err != nil {
+			app.logger.Error("Scheduler Job failed to start", zap.Error(err))
+		}
+	}()
+
+	app.logger.Info("Starting Alert Job ...")
+	app.jobs.Add(1)
+	go func() {
+		defer app.jobs.Done()
+		if err := app.alertJob.Start(jobCtx); err != nil {
+			app.logger.Error("Alert Job failed to start", zap.Error(err))
+		}
+	}()
+
+	app.logger.Info("Starting Report Job ...")
+	app.jobs.Add(1)
 	go func() {
-		if err := app.rootConsumer.Start(ctx); err != nil {
-			app.logger.Error("Kafka Consumer failed to start", zap.Error(err))
+		defer app.jobs.Done()
+		if err := app.reportJob.Start(jobCtx); err != nil {
+			app.logger.Error("Report Job failed to start", zap.Error(err))
 		}
 	}()
 
-	utils.BlockUntilSignal(syscall.SIGINT, syscall.SIGTERM)
+	var startErr error
+	select {
+	case <-ctx.Done():
+		app.logger.Info("Shutdown signal received")
+	case startErr = <-failed:
+	}
+
+	cancelJobs()
+	return errors.Join(startErr, app.shutdown())
+}
+
+// shutdown stops the components in dependency order within the configured
+// timeout: event streams, HTTP and gRPC first so no new work arrives, then the
+// consumer (committing its offsets), running imports and jobs, alert
+// evaluations, webhook deliveries, notifications, stream events, the
+// producer, and finally the tracer and the database.
+func (app *Application) shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), app.config.Server.ShutdownTimeout)
+	defer cancel()
+
+	app.logger.Info("Shutting down application ...", zap.Duration("timeout", app.config.Server.ShutdownTimeout))
+	var errs []error
+
+	// Streams never finish on their own and would hold HTTP and gRPC
+	// shutdown open.
+	app.stream.Close()
 
-	return nil
+	if err := app.httpServer.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("http server: %w", err))
+	}
+
+	if err := app.grpcServer.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("grpc server: %w", err))
+	}
+
+	if err := app.rootConsumer.Stop(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("kafka consumer: %w", err))
+	}
+
+	app.logger.Info("Waiting for running imports ...")
+	if err := app.usecase.Drain(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("imports: %w", err))
+	}
+
+	// Status changes stop once the consumer and the probe job are done; the
+	// deliveries they queued are flushed before the database closes.
+	if err := waitGroup(ctx, &app.jobs); err != nil {
+		errs = append(errs, fmt.Errorf("background jobs: %w", err))
+	}
+
+	app.logger.Info("Waiting for alert evaluations ...")
+	if err := app.alerts.Drain(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("alerts: %w", err))
+	}
+
+	app.logger.Info("Waiting for webhook deliveries ...")
+	if err := app.webhooks.Drain(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("webhooks: %w", err))
+	}
+
+	app.logger.Info("Waiting for notifications ...")
+	if err := app.notifier.Drain(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("notifications: %w", err))
+	}
+
+	if err := app.stream.Drain(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("stream events: %w", err))
+	}
+
+	if err := app.broker.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("kafka producer: %w", err))
+	}
+
+	// Flush spans still buffered by the batch exporter.
+	if err := app.tracer.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("tracer: %w", err))
+	}
+
+	if err := app.db.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("database: %w", err))
+	}
+
+	err := errors.Join(errs...)
+	if err != nil {
+		app.logger.Error("Application shutdown incomplete", zap.Error(err))
+	} else {
+		app.logger.Info("Application stopped")
+	}
+	_ = app.logger.Sync()
+	return err
+}
+
+func waitGroup(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
@@ -1,67 +1,243 @@
 package application
 
 import (
+	"context"
+	"fmt"
+
+	"github.com/robfig/cron/v3"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/auth"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/consumer"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/grpc"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/http"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/http/controller"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/http/middleware"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/http/presenter"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/job"
+	srv "github.com/th1enq/ViettelSMS_ServerService/internal/domain/service"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/certificate"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/health"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/kafka"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/kafka/broadcast"
 	consumerGroup "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/kafka/consumer"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/kafka/membership"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/kafka/producer"
 	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/metrics"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/postgres"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/prober"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/repository"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/service"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/tracing"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/alert"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/idempotency"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/notification"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/probe"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/report"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/server"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/stats"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/stream"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/webhook"
 )
 
 func InitApp() (*Application, error) {
 	config := config.LoadConfig()
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := auth.ValidateClients(config.TLS); err != nil {
+		return nil, fmt.Errorf("invalid client certificate mapping: %w", err)
+	}
 
 	logger, err := log.LoadLogger(config)
 	if err != nil {
 		return nil, err
 	}
 
-	db, err := postgres.NewPostgresDB(config, logger)
+	tracerProvider, err := tracing.NewProvider(config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := metrics.NewMetrics()
+
+	db, err := postgres.NewPostgresDB(config, logger, metrics)
 	if err != nil {
 		return nil, err
 	}
 
 	excelSrv := service.NewExcelizeService(logger)
 
+	broker, err := producer.NewBroker(config, logger)
+	if err != nil {
+		return nil, err
+	}
+	streamUseCase := stream.NewStreamUseCase(broker, config, logger)
+
 	repo := repository.NewServerRepository(db)
+	metrics.RegisterServerCounts(repo, config.Metrics.TenantLabel)
+
+	webhookSender, err := service.NewWebhookSender(config.Webhook)
+	if err != nil {
+		return nil, err
+	}
+	webhookRepo := repository.NewWebhookRepository(db)
+	webhookUseCase := webhook.NewWebhookUseCase(
+		webhookRepo,
+		webhookSender,
+		config,
+		logger,
+	)
+
+	alertRepo := repository.NewAlertRepository(db)
+	alertUseCase := alert.NewAlertUseCase(alertRepo, repo, config, logger)
+
+	channels := make(map[string]srv.NotificationChannel)
+	var mailer srv.AttachmentChannel
+	if config.Notification.SMTP.Host != "" {
+		mailer = service.NewSMTPChannel(config.Notification.SMTP)
+		channels[notification.CHANNEL_EMAIL] = mailer
+	}
+	if config.Notification.SMS.URL != "" {
+		channels[notification.CHANNEL_SMS] = service.NewSMSGatewayChannel(config.Notification.SMS)
+	}
+	notificationUseCase, err := notification.NewNotificationUseCase(config, channels, alertRepo, logger)
+	if err != nil {
+		return nil, err
+	}
 
-	usecase := server.NewServerUseCase(
+	usecase := server.NewTracedUseCase(server.NewServerUseCase(
 		repo,
 		excelSrv,
+		srv.StatusNotifiers{alertUseCase, webhookUseCase, notificationUseCase},
+		streamUseCase,
+		metrics,
 		logger,
-	)
+	))
+
+	idempotencyUseCase := idempotency.NewIdempotencyUseCase(repository.NewIdempotencyRepository(db), config, logger)
 
 	presenter := presenter.NewPresenter()
-	middleware := middleware.NewJWTMiddleware(presenter, []byte(config.JWT.Secret))
-	controller := controller.NewController(usecase, logger, presenter)
+	middleware := middleware.NewJWTMiddleware(config.JWT, config.APIKey, config.TLS)
 
-	httpServer := http.NewHttpServer(config, controller, middleware, logger)
+	var certificates certificate.Reloader
+	if config.TLS.Enabled {
+		certificates, err = certificate.NewReloader(config.TLS, logger)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLS configuration: %w", err)
+		}
+	}
 
 	statusConsumer, err := consumerGroup.NewConsumer(
 		config,
 		logger,
+		metrics,
 		config.Consumer.StatusConsumer,
 	)
 
-	statusHandleFunc := consumer.NewStatusHandlerFunc(logger, usecase)
+	statusHandleFunc := consumer.NewStatusHandlerFunc(config, logger, usecase, metrics)
 
 	if err != nil {
 		return nil, err
 	}
 
+	streamListener, err := broadcast.NewListener(config, config.Stream.Topic, logger)
+	if err != nil {
+		return nil, err
+	}
+
 	rootConsumer := consumer.NewRoot(
 		logger,
 		statusConsumer,
 		statusHandleFunc,
+		streamListener,
+		consumer.NewStreamHandlerFunc(logger, streamUseCase),
+	)
+
+	healthService := health.NewService(config.Server.ReadinessTimeout, map[string]health.Checker{
+		"postgres": health.CheckerFunc(db.Ping),
+		"kafka":    kafka.NewBrokerChecker(config),
+		"consumer": health.CheckerFunc(func(context.Context) error { return rootConsumer.Healthy() }),
+	})
+	healthController := controller.NewHealthController(healthService, logger)
+	webhookController := controller.NewWebhookController(webhookUseCase, logger, presenter)
+	alertController := controller.NewAlertController(alertUseCase, logger, presenter)
+	statsController := controller.NewStatsController(stats.NewStatsUseCase(repo, config, logger), logger, presenter)
+	reportUseCase := report.NewReportUseCase(
+		repo,
+		alertRepo,
+		repository.NewReportRepository(db),
+		mailer,
+		config,
+		logger,
 	)
+	reportController := controller.NewReportController(reportUseCase, logger, presenter)
+	streamController := controller.NewStreamController(streamUseCase, config.Stream.Heartbeat, logger, presenter)
+	controller := controller.NewController(usecase, logger, presenter)
 
-	app := NewApplication(httpServer, rootConsumer, logger)
+	rateLimiter := service.NewMemoryRateLimiter()
+	httpServer := http.NewHttpServer(config, controller, healthController, webhookController, alertController, streamController, statsController, reportController, middleware, presenter, idempotencyUseCase, rateLimiter, certificates, metrics, logger)
+	grpcServer := grpc.NewGrpcServer(
+		config,
+		grpc.NewServerHandler(usecase, streamUseCase, logger),
+		auth.NewAuthenticator(config.JWT, config.APIKey, config.TLS),
+		rateLimiter,
+		idempotencyUseCase,
+		certificates,
+		logger,
+	)
+
+	retentionJob := job.NewRetentionJob(config, usecase, idempotencyUseCase, logger)
+	alertJob := job.NewAlertJob(config, alertUseCase, logger)
+
+	var reportSchedule cron.Schedule
+	if config.Report.Enabled {
+		reportSchedule, err = job.ParseReportSchedule(config.Report.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid report schedule %q: %w", config.Report.Schedule, err)
+		}
+	}
+	reportJob := job.NewReportJob(config, reportUseCase, reportSchedule, logger)
+
+	var serverProber srv.Prober
+	if config.Prober.Enabled {
+		serverProber, err = prober.New(config.Prober)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var shardMembership membership.Membership
+	if config.Scheduler.Enabled {
+		shardMembership, err = membership.NewMembership(config, logger)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	probeUseCase := probe.NewProbeUseCase(repo, serverProber, usecase, broker, config.Scheduler.Topic, metrics, logger)
+	probeJob := job.NewProbeJob(config, probeUseCase, logger)
+	schedulerJob := job.NewSchedulerJob(config, probeUseCase, shardMembership, logger)
+
+	app := NewApplication(
+		config,
+		httpServer,
+		grpcServer,
+		rootConsumer,
+		retentionJob,
+		probeJob,
+		schedulerJob,
+		alertJob,
+		reportJob,
+		usecase,
+		alertUseCase,
+		webhookUseCase,
+		notificationUseCase,
+		streamUseCase,
+		broker,
+		db,
+		tracerProvider,
+		logger,
+	)
 	return app, nil
 }
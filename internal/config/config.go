@@ -1,6 +1,12 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/spf13/viper"
 )
 
@@ -8,6 +14,56 @@ type (
 	Server struct {
 		Host string
 		Port int
+		// ReadinessTimeout bounds all dependency checks of /health/ready.
+		ReadinessTimeout time.Duration
+		// ShutdownTimeout bounds the whole graceful shutdown.
+		ShutdownTimeout time.Duration
+	}
+
+	GRPC struct {
+		Enabled bool
+		Host    string
+		Port    int
+		// Reflection lets tools such as grpcurl discover the services. It
+		// needs no credentials, so it stays off outside development.
+		Reflection bool
+		// MaxMessageSize bounds a received message, which includes imported
+		// workbooks.
+		MaxMessageSize int
+	}
+
+	CORS struct {
+		// AllowOrigins lists the browser origins allowed to call the API; "*"
+		// allows any origin and cannot be combined with AllowCredentials.
+		AllowOrigins     []string
+		AllowMethods     []string
+		AllowHeaders     []string
+		ExposeHeaders    []string
+		AllowCredentials bool
+		// MaxAge is how long browsers may cache a preflight response.
+		MaxAge time.Duration
+	}
+
+	TLS struct {
+		Enabled  bool
+		CertFile string
+		KeyFile  string
+		// ReloadInterval is how often the certificate, key and client CA
+		// files are checked for changes.
+		ReloadInterval time.Duration
+		// ClientCAFile enables client certificates signed by these CAs.
+		ClientCAFile string
+		// RequireClientCert rejects connections without a valid client
+		// certificate; otherwise one is only verified when presented.
+		RequireClientCert bool
+		// ClientScopes maps the common name of a client certificate to the
+		// scopes it is granted, and ClientTenants to the tenant it acts for;
+		// a certificate with scopes but no tenant is rejected.
+		// ClientConstraints limits it to servers matching "key=value" entries,
+		// as the constraints claim of a token does.
+		ClientScopes      map[string][]string
+		ClientTenants     map[string]string
+		ClientConstraints map[string][]string
 	}
 
 	Postgres struct {
@@ -35,20 +91,233 @@ type (
 
 	JWT struct {
 		Secret string
+		// RequireTenant rejects tokens that carry no tenant_id claim instead
+		// of acting for the default tenant. Enable it once every issuer sets
+		// the claim, so a forgotten claim cannot read the default tenant.
+		RequireTenant bool
+	}
+
+	APIKey struct {
+		// Tenants maps each accepted API key to the tenant it acts for.
+		Tenants map[string]string
+		Scopes  []string
 	}
 
 	Consumer struct {
 		StatusConsumer string
+		// AllowMissingTenant applies status messages without a tenant header
+		// to the default tenant instead of dropping them. Only single-tenant
+		// deployments with agents predating the header should enable it.
+		AllowMissingTenant bool
+	}
+
+	Retention struct {
+		DeletedServerDays int
+		PurgeInterval     time.Duration
+	}
+
+	Prober struct {
+		Enabled bool
+		// Type is one of "tcp", "http" or "icmp".
+		Type     string
+		TCPPort  int
+		HTTPPort int
+		// HTTPScheme and HTTPPath build the probed URL from the server IPv4.
+		HTTPScheme string
+		HTTPPath   string
+		// ICMPPrivileged uses raw sockets instead of unprivileged datagram pings.
+		ICMPPrivileged bool
+		Timeout        time.Duration
+		Workers        int
+		// RefreshInterval is how often the list of servers to probe is reloaded.
+		RefreshInterval time.Duration
+	}
+
+	Scheduler struct {
+		// Enabled hands probing to agents; it excludes Prober.Enabled.
+		Enabled bool
+		// Topic receives the health_check_request messages for probe agents.
+		Topic string
+		// ShardTopic is only used for partition assignment: each replica owns
+		// the servers hashing to its partitions. It needs at least as many
+		// partitions as there are replicas.
+		ShardTopic      string
+		GroupID         string
+		RefreshInterval time.Duration
+	}
+
+	Webhook struct {
+		Workers int
+		// Timeout bounds a single delivery attempt.
+		Timeout        time.Duration
+		MaxAttempts    int
+		InitialBackoff time.Duration
+		MaxBackoff     time.Duration
+		// DisableAfter consecutive failed deliveries disable the webhook.
+		DisableAfter int
+		// AllowedNetworks are CIDRs webhooks may reach although they are
+		// loopback, private or link-local, which are refused by default.
+		AllowedNetworks []string
+	}
+
+	Alert struct {
+		// OfflineAfter raises an alert for a server offline this long.
+		OfflineAfter time.Duration
+		// GroupOfflinePercent raises an alert when more than this share of a
+		// group is offline.
+		GroupOfflinePercent float64
+		// FlapThreshold raises an alert for a server changing status more than
+		// this many times within FlapWindow.
+		FlapThreshold int
+		FlapWindow    time.Duration
+		// EvaluationInterval is how often time-based rules are evaluated and
+		// firing alerts re-checked. A zero threshold or interval disables a rule.
+		EvaluationInterval time.Duration
+		// Workers evaluate status changes off the caller's goroutine.
+		Workers int
+	}
+
+	Notification struct {
+		// Routes is a JSON array of routes; each sends the transitions of the
+		// servers it matches by tenant, labels and location to a channel. A
+		// route without tenants only matches the default tenant.
+		Routes string
+		// DedupeWindow suppresses the same notification to the same route
+		// within this period.
+		DedupeWindow time.Duration
+		Workers      int
+		SMTP         SMTP
+		SMS          SMS
+		// Templates use text/template over the status change event.
+		EmailSubjectTemplate string
+		EmailBodyTemplate    string
+		SMSTemplate          string
+	}
+
+	SMTP struct {
+		Host     string
+		Port     int
+		Username string
+		Password string
+		From     string
+	}
+
+	SMS struct {
+		// URL of the gateway endpoint receiving {"to": [...], "message": "..."}.
+		URL     string
+		Token   string
+		Timeout time.Duration
+	}
+
+	Stream struct {
+		// Topic carries server events between replicas; every replica reads
+		// all of it and forwards to its own subscribers.
+		Topic string
+		// Heartbeat is how often idle streams get a keep-alive.
+		Heartbeat time.Duration
+		// BufferSize is the number of events a subscriber may fall behind by
+		// before it is disconnected.
+		BufferSize int
+	}
+
+	Stats struct {
+		// CacheTTL is how long a tenant's statistics are served from memory.
+		CacheTTL time.Duration
+		// CreatedDays is the number of days covered by created_per_day.
+		CreatedDays int
+		// FlappingWindow and TopFlapping select the servers with the most
+		// status changes in the window.
+		FlappingWindow time.Duration
+		TopFlapping    int
+	}
+
+	Report struct {
+		Enabled bool
+		// Schedule is a five-field cron expression, optionally prefixed with
+		// CRON_TZ=<zone>; the default runs every Monday at 07:00.
+		Schedule string
+		// Period is the window covered by the uptime sheet. Status
+		// transitions are only kept for a week, so longer periods under-report.
+		Period time.Duration
+		// RetryInterval is how long after a failed run its failed reports are
+		// generated again, as long as the next scheduled run is not due first.
+		// Zero disables retries.
+		RetryInterval time.Duration
+		// Recipients maps each tenant to the addresses its reports are emailed
+		// to when SMTP is configured.
+		Recipients map[string][]string
+	}
+
+	// Limit allows Requests per Period. The bucket also holds Requests tokens,
+	// so a client may spend a whole period's quota at once.
+	Limit struct {
+		Requests int
+		Period   time.Duration
+	}
+
+	RateLimit struct {
+		Enabled bool
+		// Default is shared by the routes without a limit of their own.
+		Default Limit
+		// Routes maps METHOD:/path, with the path as registered such as
+		// GET:/server/export, or GRPC:/full.Method such as
+		// GRPC:/server.v1.ServerService/ImportServers, to a limit with
+		// buckets of its own.
+		Routes map[string]Limit
+		// Invalid holds the malformed entries, which Validate reports.
+		Invalid []string
+	}
+
+	Idempotency struct {
+		// TTL is how long a key and its stored response are kept.
+		TTL time.Duration
+		// LockTimeout is how long a request holds its key before it is
+		// presumed dead and a retry may run in its place. It must exceed the
+		// longest request, such as a large import.
+		LockTimeout time.Duration
+	}
+
+	Tracing struct {
+		// Exporter is one of "otlp", "stdout" or "none".
+		Exporter     string
+		OTLPEndpoint string
+		OTLPInsecure bool
+		ServiceName  string
+		SampleRatio  float64
+	}
+
+	Metrics struct {
+		// TenantLabel splits the server counts by tenant. /metrics takes no
+		// credentials, so only enable it when the port is reachable by the
+		// monitoring system alone; it names every tenant.
+		TenantLabel bool
 	}
 )
 
 type Config struct {
-	Server   Server
-	Postgres Postgres
-	Logger   Logger
-	Kafka    Kafka
-	JWT      JWT
-	Consumer Consumer
+	Server       Server
+	GRPC         GRPC
+	CORS         CORS
+	TLS          TLS
+	Postgres     Postgres
+	Logger       Logger
+	Kafka        Kafka
+	JWT          JWT
+	APIKey       APIKey
+	Consumer     Consumer
+	Retention    Retention
+	Prober       Prober
+	Scheduler    Scheduler
+	Webhook      Webhook
+	Alert        Alert
+	Notification Notification
+	Stream       Stream
+	Stats        Stats
+	Report       Report
+	RateLimit    RateLimit
+	Idempotency  Idempotency
+	Tracing      Tracing
+	Metrics      Metrics
 }
 
 func LoadConfig() *Config {
@@ -58,10 +327,86 @@ func LoadConfig() *Config {
 	// server service env
 	viper.SetDefault("SERVER_HOST", "0.0.0.0")
 	viper.SetDefault("SERVER_PORT", 8080)
+	viper.SetDefault("SERVER_READINESS_TIMEOUT", 3*time.Second)
+	viper.SetDefault("SERVER_SHUTDOWN_TIMEOUT", 30*time.Second)
 
 	serverEnv := Server{
-		Host: viper.GetString("SERVER_HOST"),
-		Port: viper.GetInt("SERVER_PORT"),
+		Host:             viper.GetString("SERVER_HOST"),
+		Port:             viper.GetInt("SERVER_PORT"),
+		ReadinessTimeout: viper.GetDuration("SERVER_READINESS_TIMEOUT"),
+		ShutdownTimeout:  viper.GetDuration("SERVER_SHUTDOWN_TIMEOUT"),
+	}
+
+	// grpc env, served with the same TLS settings as HTTP
+	viper.SetDefault("GRPC_ENABLED", true)
+	viper.SetDefault("GRPC_HOST", "0.0.0.0")
+	viper.SetDefault("GRPC_PORT", 9090)
+	viper.SetDefault("GRPC_REFLECTION", false)
+	viper.SetDefault("GRPC_MAX_MESSAGE_SIZE", 16<<20)
+	grpcEnv := GRPC{
+		Enabled:        viper.GetBool("GRPC_ENABLED"),
+		Host:           viper.GetString("GRPC_HOST"),
+		Port:           viper.GetInt("GRPC_PORT"),
+		Reflection:     viper.GetBool("GRPC_REFLECTION"),
+		MaxMessageSize: viper.GetInt("GRPC_MAX_MESSAGE_SIZE"),
+	}
+
+	// cors env
+	viper.SetDefault("CORS_ALLOW_ORIGINS", []string{"*"})
+	viper.SetDefault("CORS_ALLOW_METHODS", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"})
+	viper.SetDefault("CORS_ALLOW_HEADERS", []string{
+		"Origin", "Content-Type", "Authorization", "X-API-Key", "If-Match",
+		"X-Request-ID", "Idempotency-Key", "traceparent", "tracestate",
+	})
+	viper.SetDefault("CORS_EXPOSE_HEADERS", []string{"X-Request-ID", "Idempotent-Replayed", "Retry-After"})
+	viper.SetDefault("CORS_ALLOW_CREDENTIALS", false)
+	viper.SetDefault("CORS_MAX_AGE", 12*time.Hour)
+	corsEnv := CORS{
+		AllowOrigins:     viper.GetStringSlice("CORS_ALLOW_ORIGINS"),
+		AllowMethods:     viper.GetStringSlice("CORS_ALLOW_METHODS"),
+		AllowHeaders:     viper.GetStringSlice("CORS_ALLOW_HEADERS"),
+		ExposeHeaders:    viper.GetStringSlice("CORS_EXPOSE_HEADERS"),
+		AllowCredentials: viper.GetBool("CORS_ALLOW_CREDENTIALS"),
+		MaxAge:           viper.GetDuration("CORS_MAX_AGE"),
+	}
+
+	// tls env, client scopes are formatted as cn=scope,scope, client tenants
+	// as cn=tenant and client constraints as cn=key=value,key=value
+	viper.SetDefault("TLS_ENABLED", false)
+	viper.SetDefault("TLS_CERT_FILE", "")
+	viper.SetDefault("TLS_KEY_FILE", "")
+	viper.SetDefault("TLS_RELOAD_INTERVAL", time.Minute)
+	viper.SetDefault("TLS_CLIENT_CA_FILE", "")
+	viper.SetDefault("TLS_REQUIRE_CLIENT_CERT", false)
+	viper.SetDefault("TLS_CLIENT_SCOPES", []string{})
+	viper.SetDefault("TLS_CLIENT_TENANTS", []string{})
+	viper.SetDefault("TLS_CLIENT_CONSTRAINTS", []string{})
+	tlsEnv := TLS{
+		Enabled:           viper.GetBool("TLS_ENABLED"),
+		CertFile:          viper.GetString("TLS_CERT_FILE"),
+		KeyFile:           viper.GetString("TLS_KEY_FILE"),
+		ReloadInterval:    viper.GetDuration("TLS_RELOAD_INTERVAL"),
+		ClientCAFile:      viper.GetString("TLS_CLIENT_CA_FILE"),
+		RequireClientCert: viper.GetBool("TLS_REQUIRE_CLIENT_CERT"),
+		ClientScopes:      make(map[string][]string),
+		ClientTenants:     make(map[string]string),
+		ClientConstraints: make(map[string][]string),
+	}
+	for _, entry := range viper.GetStringSlice("TLS_CLIENT_SCOPES") {
+		if cn, scopes, ok := strings.Cut(entry, "="); ok && cn != "" {
+			tlsEnv.ClientScopes[cn] = strings.FieldsFunc(scopes, func(r rune) bool { return r == ',' })
+		}
+	}
+	for _, entry := range viper.GetStringSlice("TLS_CLIENT_TENANTS") {
+		if cn, tenantID, ok := strings.Cut(entry, "="); ok && cn != "" && tenantID != "" {
+			tlsEnv.ClientTenants[cn] = tenantID
+		}
+	}
+	for _, entry := range viper.GetStringSlice("TLS_CLIENT_CONSTRAINTS") {
+		if cn, constraints, ok := strings.Cut(entry, "="); ok && cn != "" {
+			tlsEnv.ClientConstraints[cn] = append(tlsEnv.ClientConstraints[cn],
+				strings.FieldsFunc(constraints, func(r rune) bool { return r == ',' })...)
+		}
 	}
 
 	// postgres env
@@ -107,22 +452,332 @@ func LoadConfig() *Config {
 	}
 
 	viper.SetDefault("JWT_SECRET", "mysecret")
+	viper.SetDefault("JWT_REQUIRE_TENANT", false)
 	jwtEnv := JWT{
-		Secret: viper.GetString("JWT_SECRET"),
+		Secret:        viper.GetString("JWT_SECRET"),
+		RequireTenant: viper.GetBool("JWT_REQUIRE_TENANT"),
+	}
+
+	// api key env, entries are formatted as key=tenant
+	viper.SetDefault("API_KEYS", []string{})
+	viper.SetDefault("API_KEY_SCOPES", []string{})
+	apiKeyEnv := APIKey{
+		Tenants: make(map[string]string),
+		Scopes:  viper.GetStringSlice("API_KEY_SCOPES"),
+	}
+	for _, entry := range viper.GetStringSlice("API_KEYS") {
+		if key, tenantID, ok := strings.Cut(entry, "="); ok && key != "" {
+			apiKeyEnv.Tenants[key] = tenantID
+		}
 	}
 
 	// consumer env
 	viper.SetDefault("STATUS_CONSUMER_GROUP", "status-consumer-group")
+	viper.SetDefault("STATUS_ALLOW_MISSING_TENANT", false)
 	consumerEnv := Consumer{
-		StatusConsumer: viper.GetString("STATUS_CONSUMER_GROUP"),
+		StatusConsumer:     viper.GetString("STATUS_CONSUMER_GROUP"),
+		AllowMissingTenant: viper.GetBool("STATUS_ALLOW_MISSING_TENANT"),
+	}
+
+	// retention env, a non-positive number of days disables the purge job
+	viper.SetDefault("RETENTION_DELETED_SERVER_DAYS", 30)
+	viper.SetDefault("RETENTION_PURGE_INTERVAL", time.Hour)
+	retentionEnv := Retention{
+		DeletedServerDays: viper.GetInt("RETENTION_DELETED_SERVER_DAYS"),
+		PurgeInterval:     viper.GetDuration("RETENTION_PURGE_INTERVAL"),
+	}
+
+	// prober env, the built-in prober is off unless enabled
+	viper.SetDefault("PROBER_ENABLED", false)
+	viper.SetDefault("PROBER_TYPE", "tcp")
+	viper.SetDefault("PROBER_TCP_PORT", 22)
+	viper.SetDefault("PROBER_HTTP_PORT", 80)
+	viper.SetDefault("PROBER_HTTP_SCHEME", "http")
+	viper.SetDefault("PROBER_HTTP_PATH", "/")
+	viper.SetDefault("PROBER_ICMP_PRIVILEGED", false)
+	viper.SetDefault("PROBER_TIMEOUT", 3*time.Second)
+	viper.SetDefault("PROBER_WORKERS", 20)
+	viper.SetDefault("PROBER_REFRESH_INTERVAL", time.Minute)
+	proberEnv := Prober{
+		Enabled:         viper.GetBool("PROBER_ENABLED"),
+		Type:            strings.ToLower(viper.GetString("PROBER_TYPE")),
+		TCPPort:         viper.GetInt("PROBER_TCP_PORT"),
+		HTTPPort:        viper.GetInt("PROBER_HTTP_PORT"),
+		HTTPScheme:      viper.GetString("PROBER_HTTP_SCHEME"),
+		HTTPPath:        viper.GetString("PROBER_HTTP_PATH"),
+		ICMPPrivileged:  viper.GetBool("PROBER_ICMP_PRIVILEGED"),
+		Timeout:         viper.GetDuration("PROBER_TIMEOUT"),
+		Workers:         viper.GetInt("PROBER_WORKERS"),
+		RefreshInterval: viper.GetDuration("PROBER_REFRESH_INTERVAL"),
+	}
+
+	// scheduler env, publishes probe jobs to kafka instead of probing in-process,
+	// so it cannot be enabled together with the prober
+	viper.SetDefault("SCHEDULER_ENABLED", false)
+	viper.SetDefault("SCHEDULER_TOPIC", "health_check_request")
+	viper.SetDefault("SCHEDULER_SHARD_TOPIC", "health_check_scheduler")
+	viper.SetDefault("SCHEDULER_GROUP", "health-check-scheduler")
+	viper.SetDefault("SCHEDULER_REFRESH_INTERVAL", time.Minute)
+	schedulerEnv := Scheduler{
+		Enabled:         viper.GetBool("SCHEDULER_ENABLED"),
+		Topic:           viper.GetString("SCHEDULER_TOPIC"),
+		ShardTopic:      viper.GetString("SCHEDULER_SHARD_TOPIC"),
+		GroupID:         viper.GetString("SCHEDULER_GROUP"),
+		RefreshInterval: viper.GetDuration("SCHEDULER_REFRESH_INTERVAL"),
+	}
+
+	// webhook env, a delivery is retried with exponential backoff before it counts as failed
+	viper.SetDefault("WEBHOOK_WORKERS", 10)
+	viper.SetDefault("WEBHOOK_TIMEOUT", 10*time.Second)
+	viper.SetDefault("WEBHOOK_MAX_ATTEMPTS", 5)
+	viper.SetDefault("WEBHOOK_INITIAL_BACKOFF", time.Second)
+	viper.SetDefault("WEBHOOK_MAX_BACKOFF", time.Minute)
+	viper.SetDefault("WEBHOOK_DISABLE_AFTER", 10)
+	viper.SetDefault("WEBHOOK_ALLOWED_NETWORKS", []string{})
+	webhookEnv := Webhook{
+		Workers:         viper.GetInt("WEBHOOK_WORKERS"),
+		Timeout:         viper.GetDuration("WEBHOOK_TIMEOUT"),
+		MaxAttempts:     viper.GetInt("WEBHOOK_MAX_ATTEMPTS"),
+		InitialBackoff:  viper.GetDuration("WEBHOOK_INITIAL_BACKOFF"),
+		MaxBackoff:      viper.GetDuration("WEBHOOK_MAX_BACKOFF"),
+		DisableAfter:    viper.GetInt("WEBHOOK_DISABLE_AFTER"),
+		AllowedNetworks: viper.GetStringSlice("WEBHOOK_ALLOWED_NETWORKS"),
+	}
+
+	// alert env, a zero threshold disables the rule
+	viper.SetDefault("ALERT_OFFLINE_AFTER", 5*time.Minute)
+	viper.SetDefault("ALERT_GROUP_OFFLINE_PERCENT", 50.0)
+	viper.SetDefault("ALERT_FLAP_THRESHOLD", 5)
+	viper.SetDefault("ALERT_FLAP_WINDOW", time.Hour)
+	viper.SetDefault("ALERT_EVALUATION_INTERVAL", 30*time.Second)
+	viper.SetDefault("ALERT_WORKERS", 5)
+	alertEnv := Alert{
+		OfflineAfter:        viper.GetDuration("ALERT_OFFLINE_AFTER"),
+		GroupOfflinePercent: viper.GetFloat64("ALERT_GROUP_OFFLINE_PERCENT"),
+		FlapThreshold:       viper.GetInt("ALERT_FLAP_THRESHOLD"),
+		FlapWindow:          viper.GetDuration("ALERT_FLAP_WINDOW"),
+		EvaluationInterval:  viper.GetDuration("ALERT_EVALUATION_INTERVAL"),
+		Workers:             viper.GetInt("ALERT_WORKERS"),
+	}
+
+	// notification env, channels are only built when configured
+	viper.SetDefault("NOTIFY_ROUTES", "[]")
+	viper.SetDefault("NOTIFY_DEDUPE_WINDOW", 15*time.Minute)
+	viper.SetDefault("NOTIFY_WORKERS", 5)
+	viper.SetDefault("NOTIFY_SMTP_PORT", 25)
+	viper.SetDefault("NOTIFY_SMS_TIMEOUT", 10*time.Second)
+	viper.SetDefault("NOTIFY_EMAIL_SUBJECT_TEMPLATE", "[{{.Current}}] {{.ServerName}} ({{.ServerID}})")
+	viper.SetDefault("NOTIFY_EMAIL_BODY_TEMPLATE", "Server {{.ServerName}} ({{.ServerID}}, {{.IPv4}}) in {{.Location}} changed from {{.Previous}} to {{.Current}} at {{.ChangedAt.Format \"2006-01-02 15:04:05 MST\"}}.")
+	viper.SetDefault("NOTIFY_SMS_TEMPLATE", "{{.ServerName}} ({{.IPv4}}) {{.Previous}}->{{.Current}} {{.ChangedAt.Format \"15:04 02/01\"}}")
+	notificationEnv := Notification{
+		Routes:       viper.GetString("NOTIFY_ROUTES"),
+		DedupeWindow: viper.GetDuration("NOTIFY_DEDUPE_WINDOW"),
+		Workers:      viper.GetInt("NOTIFY_WORKERS"),
+		SMTP: SMTP{
+			Host:     viper.GetString("NOTIFY_SMTP_HOST"),
+			Port:     viper.GetInt("NOTIFY_SMTP_PORT"),
+			Username: viper.GetString("NOTIFY_SMTP_USERNAME"),
+			Password: viper.GetString("NOTIFY_SMTP_PASSWORD"),
+			From:     viper.GetString("NOTIFY_SMTP_FROM"),
+		},
+		SMS: SMS{
+			URL:     viper.GetString("NOTIFY_SMS_URL"),
+			Token:   viper.GetString("NOTIFY_SMS_TOKEN"),
+			Timeout: viper.GetDuration("NOTIFY_SMS_TIMEOUT"),
+		},
+		EmailSubjectTemplate: viper.GetString("NOTIFY_EMAIL_SUBJECT_TEMPLATE"),
+		EmailBodyTemplate:    viper.GetString("NOTIFY_EMAIL_BODY_TEMPLATE"),
+		SMSTemplate:          viper.GetString("NOTIFY_SMS_TEMPLATE"),
+	}
+
+	// stream env
+	viper.SetDefault("STREAM_TOPIC", "server_events")
+	viper.SetDefault("STREAM_HEARTBEAT", 15*time.Second)
+	viper.SetDefault("STREAM_BUFFER_SIZE", 64)
+	streamEnv := Stream{
+		Topic:      viper.GetString("STREAM_TOPIC"),
+		Heartbeat:  viper.GetDuration("STREAM_HEARTBEAT"),
+		BufferSize: viper.GetInt("STREAM_BUFFER_SIZE"),
+	}
+
+	// stats env
+	viper.SetDefault("STATS_CACHE_TTL", 30*time.Second)
+	viper.SetDefault("STATS_CREATED_DAYS", 30)
+	viper.SetDefault("STATS_FLAPPING_WINDOW", 24*time.Hour)
+	viper.SetDefault("STATS_TOP_FLAPPING", 10)
+	statsEnv := Stats{
+		CacheTTL:       viper.GetDuration("STATS_CACHE_TTL"),
+		CreatedDays:    viper.GetInt("STATS_CREATED_DAYS"),
+		FlappingWindow: viper.GetDuration("STATS_FLAPPING_WINDOW"),
+		TopFlapping:    viper.GetInt("STATS_TOP_FLAPPING"),
+	}
+
+	// report env, recipients are formatted as email or tenant=email; a bare
+	// address receives the reports of the default tenant
+	viper.SetDefault("REPORT_ENABLED", false)
+	viper.SetDefault("REPORT_SCHEDULE", "0 7 * * 1")
+	viper.SetDefault("REPORT_PERIOD", 7*24*time.Hour)
+	viper.SetDefault("REPORT_RETRY_INTERVAL", 10*time.Minute)
+	viper.SetDefault("REPORT_RECIPIENTS", []string{})
+	reportEnv := Report{
+		Enabled:       viper.GetBool("REPORT_ENABLED"),
+		Schedule:      viper.GetString("REPORT_SCHEDULE"),
+		Period:        viper.GetDuration("REPORT_PERIOD"),
+		RetryInterval: viper.GetDuration("REPORT_RETRY_INTERVAL"),
+		Recipients:    make(map[string][]string),
+	}
+	for _, entry := range viper.GetStringSlice("REPORT_RECIPIENTS") {
+		tenantID, address, ok := strings.Cut(entry, "=")
+		if !ok {
+			tenantID, address = "default", entry
+		}
+		if tenantID != "" && address != "" {
+			reportEnv.Recipients[tenantID] = append(reportEnv.Recipients[tenantID], address)
+		}
+	}
+
+	// rate limit env, limits are formatted as requests/period such as 10/m or
+	// 100/1h and routes as METHOD:/path=limit or GRPC:/full.Method=limit
+	viper.SetDefault("RATE_LIMIT_ENABLED", true)
+	viper.SetDefault("RATE_LIMIT_DEFAULT", "300/m")
+	viper.SetDefault("RATE_LIMIT_ROUTES", []string{"POST:/server/import=5/m", "GET:/server/export=10/m",
+		"GRPC:/server.v1.ServerService/ImportServers=5/m"})
+	rateLimitEnv := RateLimit{
+		Enabled: viper.GetBool("RATE_LIMIT_ENABLED"),
+		Routes:  make(map[string]Limit),
+	}
+	if limit, ok := parseLimit(viper.GetString("RATE_LIMIT_DEFAULT")); ok {
+		rateLimitEnv.Default = limit
+	} else {
+		rateLimitEnv.Invalid = append(rateLimitEnv.Invalid, fmt.Sprintf("RATE_LIMIT_DEFAULT %q", viper.GetString("RATE_LIMIT_DEFAULT")))
+	}
+	for _, entry := range viper.GetStringSlice("RATE_LIMIT_ROUTES") {
+		route, value, ok := strings.Cut(entry, "=")
+		limit, valid := parseLimit(value)
+		if !ok || route == "" || !valid {
+			rateLimitEnv.Invalid = append(rateLimitEnv.Invalid, fmt.Sprintf("RATE_LIMIT_ROUTES entry %q", entry))
+			continue
+		}
+		rateLimitEnv.Routes[route] = limit
+	}
+
+	// idempotency env
+	viper.SetDefault("IDEMPOTENCY_TTL", 24*time.Hour)
+	viper.SetDefault("IDEMPOTENCY_LOCK_TIMEOUT", 10*time.Minute)
+	idempotencyEnv := Idempotency{
+		TTL:         viper.GetDuration("IDEMPOTENCY_TTL"),
+		LockTimeout: viper.GetDuration("IDEMPOTENCY_LOCK_TIMEOUT"),
+	}
+
+	// tracing env, the otlp exporter sends to a collector over HTTP
+	viper.SetDefault("TRACING_EXPORTER", "none")
+	viper.SetDefault("TRACING_OTLP_ENDPOINT", "localhost:4318")
+	viper.SetDefault("TRACING_OTLP_INSECURE", true)
+	viper.SetDefault("TRACING_SERVICE_NAME", "server-service")
+	viper.SetDefault("TRACING_SAMPLE_RATIO", 1.0)
+	tracingEnv := Tracing{
+		Exporter:     strings.ToLower(viper.GetString("TRACING_EXPORTER")),
+		OTLPEndpoint: viper.GetString("TRACING_OTLP_ENDPOINT"),
+		OTLPInsecure: viper.GetBool("TRACING_OTLP_INSECURE"),
+		ServiceName:  viper.GetString("TRACING_SERVICE_NAME"),
+		SampleRatio:  viper.GetFloat64("TRACING_SAMPLE_RATIO"),
+	}
+
+	// metrics env
+	viper.SetDefault("METRICS_TENANT_LABEL", false)
+	metricsEnv := Metrics{
+		TenantLabel: viper.GetBool("METRICS_TENANT_LABEL"),
 	}
 
 	return &Config{
-		Server:   serverEnv,
-		Postgres: postgresEnv,
-		Logger:   loggerEnv,
-		Kafka:    kafkaEnv,
-		JWT:      jwtEnv,
-		Consumer: consumerEnv,
+		Server:       serverEnv,
+		GRPC:         grpcEnv,
+		CORS:         corsEnv,
+		TLS:          tlsEnv,
+		Postgres:     postgresEnv,
+		Logger:       loggerEnv,
+		Kafka:        kafkaEnv,
+		JWT:          jwtEnv,
+		APIKey:       apiKeyEnv,
+		Consumer:     consumerEnv,
+		Retention:    retentionEnv,
+		Prober:       proberEnv,
+		Scheduler:    schedulerEnv,
+		Webhook:      webhookEnv,
+		Alert:        alertEnv,
+		Notification: notificationEnv,
+		Stream:       streamEnv,
+		Stats:        statsEnv,
+		Report:       reportEnv,
+		RateLimit:    rateLimitEnv,
+		Idempotency:  idempotencyEnv,
+		Tracing:      tracingEnv,
+		Metrics:      metricsEnv,
+	}
+}
+
+// Validate reports settings the service cannot run with, such as intervals
+// that would panic a ticker. Optional features are only checked when enabled;
+// a zero interval keeps meaning "disabled" where it is documented to.
+func (c *Config) Validate() error {
+	var errs []error
+	positive := func(name string, value time.Duration) {
+		if value <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %s", name, value))
+		}
+	}
+	notNegative := func(name string, value time.Duration) {
+		if value < 0 {
+			errs = append(errs, fmt.Errorf("%s must not be negative, got %s", name, value))
+		}
+	}
+
+	notNegative("RETENTION_PURGE_INTERVAL", c.Retention.PurgeInterval)
+	notNegative("ALERT_EVALUATION_INTERVAL", c.Alert.EvaluationInterval)
+	notNegative("TLS_RELOAD_INTERVAL", c.TLS.ReloadInterval)
+	notNegative("REPORT_RETRY_INTERVAL", c.Report.RetryInterval)
+	positive("IDEMPOTENCY_LOCK_TIMEOUT", c.Idempotency.LockTimeout)
+	positive("STREAM_HEARTBEAT", c.Stream.Heartbeat)
+	if c.Prober.Enabled {
+		positive("PROBER_REFRESH_INTERVAL", c.Prober.RefreshInterval)
+		positive("PROBER_TIMEOUT", c.Prober.Timeout)
+		if c.Prober.Workers <= 0 {
+			errs = append(errs, fmt.Errorf("PROBER_WORKERS must be positive, got %d", c.Prober.Workers))
+		}
+	}
+	if c.Scheduler.Enabled {
+		positive("SCHEDULER_REFRESH_INTERVAL", c.Scheduler.RefreshInterval)
+	}
+	// A malformed default would leave it at zero, which is unlimited.
+	if c.RateLimit.Enabled {
+		for _, entry := range c.RateLimit.Invalid {
+			errs = append(errs, fmt.Errorf("%s is malformed, want requests/period such as 10/m", entry))
+		}
+	}
+	// Both would check every server, once here and once by an agent.
+	if c.Prober.Enabled && c.Scheduler.Enabled {
+		errs = append(errs, errors.New("PROBER_ENABLED and SCHEDULER_ENABLED are mutually exclusive"))
+	}
+	return errors.Join(errs...)
+}
+
+// parseLimit reads requests/period, where the period is a duration or a bare
+// unit: 10/m is the same as 10/1m.
+func parseLimit(value string) (Limit, bool) {
+	count, period, ok := strings.Cut(strings.TrimSpace(value), "/")
+	if !ok || period == "" {
+		return Limit{}, false
+	}
+	requests, err := strconv.Atoi(count)
+	if err != nil || requests <= 0 {
+		return Limit{}, false
+	}
+	if period[0] < '0' || period[0] > '9' {
+		period = "1" + period
+	}
+	duration, err := time.ParseDuration(period)
+	if err != nil || duration <= 0 {
+		return Limit{}, false
 	}
+	return Limit{Requests: requests, Period: duration}, true
 }
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	cases := []struct {
+		value string
+		want  Limit
+		ok    bool
+	}{
+		{"10/m", Limit{Requests: 10, Period: time.Minute}, true},
+		{"100/1h", Limit{Requests: 100, Period: time.Hour}, true},
+		{" 5/30s ", Limit{Requests: 5, Period: 30 * time.Second}, true},
+		{"10", Limit{}, false},
+		{"0/m", Limit{}, false},
+		{"10/", Limit{}, false},
+		{"10/fortnight", Limit{}, false},
+	}
+	for _, tc := range cases {
+		got, ok := parseLimit(tc.value)
+		if ok != tc.ok || got != tc.want {
+			t.Fatalf("parseLimit(%q) = %+v %v, want %+v %v", tc.value, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := func() *Config {
+		return &Config{
+			Stream:      Stream{Heartbeat: 15 * time.Second},
+			Prober:      Prober{Enabled: true, RefreshInterval: time.Minute, Timeout: time.Second, Workers: 1},
+			Scheduler:   Scheduler{RefreshInterval: time.Minute},
+			Idempotency: Idempotency{TTL: 24 * time.Hour, LockTimeout: 10 * time.Minute},
+		}
+	}
+
+	cases := []struct {
+		name   string
+		mutate func(c *Config)
+		ok     bool
+	}{
+		{"valid", func(c *Config) {}, true},
+		{"zero retention interval disables the job", func(c *Config) { c.Retention.PurgeInterval = 0 }, true},
+		{"negative retention interval", func(c *Config) { c.Retention.PurgeInterval = -time.Minute }, false},
+		{"zero prober refresh", func(c *Config) { c.Prober.RefreshInterval = 0 }, false},
+		{"disabled prober is not checked", func(c *Config) { c.Prober = Prober{} }, true},
+		{"scheduler instead of prober", func(c *Config) { c.Prober.Enabled = false; c.Scheduler.Enabled = true }, true},
+		{"zero scheduler refresh when enabled", func(c *Config) {
+			c.Prober.Enabled = false
+			c.Scheduler.Enabled = true
+			c.Scheduler.RefreshInterval = 0
+		}, false},
+		{"prober and scheduler together", func(c *Config) { c.Scheduler.Enabled = true }, false},
+		{"zero stream heartbeat", func(c *Config) { c.Stream.Heartbeat = 0 }, false},
+		{"zero idempotency lock", func(c *Config) { c.Idempotency.LockTimeout = 0 }, false},
+		{"malformed rate limit", func(c *Config) {
+			c.RateLimit = RateLimit{Enabled: true, Invalid: []string{`RATE_LIMIT_DEFAULT "300/mn"`}}
+		}, false},
+		{"malformed rate limit when disabled", func(c *Config) {
+			c.RateLimit = RateLimit{Invalid: []string{`RATE_LIMIT_DEFAULT "300/mn"`}}
+		}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := valid()
+			tc.mutate(cfg)
+			if err := cfg.Validate(); (err == nil) != tc.ok {
+				t.Fatalf("Validate() = %v, want ok=%v", err, tc.ok)
+			}
+		})
+	}
+}
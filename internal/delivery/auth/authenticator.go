@@ -0,0 +1,187 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/authz"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+)
+
+// Credentials are what a caller presented, whatever the transport.
+type Credentials struct {
+	APIKey string
+	// Token is the bearer token without its scheme.
+	Token string
+	// CommonName is the subject of the client certificate verified during
+	// the TLS handshake.
+	CommonName string
+}
+
+// Identity is the authenticated caller.
+type Identity struct {
+	// ClientID tells callers apart within a tenant: "user:<id>" for tokens,
+	// "key:<hash prefix>" for API keys and "cert:<common name>" for agents.
+	ClientID  string
+	UserID    uint
+	TenantID  string
+	Principal *authz.Principal
+}
+
+type Authenticator interface {
+	// Authenticate tries a known API key, then a client certificate mapped to
+	// scopes, then the bearer token. Failures are catalogued errors.
+	Authenticate(credentials Credentials) (*Identity, error)
+}
+
+type authenticator struct {
+	jwt     config.JWT
+	apiKeys config.APIKey
+	tls     config.TLS
+}
+
+func NewAuthenticator(
+	jwt config.JWT,
+	apiKeys config.APIKey,
+	tls config.TLS,
+) Authenticator {
+	return &authenticator{
+		jwt:     jwt,
+		apiKeys: apiKeys,
+		tls:     tls,
+	}
+}
+
+func (a *authenticator) Authenticate(credentials Credentials) (*Identity, error) {
+	if credentials.APIKey != "" {
+		if tenantID, ok := a.apiKeys.Tenants[credentials.APIKey]; ok {
+			// API keys are hashed so they never end up in logs or a shared store.
+			sum := sha256.Sum256([]byte(credentials.APIKey))
+			return &Identity{
+				ClientID:  "key:" + hex.EncodeToString(sum[:8]),
+				TenantID:  tenantID,
+				Principal: &authz.Principal{Scopes: a.apiKeys.Scopes},
+			}, nil
+		}
+	}
+
+	// Agents authenticate with a client certificate whose common name is
+	// mapped to scopes; the TLS handshake has already verified the chain.
+	if credentials.CommonName != "" {
+		if _, ok := a.tls.ClientScopes[credentials.CommonName]; ok {
+			principal, tenantID, err := clientPrincipal(a.tls, credentials.CommonName)
+			if err != nil {
+				return nil, domain.ErrUnauthorized.Wrap(err)
+			}
+			return &Identity{
+				ClientID:  "cert:" + credentials.CommonName,
+				TenantID:  tenantID,
+				Principal: principal,
+			}, nil
+		}
+	}
+
+	if credentials.Token == "" {
+		return nil, domain.ErrUnauthorized
+	}
+
+	claims, err := a.validateToken(credentials.Token)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, domain.ErrTokenExpired.Wrap(err)
+		}
+		return nil, domain.ErrInvalidToken.Wrap(err)
+	}
+
+	if claims.Blocked {
+		return nil, domain.ErrUserBlocked
+	}
+
+	// Without a tenant_id claim the caller acts for the default tenant, unless
+	// the deployment requires every token to name its tenant.
+	if claims.TenantID == "" && a.jwt.RequireTenant {
+		return nil, domain.ErrInvalidToken.Wrap(errors.New("token has no tenant_id claim"))
+	}
+
+	constraints, err := authz.ParseConstraints(claims.Constraints)
+	if err != nil {
+		return nil, domain.ErrInvalidToken.Wrap(err)
+	}
+
+	return &Identity{
+		ClientID: "user:" + strconv.FormatUint(uint64(claims.Sub), 10),
+		UserID:   claims.Sub,
+		TenantID: claims.TenantID,
+		Principal: &authz.Principal{
+			UserID:      claims.Sub,
+			Scopes:      claims.Scopes,
+			Constraints: constraints,
+		},
+	}, nil
+}
+
+// ValidateClients checks the client certificate mappings at startup, so a
+// misconfigured agent is reported before it is turned away.
+func ValidateClients(tls config.TLS) error {
+	var errs []error
+	for cn := range tls.ClientScopes {
+		if _, _, err := clientPrincipal(tls, cn); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for cn := range tls.ClientConstraints {
+		if _, ok := tls.ClientScopes[cn]; !ok {
+			errs = append(errs, fmt.Errorf("client certificate %q has constraints but no scopes", cn))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// clientPrincipal maps a client certificate to its principal and tenant. A
+// certificate granted scopes must name its tenant: falling back to the
+// default tenant would hand an agent another tenant's inventory.
+func clientPrincipal(tls config.TLS, cn string) (*authz.Principal, string, error) {
+	tenantID, ok := tls.ClientTenants[cn]
+	if !ok {
+		return nil, "", fmt.Errorf("client certificate %q is not mapped to a tenant", cn)
+	}
+	constraints, err := authz.ParseConstraints(tls.ClientConstraints[cn])
+	if err != nil {
+		return nil, "", fmt.Errorf("client certificate %q: %w", cn, err)
+	}
+	return &authz.Principal{Scopes: tls.ClientScopes[cn], Constraints: constraints}, tenantID, nil
+}
+
+// NewContext stores the principal and tenant of the identity on ctx, so
+// usecases and repositories can scope their work by them.
+func NewContext(ctx context.Context, identity *Identity) context.Context {
+	ctx = authz.NewContext(ctx, identity.Principal)
+	return tenant.NewContext(ctx, identity.TenantID)
+}
+
+func (a *authenticator) validateToken(token string) (*dto.Claims, error) {
+	accessToken, err := jwt.ParseWithClaims(token, &dto.Claims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected token signing method: %v", token.Header["alg"])
+		}
+		return []byte(a.jwt.Secret), nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	if claims, ok := accessToken.Claims.(*dto.Claims); ok && accessToken.Valid {
+		return claims, nil
+	}
+
+	return nil, fmt.Errorf("invalid token claims: %w", err)
+}
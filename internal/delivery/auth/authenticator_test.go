@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/authz"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+)
+
+func TestAuthenticate_TokenTenant(t *testing.T) {
+	sign := func(tenantID string) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &dto.Claims{
+			Sub:              1,
+			TenantID:         tenantID,
+			RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+		}).SignedString([]byte("secret"))
+		if err != nil {
+			t.Fatalf("sign token: %v", err)
+		}
+		return token
+	}
+
+	cases := []struct {
+		name          string
+		requireTenant bool
+		tenantID      string
+		want          string
+		err           error
+	}{
+		{"tenant claim", false, "acme", "acme", nil},
+		{"no claim falls back to default", false, "", tenant.Default, nil},
+		{"tenant claim when required", true, "acme", "acme", nil},
+		{"no claim when required", true, "", "", domain.ErrInvalidToken},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			authenticator := NewAuthenticator(config.JWT{Secret: "secret", RequireTenant: tc.requireTenant}, config.APIKey{}, config.TLS{})
+
+			identity, err := authenticator.Authenticate(Credentials{Token: sign(tc.tenantID)})
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("got error %v, want %v", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("authenticate: %v", err)
+			}
+			if got := tenant.FromContext(NewContext(context.Background(), identity)); got != tc.want {
+				t.Fatalf("got tenant %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticate_ClientCertificate(t *testing.T) {
+	tls := config.TLS{
+		ClientScopes: map[string][]string{
+			"agent-hn":     {authz.ScopeServerView},
+			"agent-orphan": {authz.ScopeServerView},
+			"agent-bad":    {authz.ScopeServerView},
+		},
+		ClientTenants: map[string]string{"agent-hn": "acme", "agent-bad": "acme"},
+		ClientConstraints: map[string][]string{
+			"agent-hn":  {"location=HN"},
+			"agent-bad": {"rack=7"},
+		},
+	}
+	authenticator := NewAuthenticator(config.JWT{Secret: "secret"}, config.APIKey{}, tls)
+
+	identity, err := authenticator.Authenticate(Credentials{CommonName: "agent-hn"})
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	if identity.TenantID != "acme" || identity.ClientID != "cert:agent-hn" {
+		t.Fatalf("unexpected identity %+v", identity)
+	}
+	if got := identity.Principal.Constraints[authz.ConstraintLocation]; len(got) != 1 || got[0] != "HN" {
+		t.Fatalf("expected the certificate's constraints, got %v", identity.Principal.Constraints)
+	}
+
+	for _, cn := range []string{"agent-orphan", "agent-bad", "unknown"} {
+		if _, err := authenticator.Authenticate(Credentials{CommonName: cn}); !errors.Is(err, domain.ErrUnauthorized) {
+			t.Fatalf("%s: got error %v, want ErrUnauthorized", cn, err)
+		}
+	}
+}
+
+func TestValidateClients(t *testing.T) {
+	valid := config.TLS{
+		ClientScopes:      map[string][]string{"agent-hn": {authz.ScopeServerView}},
+		ClientTenants:     map[string]string{"agent-hn": "acme"},
+		ClientConstraints: map[string][]string{"agent-hn": {"location=HN"}},
+	}
+	if err := ValidateClients(valid); err != nil {
+		t.Fatalf("valid mapping rejected: %v", err)
+	}
+
+	invalid := config.TLS{
+		ClientScopes:      map[string][]string{"agent-orphan": {authz.ScopeServerView}, "agent-bad": {authz.ScopeServerView}},
+		ClientTenants:     map[string]string{"agent-bad": "acme"},
+		ClientConstraints: map[string][]string{"agent-bad": {"rack=7"}, "agent-unscoped": {"location=HN"}},
+	}
+	err := ValidateClients(invalid)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, want := range []string{`"agent-orphan" is not mapped to a tenant`, `"agent-bad": unsupported constraint key`, `"agent-unscoped" has constraints but no scopes`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+}
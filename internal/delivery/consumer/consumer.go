@@ -2,7 +2,10 @@ package consumer
 
 import (
 	"context"
+	"errors"
 
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/mq"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/kafka/broadcast"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/kafka/consumer"
 	"go.uber.org/zap"
 )
@@ -14,12 +17,19 @@ const (
 type (
 	Root interface {
 		Start(ctx context.Context) error
+		Stop(ctx context.Context) error
+		Healthy() error
 	}
 
 	root struct {
 		logger            *zap.Logger
 		statusConsumer    consumer.Consumer
 		statusHandlerFunc StatusHandleFunc
+		streamListener    broadcast.Listener
+		streamHandlerFunc StreamHandleFunc
+
+		stopStream context.CancelFunc
+		streamDone chan struct{}
 	}
 )
 
@@ -27,11 +37,15 @@ func NewRoot(
 	logger *zap.Logger,
 	statusConsumer consumer.Consumer,
 	statusHandlerFunc StatusHandleFunc,
+	streamListener broadcast.Listener,
+	streamHandlerFunc StreamHandleFunc,
 ) Root {
 	return &root{
 		logger:            logger,
 		statusConsumer:    statusConsumer,
 		statusHandlerFunc: statusHandlerFunc,
+		streamListener:    streamListener,
+		streamHandlerFunc: streamHandlerFunc,
 	}
 }
 
@@ -40,8 +54,8 @@ func (r *root) Start(ctx context.Context) error {
 
 	r.statusConsumer.RegisterHandler(
 		STATUS_UPDATE_TOPIC,
-		func(ctx context.Context, queueName string, payload []byte) error {
-			return r.statusHandlerFunc.Handle(ctx, queueName, payload)
+		func(ctx context.Context, message mq.Message) error {
+			return r.statusHandlerFunc.Handle(ctx, message)
 		},
 	)
 
@@ -51,5 +65,36 @@ func (r *root) Start(ctx context.Context) error {
 			r.logger.Error("Failed to start consumer", zap.Error(err))
 		}
 	}()
+
+	streamCtx, stopStream := context.WithCancel(ctx)
+	r.stopStream = stopStream
+	r.streamDone = make(chan struct{})
+	go func() {
+		defer close(r.streamDone)
+		if err := r.streamListener.Listen(streamCtx, r.streamHandlerFunc.Handle); err != nil {
+			r.logger.Error("Failed to listen to server events", zap.Error(err))
+		}
+	}()
 	return nil
 }
+
+// Stop drains the status consumer: the message in flight is finished, its
+// offset committed, and the group left. The stream listener has nothing to
+// commit and simply stops.
+func (r *root) Stop(ctx context.Context) error {
+	err := r.statusConsumer.Stop(ctx)
+
+	if r.stopStream != nil {
+		r.stopStream()
+		select {
+		case <-r.streamDone:
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		}
+	}
+	return err
+}
+
+func (r *root) Healthy() error {
+	return r.statusConsumer.Healthy()
+}
@@ -3,39 +3,78 @@ package consumer
 import (
 	"context"
 	"encoding/json"
+	"errors"
 
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/mq"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/metrics"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/server"
 	"go.uber.org/zap"
 )
 
 type StatusHandleFunc interface {
-	Handle(ctx context.Context, topic string, payload []byte) error
+	Handle(ctx context.Context, message mq.Message) error
 }
 
 type statusHandleFunc struct {
-	logger  *zap.Logger
-	usecase server.UseCase
+	allowMissingTenant bool
+	logger             *zap.Logger
+	usecase            server.UseCase
+	metrics            *metrics.Metrics
 }
 
+// NewStatusHandlerFunc applies status messages to the tenant named in their
+// header. Messages without one are dropped unless the config allows them for
+// the default tenant, so an agent cannot reach another tenant's servers by
+// leaving the header out.
 func NewStatusHandlerFunc(
+	config *config.Config,
 	logger *zap.Logger,
 	usecase server.UseCase,
+	metrics *metrics.Metrics,
 ) StatusHandleFunc {
 	return &statusHandleFunc{
-		logger:  logger,
-		usecase: usecase,
+		allowMissingTenant: config.Consumer.AllowMissingTenant,
+		logger:             logger,
+		usecase:            usecase,
+		metrics:            metrics,
 	}
 }
 
-func (h *statusHandleFunc) Handle(ctx context.Context, topic string, payload []byte) error {
-	h.logger.Info("Handling message", zap.String("topic", topic), zap.ByteString("payload", payload))
+func (h *statusHandleFunc) Handle(ctx context.Context, message mq.Message) error {
+	tenantID := message.Headers[tenant.Header]
+	logger := log.LoggerWithContext(ctx, h.logger)
+	logger.Info("Handling message",
+		zap.String("topic", message.Topic),
+		zap.String("tenant_id", tenantID),
+		zap.ByteString("payload", message.Body))
 
 	var msg dto.UpdateStatusMessage
-	if err := json.Unmarshal(payload, &msg); err != nil {
-		h.logger.Error("failed to unmarshal payload", zap.Error(err))
+	if err := json.Unmarshal(message.Body, &msg); err != nil {
+		logger.Error("failed to unmarshal payload", zap.Error(err))
 		return err
 	}
 
-	return h.usecase.UpdateStatus(ctx, msg)
+	if tenantID == "" && !h.allowMissingTenant {
+		h.metrics.StatusIgnored.WithLabelValues("no_tenant").Inc()
+		logger.Warn("Ignoring status without a tenant header", zap.String("server_id", msg.ServerID))
+		return nil
+	}
+
+	err := h.usecase.UpdateStatus(tenant.NewContext(ctx, tenantID), msg)
+	switch {
+	case errors.Is(err, domain.ErrServerDeleted):
+		h.metrics.StatusIgnored.WithLabelValues("deleted").Inc()
+		logger.Info("Ignoring status for deleted server", zap.String("server_id", msg.ServerID))
+		return nil
+	case errors.Is(err, domain.ErrServerNotFound):
+		h.metrics.StatusIgnored.WithLabelValues("unknown").Inc()
+		logger.Warn("Ignoring status for unknown server", zap.String("server_id", msg.ServerID))
+		return nil
+	}
+	return err
 }
@@ -0,0 +1,64 @@
+package consumer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/mq"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/metrics"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/server"
+	"go.uber.org/zap"
+)
+
+// recordingServers embeds the interface so only UpdateStatus needs a body.
+type recordingServers struct {
+	server.UseCase
+	tenantID string
+	message  dto.UpdateStatusMessage
+}
+
+func (r *recordingServers) UpdateStatus(ctx context.Context, message dto.UpdateStatusMessage) error {
+	r.tenantID, r.message = tenant.FromContext(ctx), message
+	return nil
+}
+
+func TestStatusHandler_TenantHeader(t *testing.T) {
+	cases := []struct {
+		name         string
+		headers      map[string]string
+		allowMissing bool
+		want         string
+	}{
+		{"header", map[string]string{tenant.Header: "acme"}, false, "acme"},
+		{"no header", nil, false, ""},
+		{"no header allowed", nil, true, tenant.Default},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			servers := &recordingServers{}
+			cfg := &config.Config{Consumer: config.Consumer{AllowMissingTenant: tc.allowMissing}}
+			handler := NewStatusHandlerFunc(cfg, zap.NewNop(), servers, metrics.NewMetrics())
+
+			err := handler.Handle(context.Background(), mq.Message{
+				Topic:   "server.status",
+				Headers: tc.headers,
+				Body:    []byte(`{"server_id":"srv-1","status":"ONLINE"}`),
+			})
+			if err != nil {
+				t.Fatalf("handle: %v", err)
+			}
+			if tc.want == "" {
+				if servers.message.ServerID != "" {
+					t.Fatalf("got status applied to tenant %q, want it dropped", servers.tenantID)
+				}
+				return
+			}
+			if servers.tenantID != tc.want || servers.message.ServerID != "srv-1" {
+				t.Fatalf("got tenant %q for %q, want %q for srv-1", servers.tenantID, servers.message.ServerID, tc.want)
+			}
+		})
+	}
+}
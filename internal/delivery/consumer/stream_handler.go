@@ -0,0 +1,42 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/mq"
+	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/stream"
+	"go.uber.org/zap"
+)
+
+type StreamHandleFunc interface {
+	Handle(ctx context.Context, message mq.Message) error
+}
+
+type streamHandleFunc struct {
+	logger  *zap.Logger
+	usecase stream.UseCase
+}
+
+func NewStreamHandlerFunc(
+	logger *zap.Logger,
+	usecase stream.UseCase,
+) StreamHandleFunc {
+	return &streamHandleFunc{
+		logger:  logger,
+		usecase: usecase,
+	}
+}
+
+func (h *streamHandleFunc) Handle(ctx context.Context, message mq.Message) error {
+	var event dto.ServerEvent
+	if err := json.Unmarshal(message.Body, &event); err != nil {
+		log.LoggerWithContext(ctx, h.logger).Error("failed to unmarshal server event", zap.Error(err))
+		return err
+	}
+
+	h.usecase.Broadcast(ctx, event)
+	return nil
+}
@@ -0,0 +1,144 @@
+package grpc
+
+import (
+	"github.com/mcuadros/go-defaults"
+	serverv1 "github.com/th1enq/ViettelSMS_ServerService/api/proto/server/v1"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	statusToProto = map[entity.ServerStatus]serverv1.ServerStatus{
+		entity.ServerStatusUnknown: serverv1.ServerStatus_SERVER_STATUS_UNKNOWN,
+		entity.ServerStatusOnline:  serverv1.ServerStatus_SERVER_STATUS_ONLINE,
+		entity.ServerStatusOffline: serverv1.ServerStatus_SERVER_STATUS_OFFLINE,
+	}
+	statusFromProto = map[serverv1.ServerStatus]entity.ServerStatus{
+		serverv1.ServerStatus_SERVER_STATUS_UNKNOWN: entity.ServerStatusUnknown,
+		serverv1.ServerStatus_SERVER_STATUS_ONLINE:  entity.ServerStatusOnline,
+		serverv1.ServerStatus_SERVER_STATUS_OFFLINE: entity.ServerStatusOffline,
+	}
+)
+
+func toServer(server *dto.ServerResponse) *serverv1.Server {
+	message := &serverv1.Server{
+		ServerId:     server.ServerID,
+		ServerName:   server.ServerName,
+		Ipv4:         server.IPv4,
+		Status:       statusToProto[server.Status],
+		Location:     server.Location,
+		Os:           server.OS,
+		Group:        server.Group,
+		Labels:       server.Labels,
+		IntervalTime: int32(server.IntervalTime),
+	}
+	if server.DeletedAt != nil {
+		message.DeletedAt = timestamppb.New(*server.DeletedAt)
+	}
+	return message
+}
+
+func toServers(servers []*dto.ServerResponse) []*serverv1.Server {
+	messages := make([]*serverv1.Server, len(servers))
+	for i, server := range servers {
+		messages[i] = toServer(server)
+	}
+	return messages
+}
+
+// toFilter leaves the status unset for SERVER_STATUS_UNSPECIFIED.
+func toFilter(filter *serverv1.ServerFilter) (dto.ServerFilterOptions, error) {
+	options := dto.ServerFilterOptions{
+		ServerName:     serverName(filter),
+		IncludeDeleted: filter.GetIncludeDeleted(),
+	}
+	if filter.GetStatus() != serverv1.ServerStatus_SERVER_STATUS_UNSPECIFIED {
+		status, ok := statusFromProto[filter.GetStatus()]
+		if !ok {
+			return options, domain.NewValidationError(domain.FieldError{Field: "filter.status", Message: "is not a known status"})
+		}
+		options.Status = &status
+	}
+	return options, nil
+}
+
+// toPagination applies the REST defaults to the fields left at zero.
+func toPagination(pagination *serverv1.Pagination) dto.ServerPaginationOptions {
+	var options dto.ServerPaginationOptions
+	defaults.SetDefaults(&options)
+	if pagination.GetPage() != 0 {
+		options.Page = int(pagination.GetPage())
+	}
+	if pagination.GetPageSize() != 0 {
+		options.PageSize = int(pagination.GetPageSize())
+	}
+	if pagination.GetSortBy() != "" {
+		options.SortBy = pagination.GetSortBy()
+	}
+	if pagination.GetSortOrder() != "" {
+		options.SortOrder = pagination.GetSortOrder()
+	}
+	return options
+}
+
+func toCreateParams(req *serverv1.CreateServerRequest) dto.CreateServerParams {
+	return dto.CreateServerParams{
+		ServerID:     req.GetServerId(),
+		ServerName:   req.GetServerName(),
+		IPv4:         req.GetIpv4(),
+		Location:     req.Location,
+		OS:           req.Os,
+		Group:        req.Group,
+		Labels:       req.GetLabels(),
+		IntervalTime: int(req.GetIntervalTime()),
+	}
+}
+
+func toUpdateParams(req *serverv1.UpdateServerRequest) dto.UpdateServerParams {
+	params := dto.UpdateServerParams{
+		ServerName: req.ServerName,
+		IPv4:       req.Ipv4,
+		Location:   req.Location,
+		OS:         req.Os,
+		Group:      req.Group,
+	}
+	if len(req.GetLabels()) > 0 {
+		params.Labels = req.GetLabels()
+	}
+	if req.IntervalTime != nil {
+		intervalTime := int(req.GetIntervalTime())
+		params.IntervalTime = &intervalTime
+	}
+	return params
+}
+
+func toImportResponse(result *dto.ImportServerResponse) *serverv1.ImportServersResponse {
+	return &serverv1.ImportServersResponse{
+		SuccessCount:  int32(result.SuccessCount),
+		ServerIds:     result.SuccessServers,
+		FailedCount:   int32(result.FailedCount),
+		FailedServers: result.FailedServers,
+	}
+}
+
+func toStatusEvent(event dto.ServerEvent) *serverv1.StatusEvent {
+	message := &serverv1.StatusEvent{
+		Id:             event.ID,
+		PreviousStatus: statusToProto[event.PreviousStatus],
+		OccurredAt:     timestamppb.New(event.OccurredAt),
+	}
+	if event.Server != nil {
+		message.Server = toServer(event.Server)
+	}
+	return message
+}
+
+// serverName keeps the difference between an unset and an empty name filter.
+func serverName(filter *serverv1.ServerFilter) *string {
+	if filter == nil {
+		return nil
+	}
+	return filter.ServerName
+}
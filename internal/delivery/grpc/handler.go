@@ -0,0 +1,202 @@
+package grpc
+
+import (
+	"context"
+	"os"
+
+	serverv1 "github.com/th1enq/ViettelSMS_ServerService/api/proto/server/v1"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/validation"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	server_usecase "github.com/th1enq/ViettelSMS_ServerService/internal/usecase/server"
+	stream_usecase "github.com/th1enq/ViettelSMS_ServerService/internal/usecase/stream"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// serverHandler serves ServerService with the usecases behind the REST API.
+// It returns catalogued errors; the interceptor turns them into statuses.
+type serverHandler struct {
+	serverv1.UnimplementedServerServiceServer
+
+	usecase server_usecase.UseCase
+	stream  stream_usecase.UseCase
+	logger  *zap.Logger
+}
+
+func NewServerHandler(
+	usecase server_usecase.UseCase,
+	stream stream_usecase.UseCase,
+	logger *zap.Logger,
+) serverv1.ServerServiceServer {
+	return &serverHandler{
+		usecase: usecase,
+		stream:  stream,
+		logger:  logger,
+	}
+}
+
+func (h *serverHandler) CreateServer(ctx context.Context, req *serverv1.CreateServerRequest) (*serverv1.Server, error) {
+	logger := log.LoggerWithContext(ctx, h.logger)
+
+	params := toCreateParams(req)
+	if err := validation.Struct(&params); err != nil {
+		logger.Warn("Invalid create server request", zap.Error(err))
+		return nil, err
+	}
+
+	created, err := h.usecase.CreateServer(ctx, params)
+	if err != nil {
+		logger.Warn("Failed to create server", zap.Error(err))
+		return nil, err
+	}
+	return toServer(created), nil
+}
+
+func (h *serverHandler) UpdateServer(ctx context.Context, req *serverv1.UpdateServerRequest) (*serverv1.Server, error) {
+	logger := log.LoggerWithContext(ctx, h.logger)
+
+	if err := requireServerID(req.GetServerId()); err != nil {
+		return nil, err
+	}
+	params := toUpdateParams(req)
+	if err := validation.Struct(&params); err != nil {
+		logger.Warn("Invalid update server request", zap.Error(err))
+		return nil, err
+	}
+
+	updated, err := h.usecase.UpdateServer(ctx, req.GetServerId(), params)
+	if err != nil {
+		logger.Warn("Failed to update server", zap.String("server_id", req.GetServerId()), zap.Error(err))
+		return nil, err
+	}
+	return toServer(updated), nil
+}
+
+func (h *serverHandler) DeleteServer(ctx context.Context, req *serverv1.DeleteServerRequest) (*serverv1.DeleteServerResponse, error) {
+	if err := requireServerID(req.GetServerId()); err != nil {
+		return nil, err
+	}
+	if err := h.usecase.DeleteServer(ctx, req.GetServerId()); err != nil {
+		log.LoggerWithContext(ctx, h.logger).Warn("Failed to delete server", zap.String("server_id", req.GetServerId()), zap.Error(err))
+		return nil, err
+	}
+	return &serverv1.DeleteServerResponse{}, nil
+}
+
+func (h *serverHandler) RestoreServer(ctx context.Context, req *serverv1.RestoreServerRequest) (*serverv1.Server, error) {
+	if err := requireServerID(req.GetServerId()); err != nil {
+		return nil, err
+	}
+	restored, err := h.usecase.RestoreServer(ctx, req.GetServerId())
+	if err != nil {
+		log.LoggerWithContext(ctx, h.logger).Warn("Failed to restore server", zap.String("server_id", req.GetServerId()), zap.Error(err))
+		return nil, err
+	}
+	return toServer(restored), nil
+}
+
+func (h *serverHandler) ListServers(ctx context.Context, req *serverv1.ListServersRequest) (*serverv1.ListServersResponse, error) {
+	logger := log.LoggerWithContext(ctx, h.logger)
+
+	filter, err := toFilter(req.GetFilter())
+	if err != nil {
+		return nil, err
+	}
+	pagination := toPagination(req.GetPagination())
+	if err := validation.Struct(&pagination); err != nil {
+		logger.Warn("Invalid pagination options", zap.Error(err))
+		return nil, err
+	}
+
+	servers, total, err := h.usecase.ViewServer(ctx, filter, pagination)
+	if err != nil {
+		logger.Error("Failed to list servers", zap.Error(err))
+		return nil, err
+	}
+	return &serverv1.ListServersResponse{Servers: toServers(servers), Total: int32(total)}, nil
+}
+
+// ImportServers stages the workbook in a temporary file for the usecase, which
+// reads it before returning.
+func (h *serverHandler) ImportServers(ctx context.Context, req *serverv1.ImportServersRequest) (*serverv1.ImportServersResponse, error) {
+	logger := log.LoggerWithContext(ctx, h.logger)
+
+	if len(req.GetContent()) == 0 {
+		return nil, domain.ErrInvalidFile.WithFields(domain.FieldError{Field: "content", Message: "is required"})
+	}
+
+	file, err := os.CreateTemp("", "import-*.xlsx")
+	if err != nil {
+		logger.Error("Failed to stage imported file", zap.Error(err))
+		return nil, domain.ErrInternalServer.Wrap(err)
+	}
+	defer os.Remove(file.Name())
+	_, err = file.Write(req.GetContent())
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		logger.Error("Failed to stage imported file", zap.Error(err))
+		return nil, domain.ErrInternalServer.Wrap(err)
+	}
+
+	logger.Info("Importing servers", zap.String("file_name", req.GetFileName()), zap.Int("size", len(req.GetContent())))
+	result, err := h.usecase.ImportServer(ctx, file.Name())
+	if err != nil {
+		logger.Warn("Failed to import servers", zap.Error(err))
+		return nil, err
+	}
+	return toImportResponse(result), nil
+}
+
+// WatchStatus forwards the status changes visible to the caller until the
+// client leaves, falls behind or the service shuts down.
+func (h *serverHandler) WatchStatus(req *serverv1.WatchStatusRequest, stream serverv1.ServerService_WatchStatusServer) error {
+	ctx := stream.Context()
+	logger := log.LoggerWithContext(ctx, h.logger)
+
+	filter, err := toFilter(req.GetFilter())
+	if err != nil {
+		return err
+	}
+	subscription, err := h.stream.Subscribe(ctx, filter)
+	if err != nil {
+		logger.Warn("Failed to subscribe to server events", zap.Error(err))
+		return err
+	}
+	defer subscription.Unsubscribe()
+	logger.Info("Status watch opened")
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Status watch closed by client")
+			return nil
+		case event, ok := <-subscription.Events:
+			if !ok {
+				logger.Info("Status watch ended", zap.Bool("lagged", subscription.Lagged()))
+				if subscription.Lagged() {
+					return status.Error(codes.Aborted, "subscriber fell behind, list the servers again")
+				}
+				return domain.ErrShuttingDown
+			}
+			if event.Type != dto.ServerEventStatusChanged {
+				continue
+			}
+			if err := stream.Send(toStatusEvent(event)); err != nil {
+				logger.Info("Status watch send failed", zap.Error(err))
+				return err
+			}
+		}
+	}
+}
+
+func requireServerID(serverID string) error {
+	if serverID == "" {
+		return domain.NewValidationError(domain.FieldError{Field: "server_id", Message: "is required"})
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package grpc
+
+import (
+	"context"
+	"runtime/debug"
+	"strings"
+
+	serverv1 "github.com/th1enq/ViettelSMS_ServerService/api/proto/server/v1"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/auth"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/authz"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/requestid"
+	srv "github.com/th1enq/ViettelSMS_ServerService/internal/domain/service"
+	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/idempotency"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// API_KEY_METADATA carries the API key, like the X-API-Key header over HTTP.
+const API_KEY_METADATA = "x-api-key"
+
+// publicMethodPrefixes are the services reachable without credentials.
+var publicMethodPrefixes = []string{"/grpc.health.v1.", "/grpc.reflection."}
+
+// methodScopes lists the scope each RPC requires. Methods missing here are
+// refused, so a new RPC is closed until it is given a scope.
+var methodScopes = map[string]string{
+	serverv1.ServerService_CreateServer_FullMethodName:  authz.ScopeServerCreate,
+	serverv1.ServerService_UpdateServer_FullMethodName:  authz.ScopeServerUpdate,
+	serverv1.ServerService_DeleteServer_FullMethodName:  authz.ScopeServerDelete,
+	serverv1.ServerService_RestoreServer_FullMethodName: authz.ScopeServerRestore,
+	serverv1.ServerService_ListServers_FullMethodName:   authz.ScopeServerView,
+	serverv1.ServerService_ImportServers_FullMethodName: authz.ScopeServerImport,
+	serverv1.ServerService_WatchStatus_FullMethodName:   authz.ScopeServerView,
+}
+
+// interceptor does for RPCs what the RequestID, RequireAuth, RequireScope,
+// RateLimit, Idempotency, Errors and Recovery middlewares do for HTTP
+// requests.
+type interceptor struct {
+	authenticator auth.Authenticator
+	limiter       srv.RateLimiter
+	rateLimits    config.RateLimit
+	idempotency   idempotency.UseCase
+	logger        *zap.Logger
+}
+
+func (i *interceptor) Unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	ctx = i.withRequestID(ctx)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(strings.ToLower(requestid.Header), requestid.FromContext(ctx)))
+	defer i.recover(ctx, info.FullMethod, &err)
+
+	ctx, identity, err := i.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	if err := i.rateLimit(ctx, info.FullMethod, identity); err != nil {
+		return nil, statusError(err)
+	}
+
+	resp, err = i.idempotent(ctx, req, info, identity, handler)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return resp, nil
+}
+
+func (i *interceptor) Stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	ctx := i.withRequestID(ss.Context())
+	_ = ss.SetHeader(metadata.Pairs(strings.ToLower(requestid.Header), requestid.FromContext(ctx)))
+	defer i.recover(ctx, info.FullMethod, &err)
+
+	ctx, identity, err := i.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return statusError(err)
+	}
+	if err := i.rateLimit(ctx, info.FullMethod, identity); err != nil {
+		return statusError(err)
+	}
+
+	if err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx}); err != nil {
+		return statusError(err)
+	}
+	return nil
+}
+
+// withRequestID accepts the caller's x-request-id or generates one.
+func (i *interceptor) withRequestID(ctx context.Context) context.Context {
+	return requestid.NewContext(ctx, requestid.Sanitize(firstMetadata(ctx, requestid.Header)))
+}
+
+// authorize authenticates the caller and checks the scope of the method,
+// returning a context carrying the caller's principal and tenant. Public
+// methods have no identity.
+func (i *interceptor) authorize(ctx context.Context, method string) (context.Context, *auth.Identity, error) {
+	for _, prefix := range publicMethodPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return ctx, nil, nil
+		}
+	}
+
+	identity, err := i.authenticator.Authenticate(auth.Credentials{
+		APIKey:     firstMetadata(ctx, API_KEY_METADATA),
+		Token:      bearerToken(ctx),
+		CommonName: clientCommonName(ctx),
+	})
+	if err != nil {
+		log.LoggerWithContext(ctx, i.logger).Info("RPC authentication failed", zap.String("method", method), zap.Error(err))
+		return nil, nil, err
+	}
+
+	scope, ok := methodScopes[method]
+	if !ok || !identity.Principal.HasScope(scope) {
+		return nil, nil, domain.ErrInsufficientScope
+	}
+	return auth.NewContext(ctx, identity), identity, nil
+}
+
+// recover turns a panic in a handler into an internal error, so one bad
+// request does not take the process down.
+func (i *interceptor) recover(ctx context.Context, method string, err *error) {
+	if r := recover(); r != nil {
+		log.LoggerWithContext(ctx, i.logger).Error("RPC handler panicked",
+			zap.String("method", method), zap.Any("panic", r), zap.ByteString("stack", debug.Stack()))
+		*err = statusError(domain.ErrInternalServer)
+	}
+}
+
+// contextStream hands the authenticated context to stream handlers.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func firstMetadata(ctx context.Context, key string) string {
+	values := metadata.ValueFromIncomingContext(ctx, strings.ToLower(key))
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func bearerToken(ctx context.Context) string {
+	authorization := firstMetadata(ctx, "authorization")
+	if !strings.HasPrefix(authorization, "Bearer ") {
+		return ""
+	}
+	return strings.TrimPrefix(authorization, "Bearer ")
+}
+
+// clientCommonName returns the common name of the verified client certificate,
+// or "" when the connection carries none.
+func clientCommonName(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	return info.State.VerifiedChains[0][0].Subject.CommonName
+}
@@ -0,0 +1,153 @@
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	serverv1 "github.com/th1enq/ViettelSMS_ServerService/api/proto/server/v1"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/auth"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// IDEMPOTENCY_KEY_METADATA carries the key, like the Idempotency-Key
+	// header over HTTP.
+	IDEMPOTENCY_KEY_METADATA = "idempotency-key"
+	// IDEMPOTENT_REPLAYED_METADATA marks a response replayed from storage.
+	IDEMPOTENT_REPLAYED_METADATA = "idempotent-replayed"
+	MAX_IDEMPOTENCY_KEY_LENGTH   = 255
+
+	// RATE_LIMIT_ROUTE_PREFIX names the rate limit routes of RPCs, e.g.
+	// GRPC:/server.v1.ServerService/ImportServers.
+	RATE_LIMIT_ROUTE_PREFIX = "GRPC:"
+	// DEFAULT_LIMIT_BUCKET is the bucket shared with HTTP routes without a
+	// limit of their own.
+	DEFAULT_LIMIT_BUCKET = "default"
+
+	// idempotentContentType marks stored responses as protobuf messages.
+	idempotentContentType = "application/x-protobuf"
+)
+
+// idempotentMethods are the RPCs honouring an idempotency key, with the
+// response message a stored response is decoded into.
+var idempotentMethods = map[string]func() proto.Message{
+	serverv1.ServerService_CreateServer_FullMethodName:  func() proto.Message { return &serverv1.Server{} },
+	serverv1.ServerService_ImportServers_FullMethodName: func() proto.Message { return &serverv1.ImportServersResponse{} },
+}
+
+// rateLimit gives every client a token bucket per limited RPC, falling back to
+// the bucket its HTTP requests share. When the limiter fails, the RPC is let
+// through rather than rejected.
+func (i *interceptor) rateLimit(ctx context.Context, method string, identity *auth.Identity) error {
+	if i.limiter == nil || !i.rateLimits.Enabled || identity == nil {
+		return nil
+	}
+
+	route := RATE_LIMIT_ROUTE_PREFIX + method
+	limit, ok := i.rateLimits.Routes[route]
+	if !ok {
+		route, limit = DEFAULT_LIMIT_BUCKET, i.rateLimits.Default
+	}
+
+	client := tenant.FromContext(ctx) + "|" + identity.ClientID
+	allowed, wait, err := i.limiter.Allow(ctx, route+"|"+client, limit.Requests, limit.Period)
+	if err != nil {
+		log.LoggerWithContext(ctx, i.logger).Warn("Rate limiter unavailable, RPC let through", zap.Error(err))
+		return nil
+	}
+	if !allowed {
+		log.LoggerWithContext(ctx, i.logger).Info("Rate limit exceeded",
+			zap.String("route", route), zap.Duration("retry_after", wait))
+		return domain.ErrRateLimited.WithRetryAfter(wait)
+	}
+	return nil
+}
+
+// idempotent runs an RPC sent with an idempotency key at most once per key,
+// as the Idempotency middleware does for HTTP: retries with the same request
+// get the stored response back, the same key with another request is
+// rejected, and failed RPCs are not stored so they can be retried.
+func (i *interceptor) idempotent(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, identity *auth.Identity, handler grpc.UnaryHandler) (interface{}, error) {
+	newResponse, ok := idempotentMethods[info.FullMethod]
+	key := strings.TrimSpace(firstMetadata(ctx, IDEMPOTENCY_KEY_METADATA))
+	if i.idempotency == nil || !ok || key == "" {
+		return handler(ctx, req)
+	}
+	if len(key) > MAX_IDEMPOTENCY_KEY_LENGTH {
+		return nil, domain.NewValidationError(domain.FieldError{
+			Field:   IDEMPOTENCY_KEY_METADATA,
+			Message: fmt.Sprintf("must be at most %d characters", MAX_IDEMPOTENCY_KEY_LENGTH),
+		})
+	}
+
+	hash, err := rpcHash(info.FullMethod, identity, req)
+	if err != nil {
+		return nil, domain.ErrInvalidRequest.Wrap(err)
+	}
+
+	stored, err := i.idempotency.Begin(ctx, key, hash)
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil {
+		resp := newResponse()
+		if err := proto.Unmarshal(stored.Body, resp); err != nil {
+			log.LoggerWithContext(ctx, i.logger).Error("failed to decode stored response",
+				zap.String("idempotency_key", key), zap.Error(err))
+			return nil, domain.ErrInternalServer
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs(IDEMPOTENT_REPLAYED_METADATA, "true"))
+		return resp, nil
+	}
+
+	resp, err := handler(ctx, req)
+
+	// The key outlives a caller that hung up, so the outcome is saved regardless.
+	saveCtx := context.WithoutCancel(ctx)
+	message, isMessage := resp.(proto.Message)
+	var body []byte
+	if err == nil && isMessage {
+		body, err = proto.Marshal(message)
+		if err != nil {
+			log.LoggerWithContext(ctx, i.logger).Error("failed to encode response", zap.Error(err))
+			err = domain.ErrInternalServer
+		}
+	}
+	if err != nil || !isMessage {
+		if releaseErr := i.idempotency.Release(saveCtx, key); releaseErr != nil {
+			log.LoggerWithContext(ctx, i.logger).Warn("Idempotency key left reserved until it expires", zap.String("idempotency_key", key))
+		}
+		return resp, err
+	}
+	if err := i.idempotency.Complete(saveCtx, key, http.StatusOK, idempotentContentType, body); err != nil {
+		log.LoggerWithContext(ctx, i.logger).Warn("Idempotent response not stored", zap.String("idempotency_key", key))
+	}
+	return resp, nil
+}
+
+// rpcHash identifies the request by method, caller and payload.
+func rpcHash(method string, identity *auth.Identity, req interface{}) (string, error) {
+	message, ok := req.(proto.Message)
+	if !ok {
+		return "", fmt.Errorf("unexpected request type %T", req)
+	}
+	payload, err := proto.MarshalOptions{Deterministic: true}.Marshal(message)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "GRPC %s %d\n", method, identity.UserID)
+	hash.Write(payload)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,122 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	serverv1 "github.com/th1enq/ViettelSMS_ServerService/api/proto/server/v1"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/auth"
+	srv "github.com/th1enq/ViettelSMS_ServerService/internal/domain/service"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/certificate"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/idempotency"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+type (
+	Server interface {
+		// Start serves until Shutdown is called and returns any other error.
+		Start(ctx context.Context) error
+		// Shutdown reports NOT_SERVING, stops accepting RPCs and waits for
+		// in-flight ones until ctx expires, then closes the connections.
+		Shutdown(ctx context.Context) error
+	}
+
+	server struct {
+		grpcServer *grpc.Server
+		health     *health.Server
+		config     *config.Config
+		logger     *zap.Logger
+	}
+)
+
+// NewGrpcServer serves ServerService, health and, when enabled, reflection.
+// With TLS enabled it uses the certificates of the HTTP server, whose reloads
+// it picks up; client certificates are checked the same way. RPCs share the
+// rate limiter and idempotency keys of the HTTP server.
+func NewGrpcServer(
+	config *config.Config,
+	handler serverv1.ServerServiceServer,
+	authenticator auth.Authenticator,
+	limiter srv.RateLimiter,
+	idempotency idempotency.UseCase,
+	certificates certificate.Reloader,
+	logger *zap.Logger,
+) Server {
+	interceptor := &interceptor{
+		authenticator: authenticator,
+		limiter:       limiter,
+		rateLimits:    config.RateLimit,
+		idempotency:   idempotency,
+		logger:        logger,
+	}
+	options := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(config.GRPC.MaxMessageSize),
+		grpc.ChainUnaryInterceptor(interceptor.Unary),
+		grpc.ChainStreamInterceptor(interceptor.Stream),
+	}
+	if certificates != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(certificates.TLSConfig())))
+	}
+
+	s := &server{
+		grpcServer: grpc.NewServer(options...),
+		health:     health.NewServer(),
+		config:     config,
+		logger:     logger,
+	}
+	serverv1.RegisterServerServiceServer(s.grpcServer, handler)
+	healthpb.RegisterHealthServer(s.grpcServer, s.health)
+	s.health.SetServingStatus(serverv1.ServerService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	if config.GRPC.Reflection {
+		reflection.Register(s.grpcServer)
+	}
+	return s
+}
+
+func (s *server) Start(ctx context.Context) error {
+	if !s.config.GRPC.Enabled {
+		s.logger.Info("gRPC server disabled")
+		return nil
+	}
+
+	addr := fmt.Sprintf("%s:%d", s.config.GRPC.Host, s.config.GRPC.Port)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to start gRPC server: %w", err)
+	}
+
+	s.logger.Info("gRPC server starting", zap.String("host", s.config.GRPC.Host), zap.Int("port", s.config.GRPC.Port),
+		zap.Bool("tls", s.config.TLS.Enabled))
+	if err := s.grpcServer.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return fmt.Errorf("failed to start gRPC server: %w", err)
+	}
+	return nil
+}
+
+func (s *server) Shutdown(ctx context.Context) error {
+	s.logger.Info("gRPC server shutting down")
+	s.health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.logger.Warn("gRPC server did not drain in time, closing connections", zap.Error(ctx.Err()))
+		s.grpcServer.Stop()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,339 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	serverv1 "github.com/th1enq/ViettelSMS_ServerService/api/proto/server/v1"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/auth"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/authz"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/response"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/service"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/idempotency"
+	server_usecase "github.com/th1enq/ViettelSMS_ServerService/internal/usecase/server"
+	stream_usecase "github.com/th1enq/ViettelSMS_ServerService/internal/usecase/stream"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	testSecret = "secret"
+	testAPIKey = "viewer-key"
+)
+
+type mockServers struct {
+	server_usecase.UseCase
+	created    []dto.CreateServerParams
+	filter     dto.ServerFilterOptions
+	pagination dto.ServerPaginationOptions
+	tenantID   string
+}
+
+func (m *mockServers) CreateServer(ctx context.Context, params dto.CreateServerParams) (*dto.ServerResponse, error) {
+	if params.ServerID == "taken" {
+		return nil, domain.ErrServerExist
+	}
+	m.created = append(m.created, params)
+	return &dto.ServerResponse{ServerID: params.ServerID, ServerName: params.ServerName, IPv4: params.IPv4, Status: entity.ServerStatusUnknown}, nil
+}
+
+func (m *mockServers) ViewServer(ctx context.Context, filter dto.ServerFilterOptions, pagination dto.ServerPaginationOptions) ([]*dto.ServerResponse, int, error) {
+	m.filter, m.pagination, m.tenantID = filter, pagination, tenant.FromContext(ctx)
+	return []*dto.ServerResponse{{ServerID: "srv-1", Status: entity.ServerStatusOnline, Labels: entity.Labels{"env": "prod"}}}, 1, nil
+}
+
+// memoryKeys stores idempotency keys like the repository does.
+type memoryKeys struct {
+	idempotency.UseCase
+	keys map[string]*entity.IdempotencyKey
+}
+
+func (m *memoryKeys) Begin(ctx context.Context, key, requestHash string) (*entity.IdempotencyKey, error) {
+	stored, ok := m.keys[key]
+	switch {
+	case !ok:
+		m.keys[key] = &entity.IdempotencyKey{Key: key, RequestHash: requestHash}
+		return nil, nil
+	case stored.RequestHash != requestHash:
+		return nil, domain.ErrIdempotencyKeyReused
+	case !stored.Completed():
+		return nil, domain.ErrIdempotencyKeyInProgress
+	}
+	return stored, nil
+}
+
+func (m *memoryKeys) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	m.keys[key].StatusCode, m.keys[key].ContentType, m.keys[key].Body = statusCode, contentType, body
+	return nil
+}
+
+func (m *memoryKeys) Release(ctx context.Context, key string) error {
+	delete(m.keys, key)
+	return nil
+}
+
+func startServer(t *testing.T, servers server_usecase.UseCase, stream stream_usecase.UseCase) *grpc.ClientConn {
+	t.Helper()
+	return startLimitedServer(t, servers, stream, config.RateLimit{}, &memoryKeys{keys: map[string]*entity.IdempotencyKey{}})
+}
+
+func startLimitedServer(t *testing.T, servers server_usecase.UseCase, stream stream_usecase.UseCase, limits config.RateLimit, keys idempotency.UseCase) *grpc.ClientConn {
+	t.Helper()
+	cfg := &config.Config{GRPC: config.GRPC{MaxMessageSize: 1 << 20}, RateLimit: limits}
+	authenticator := auth.NewAuthenticator(config.JWT{Secret: testSecret}, config.APIKey{
+		Tenants: map[string]string{testAPIKey: "acme"},
+		Scopes:  []string{authz.ScopeServerView},
+	}, config.TLS{})
+	s := NewGrpcServer(cfg, NewServerHandler(servers, stream, zap.NewNop()), authenticator, service.NewMemoryRateLimiter(), keys, nil, zap.NewNop()).(*server)
+
+	listener := bufconn.Listen(1 << 20)
+	go func() { _ = s.grpcServer.Serve(listener) }()
+	t.Cleanup(s.grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func withToken(t *testing.T, ctx context.Context, scopes ...string) context.Context {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &dto.Claims{
+		Sub:              7,
+		TenantID:         "acme",
+		Scopes:           scopes,
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+	}).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+func reason(t *testing.T, err error, code codes.Code) (string, []*errdetails.BadRequest_FieldViolation) {
+	t.Helper()
+	st := status.Convert(err)
+	if st.Code() != code {
+		t.Fatalf("got code %v, want %v: %v", st.Code(), code, err)
+	}
+	var (
+		errorReason string
+		violations  []*errdetails.BadRequest_FieldViolation
+	)
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			errorReason = detail.GetReason()
+		case *errdetails.BadRequest:
+			violations = detail.GetFieldViolations()
+		}
+	}
+	return errorReason, violations
+}
+
+func TestGrpc_Authentication(t *testing.T) {
+	conn := startServer(t, &mockServers{}, nil)
+	client := serverv1.NewServerServiceClient(conn)
+	ctx := context.Background()
+
+	_, err := client.ListServers(ctx, &serverv1.ListServersRequest{})
+	if got, _ := reason(t, err, codes.Unauthenticated); got != response.CodeUnauthorized {
+		t.Fatalf("got reason %q, want %q", got, response.CodeUnauthorized)
+	}
+
+	keyCtx := metadata.AppendToOutgoingContext(ctx, API_KEY_METADATA, testAPIKey)
+	_, err = client.CreateServer(keyCtx, &serverv1.CreateServerRequest{ServerId: "srv-1"})
+	if got, _ := reason(t, err, codes.PermissionDenied); got != response.CodeForbidden {
+		t.Fatalf("got reason %q, want %q", got, response.CodeForbidden)
+	}
+
+	health, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: serverv1.ServerService_ServiceDesc.ServiceName})
+	if err != nil || health.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("health check = %v, %v; want SERVING without credentials", health, err)
+	}
+}
+
+func TestGrpc_ListServers(t *testing.T) {
+	servers := &mockServers{}
+	client := serverv1.NewServerServiceClient(startServer(t, servers, nil))
+	ctx := metadata.AppendToOutgoingContext(context.Background(), API_KEY_METADATA, testAPIKey)
+
+	resp, err := client.ListServers(ctx, &serverv1.ListServersRequest{
+		Filter:     &serverv1.ServerFilter{Status: serverv1.ServerStatus_SERVER_STATUS_ONLINE},
+		Pagination: &serverv1.Pagination{PageSize: 50},
+	})
+	if err != nil {
+		t.Fatalf("ListServers() error = %v", err)
+	}
+	if resp.GetTotal() != 1 || resp.GetServers()[0].GetStatus() != serverv1.ServerStatus_SERVER_STATUS_ONLINE || resp.GetServers()[0].GetLabels()["env"] != "prod" {
+		t.Fatalf("unexpected response %v", resp)
+	}
+	if servers.tenantID != "acme" || servers.filter.Status == nil || *servers.filter.Status != entity.ServerStatusOnline {
+		t.Fatalf("usecase got tenant %q and filter %+v", servers.tenantID, servers.filter)
+	}
+	if p := servers.pagination; p.Page != 1 || p.PageSize != 50 || p.SortBy != "server_name" || p.SortOrder != "asc" {
+		t.Fatalf("pagination defaults not applied: %+v", p)
+	}
+
+	_, err = client.ListServers(ctx, &serverv1.ListServersRequest{Pagination: &serverv1.Pagination{PageSize: 500}})
+	if _, violations := reason(t, err, codes.InvalidArgument); len(violations) != 1 || violations[0].GetField() != "page_size" {
+		t.Fatalf("got violations %v, want page_size", violations)
+	}
+}
+
+func TestGrpc_CreateServerErrors(t *testing.T) {
+	servers := &mockServers{}
+	client := serverv1.NewServerServiceClient(startServer(t, servers, nil))
+	ctx := withToken(t, context.Background(), "server:*")
+
+	_, err := client.CreateServer(ctx, &serverv1.CreateServerRequest{ServerId: "srv-1", ServerName: "web", Ipv4: "not-an-ip", IntervalTime: 5})
+	got, violations := reason(t, err, codes.InvalidArgument)
+	if got != response.CodeValidationError || len(violations) != 1 || violations[0].GetField() != "ipv4" {
+		t.Fatalf("got reason %q and violations %v, want ipv4 rejected", got, violations)
+	}
+
+	_, err = client.CreateServer(ctx, &serverv1.CreateServerRequest{ServerId: "taken", ServerName: "web", Ipv4: "10.0.0.1", IntervalTime: 5})
+	reason(t, err, codes.AlreadyExists)
+
+	created, err := client.CreateServer(ctx, &serverv1.CreateServerRequest{ServerId: "srv-2", ServerName: "web", Ipv4: "10.0.0.2", IntervalTime: 5})
+	if err != nil || created.GetServerId() != "srv-2" || len(servers.created) != 1 {
+		t.Fatalf("CreateServer() = %v, %v", created, err)
+	}
+}
+
+func TestGrpc_CreateServerIdempotency(t *testing.T) {
+	servers := &mockServers{}
+	client := serverv1.NewServerServiceClient(startServer(t, servers, nil))
+	ctx := metadata.AppendToOutgoingContext(withToken(t, context.Background(), "server:*"), IDEMPOTENCY_KEY_METADATA, "create-1")
+	request := &serverv1.CreateServerRequest{ServerId: "srv-1", ServerName: "web", Ipv4: "10.0.0.1", IntervalTime: 5}
+
+	first, err := client.CreateServer(ctx, request)
+	if err != nil {
+		t.Fatalf("CreateServer() error = %v", err)
+	}
+
+	var header metadata.MD
+	replayed, err := client.CreateServer(ctx, request, grpc.Header(&header))
+	if err != nil || replayed.GetServerId() != first.GetServerId() || len(servers.created) != 1 {
+		t.Fatalf("retry = %v, %v with %d servers created, want the stored response", replayed, err, len(servers.created))
+	}
+	if got := header.Get(IDEMPOTENT_REPLAYED_METADATA); len(got) != 1 || got[0] != "true" {
+		t.Fatalf("got replayed header %v, want true", got)
+	}
+
+	request.ServerName = "db"
+	_, err = client.CreateServer(ctx, request)
+	if got, _ := reason(t, err, codes.InvalidArgument); got != response.CodeIdempotencyKeyReused {
+		t.Fatalf("got reason %q, want %q", got, response.CodeIdempotencyKeyReused)
+	}
+
+	// Failed calls release the key so they can be retried.
+	ctx = metadata.AppendToOutgoingContext(withToken(t, context.Background(), "server:*"), IDEMPOTENCY_KEY_METADATA, "create-2")
+	taken := &serverv1.CreateServerRequest{ServerId: "taken", ServerName: "web", Ipv4: "10.0.0.2", IntervalTime: 5}
+	for range 2 {
+		_, err = client.CreateServer(ctx, taken)
+		reason(t, err, codes.AlreadyExists)
+	}
+}
+
+func TestGrpc_RateLimit(t *testing.T) {
+	limits := config.RateLimit{
+		Enabled: true,
+		Default: config.Limit{Requests: 100, Period: time.Minute},
+		Routes: map[string]config.Limit{
+			RATE_LIMIT_ROUTE_PREFIX + serverv1.ServerService_CreateServer_FullMethodName: {Requests: 1, Period: time.Minute},
+		},
+	}
+	client := serverv1.NewServerServiceClient(startLimitedServer(t, &mockServers{}, nil, limits, nil))
+	ctx := withToken(t, context.Background(), "server:*")
+
+	if _, err := client.CreateServer(ctx, &serverv1.CreateServerRequest{ServerId: "srv-1", ServerName: "web", Ipv4: "10.0.0.1", IntervalTime: 5}); err != nil {
+		t.Fatalf("CreateServer() error = %v", err)
+	}
+	_, err := client.CreateServer(ctx, &serverv1.CreateServerRequest{ServerId: "srv-2", ServerName: "web", Ipv4: "10.0.0.2", IntervalTime: 5})
+	reason(t, err, codes.ResourceExhausted)
+	retry := false
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok && info.GetRetryDelay().AsDuration() > 0 {
+			retry = true
+		}
+	}
+	if !retry {
+		t.Fatal("rate limited call has no retry delay")
+	}
+
+	// Other methods draw from the default bucket.
+	if _, err := client.ListServers(ctx, &serverv1.ListServersRequest{}); err != nil {
+		t.Fatalf("ListServers() error = %v", err)
+	}
+}
+
+func TestGrpc_WatchStatus(t *testing.T) {
+	stream := stream_usecase.NewStreamUseCase(nil, &config.Config{Stream: config.Stream{BufferSize: 100}}, zap.NewNop())
+	client := serverv1.NewServerServiceClient(startServer(t, &mockServers{}, stream))
+	ctx, cancel := context.WithTimeout(withToken(t, context.Background(), authz.ScopeServerView), 5*time.Second)
+	defer cancel()
+
+	watch, err := client.WatchStatus(ctx, &serverv1.WatchStatusRequest{})
+	if err != nil {
+		t.Fatalf("WatchStatus() error = %v", err)
+	}
+
+	// Events are published until the subscription is in place; only status
+	// changes are forwarded.
+	created := dto.NewServerEvent(dto.ServerEventCreated, &entity.Server{TenantID: "acme", ServerID: "srv-1"})
+	changed := dto.NewServerEvent(dto.ServerEventStatusChanged, &entity.Server{TenantID: "acme", ServerID: "srv-1", Status: entity.ServerStatusOffline})
+	changed.PreviousStatus = entity.ServerStatusOnline
+	stop := make(chan struct{})
+	published := make(chan struct{})
+	go func() {
+		defer close(published)
+		for {
+			stream.Broadcast(context.Background(), created)
+			stream.Broadcast(context.Background(), changed)
+			select {
+			case <-stop:
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}()
+
+	event, err := watch.Recv()
+	close(stop)
+	<-published
+	if err != nil {
+		t.Fatalf("Recv() error = %v", err)
+	}
+	if event.GetServer().GetStatus() != serverv1.ServerStatus_SERVER_STATUS_OFFLINE || event.GetPreviousStatus() != serverv1.ServerStatus_SERVER_STATUS_ONLINE {
+		t.Fatalf("unexpected event %v", event)
+	}
+
+	stream.Close()
+	for {
+		if _, err = watch.Recv(); err != nil {
+			break
+		}
+	}
+	reason(t, err, codes.Unavailable)
+}
@@ -0,0 +1,77 @@
+package grpc
+
+import (
+	"errors"
+	"net/http"
+
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// ERROR_DOMAIN qualifies the catalogue codes sent as ErrorInfo reasons.
+const ERROR_DOMAIN = "server-service"
+
+// statusError reports err the way the presenter does over HTTP: the catalogued
+// code as an ErrorInfo reason, field errors as a BadRequest and the wait as a
+// RetryInfo. Anything outside the catalogue is an internal error, so causes
+// never reach the caller. Errors that already carry a status are kept.
+func statusError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) {
+		domainErr = domain.ErrInternalServer
+	}
+
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: domainErr.Code, Domain: ERROR_DOMAIN}}
+	if len(domainErr.Fields) > 0 {
+		violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(domainErr.Fields))
+		for _, field := range domainErr.Fields {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       field.Field,
+				Description: field.Message,
+			})
+		}
+		details = append(details, &errdetails.BadRequest{FieldViolations: violations})
+	}
+	if domainErr.RetryAfter > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(domainErr.RetryAfter)})
+	}
+
+	st := status.New(statusCode(domainErr), domainErr.Message)
+	if detailed, err := st.WithDetails(details...); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// statusCode maps the HTTP status of a catalogued error onto the closest code.
+func statusCode(err *domain.Error) codes.Code {
+	switch err.Status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		if errors.Is(err, domain.ErrServerExist) {
+			return codes.AlreadyExists
+		}
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
@@ -0,0 +1,182 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcuadros/go-defaults"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/http/presenter"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	alert_usecase "github.com/th1enq/ViettelSMS_ServerService/internal/usecase/alert"
+	"go.uber.org/zap"
+)
+
+type AlertController struct {
+	usecase   alert_usecase.UseCase
+	logger    *zap.Logger
+	presenter presenter.Presenter
+}
+
+func NewAlertController(
+	usecase alert_usecase.UseCase,
+	logger *zap.Logger,
+	presenter presenter.Presenter,
+) *AlertController {
+	return &AlertController{
+		usecase:   usecase,
+		logger:    logger,
+		presenter: presenter,
+	}
+}
+
+// ViewAlerts godoc
+// @Summary View alerts
+// @Description List firing and resolved alerts, newest first
+// @Tags alert
+// @Produce json
+// @Param state query string false "Filter by state (firing, resolved)"
+// @Param rule query string false "Filter by rule (offline, group_offline, flapping)"
+// @Param subject query string false "Filter by server ID or group"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /server/alerts [get]
+func (a *AlertController) View(c *gin.Context) {
+	logger := log.LoggerWithContext(c.Request.Context(), a.logger)
+	logger.Info("View alerts request received")
+
+	var (
+		filter     dto.AlertFilterOptions
+		pagination dto.AlertPaginationOptions
+	)
+	defaults.SetDefaults(&pagination)
+
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		logger.Warn("Failed to bind filter options", zap.Error(err))
+		c.Error(bindingError(err))
+		return
+	}
+
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		logger.Warn("Failed to bind pagination options", zap.Error(err))
+		c.Error(bindingError(err))
+		return
+	}
+
+	alerts, total, err := a.usecase.ViewAlerts(c.Request.Context(), filter, pagination)
+	if err != nil {
+		logger.Error("Failed to view alerts", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Alerts retrieved successfully", zap.Int("total", total))
+	a.presenter.Retrived(c, "Alerts retrieved successfully", map[string]interface{}{
+		"alerts": alerts,
+		"total":  total,
+	})
+}
+
+// CreateMaintenanceWindow godoc
+// @Summary Create a maintenance window
+// @Description Suppress new alerts for a server or a group during planned work
+// @Tags alert
+// @Accept json
+// @Produce json
+// @Param window body dto.CreateMaintenanceWindowParams true "Maintenance window"
+// @Success 201 {object} response.APIResponse{data=dto.MaintenanceWindowResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /server/maintenance [post]
+func (a *AlertController) CreateMaintenanceWindow(c *gin.Context) {
+	logger := log.LoggerWithContext(c.Request.Context(), a.logger)
+	logger.Info("Create maintenance window request received")
+
+	var req dto.CreateMaintenanceWindowParams
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		logger.Warn("Failed to bind request body", zap.Error(err))
+		c.Error(bindingError(err))
+		return
+	}
+
+	window, err := a.usecase.CreateMaintenanceWindow(c.Request.Context(), req)
+	if err != nil {
+		logger.Error("Failed to create maintenance window", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Maintenance window created successfully", zap.Uint("window_id", window.ID))
+	a.presenter.Created(c, "Maintenance window created successfully", window)
+}
+
+// ListMaintenanceWindows godoc
+// @Summary List maintenance windows
+// @Description List current and upcoming maintenance windows
+// @Tags alert
+// @Produce json
+// @Param include_expired query bool false "Include windows that have ended"
+// @Success 200 {object} response.APIResponse{data=[]dto.MaintenanceWindowResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /server/maintenance [get]
+func (a *AlertController) ListMaintenanceWindows(c *gin.Context) {
+	logger := log.LoggerWithContext(c.Request.Context(), a.logger)
+	logger.Info("List maintenance windows request received")
+
+	var filter dto.MaintenanceWindowFilterOptions
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		logger.Warn("Failed to bind filter options", zap.Error(err))
+		c.Error(bindingError(err))
+		return
+	}
+
+	windows, err := a.usecase.ListMaintenanceWindows(c.Request.Context(), filter)
+	if err != nil {
+		logger.Error("Failed to list maintenance windows", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	a.presenter.Retrived(c, "Maintenance windows retrieved successfully", windows)
+}
+
+// DeleteMaintenanceWindow godoc
+// @Summary Delete a maintenance window
+// @Tags alert
+// @Produce json
+// @Param id path int true "Maintenance window ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /server/maintenance/{id} [delete]
+func (a *AlertController) DeleteMaintenanceWindow(c *gin.Context) {
+	logger := log.LoggerWithContext(c.Request.Context(), a.logger)
+	logger.Info("Delete maintenance window request received")
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		logger.Warn("Invalid maintenance window ID", zap.String("id", c.Param("id")))
+		c.Error(invalidID(err))
+		return
+	}
+
+	if err := a.usecase.DeleteMaintenanceWindow(c.Request.Context(), uint(id)); err != nil {
+		logger.Warn("Failed to delete maintenance window", zap.Uint64("window_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Maintenance window deleted successfully", zap.Uint64("window_id", id))
+	a.presenter.Deleted(c, "Maintenance window deleted successfully")
+}
@@ -0,0 +1,17 @@
+package controller
+
+import (
+	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/validation"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+)
+
+// bindingError turns a failed bind into a catalogued error, the same way the
+// gRPC API reports invalid messages.
+func bindingError(err error) error {
+	return validation.Error(err)
+}
+
+// invalidID reports a path ID that is not a positive integer.
+func invalidID(err error) error {
+	return domain.NewValidationError(domain.FieldError{Field: "id", Message: "must be a positive integer"}).Wrap(err)
+}
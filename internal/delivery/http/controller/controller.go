@@ -1,8 +1,8 @@
 package controller
 
 import (
-	"errors"
 	"fmt"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -10,6 +10,7 @@ import (
 	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/http/presenter"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
 	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
 	server_usecase "github.com/th1enq/ViettelSMS_ServerService/internal/usecase/server"
 	"go.uber.org/zap"
 )
@@ -39,37 +40,36 @@ func NewController(
 // @Accept json
 // @Produce json
 // @Param server body dto.CreateServerParams true "Server information"
+// @Param Idempotency-Key header string false "Retries with the same key and payload within 24h replay the first response"
 // @Success 201 {object} response.APIResponse{data=dto.ServerResponse}
 // @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
 // @Failure 409 {object} response.APIResponse
+// @Failure 422 {object} response.APIResponse
 // @Failure 500 {object} response.APIResponse
 // @Security BearerAuth
 // @Router /server [post]
 func (s *Controller) Create(c *gin.Context) {
-	s.logger.Info("Create server request received")
+	logger := log.LoggerWithContext(c.Request.Context(), s.logger)
+	logger.Info("Create server request received")
 
 	var req dto.CreateServerParams
 	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
-		s.logger.Warn("Failed to bind request body", zap.Error(err))
-		s.presenter.InvalidRequest(c, "Invalid request body", err)
+		logger.Warn("Failed to bind request body", zap.Error(err))
+		c.Error(bindingError(err))
 		return
 	}
 
-	s.logger.Info("Creating server", zap.Any("request", req))
+	logger.Info("Creating server", zap.Any("request", req))
 
 	server, err := s.usecase.CreateServer(c.Request.Context(), req)
 	if err != nil {
-		if errors.Is(err, domain.ErrServerExist) {
-			s.logger.Warn("Server already exists", zap.String("server_id", req.ServerID), zap.String("server_name", req.ServerName))
-			s.presenter.Conflict(c, "Server already exists", err)
-		} else {
-			s.logger.Error("Failed to create server", zap.Error(err))
-			s.presenter.InternalError(c, "Failed to create server", err)
-		}
+		logger.Warn("Failed to create server", zap.Error(err))
+		c.Error(err)
 		return
 	}
 
-	s.logger.Info("Server created successfully", zap.Any("server", server))
+	logger.Info("Server created successfully", zap.Any("server", server))
 	s.presenter.Created(c, "Server created successfully", server)
 }
 
@@ -81,32 +81,61 @@ func (s *Controller) Create(c *gin.Context) {
 // @Produce json
 // @Param id path string true "Server ID"
 // @Success 200 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
 // @Failure 404 {object} response.APIResponse
 // @Failure 409 {object} response.APIResponse
 // @Failure 500 {object} response.APIResponse
 // @Security BearerAuth
 // @Router /server/{id} [delete]
 func (s *Controller) Delete(c *gin.Context) {
-	s.logger.Info("Delete server request received")
+	logger := log.LoggerWithContext(c.Request.Context(), s.logger)
+	logger.Info("Delete server request received")
 	serverID := c.Param("id")
 
-	s.logger.Info("Deleting server", zap.String("server_id", serverID))
+	logger.Info("Deleting server", zap.String("server_id", serverID))
 
 	if err := s.usecase.DeleteServer(c.Request.Context(), serverID); err != nil {
-		if errors.Is(err, domain.ErrServerNotFound) {
-			s.logger.Warn("Server not found", zap.String("server_id", serverID))
-			s.presenter.NotFound(c, "Server not found", err)
-		} else {
-			s.logger.Error("Failed to delete server", zap.Error(err))
-			s.presenter.InternalError(c, "Failed to delete server", err)
-		}
+		logger.Warn("Failed to delete server", zap.Error(err))
+		c.Error(err)
 		return
 	}
 
-	s.logger.Info("Server deleted successfully", zap.String("server_id", serverID))
+	logger.Info("Server deleted successfully", zap.String("server_id", serverID))
 	s.presenter.Deleted(c, "Server deleted successfully")
 }
 
+// RestoreServer godoc
+// @Summary Restore server
+// @Description Restore a soft-deleted server by ID
+// @Tags server
+// @Accept json
+// @Produce json
+// @Param id path string true "Server ID"
+// @Success 200 {object} response.APIResponse{data=dto.ServerResponse}
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /server/{id}/restore [post]
+func (s *Controller) Restore(c *gin.Context) {
+	logger := log.LoggerWithContext(c.Request.Context(), s.logger)
+	logger.Info("Restore server request received")
+	serverID := c.Param("id")
+
+	logger.Info("Restoring server", zap.String("server_id", serverID))
+
+	server, err := s.usecase.RestoreServer(c.Request.Context(), serverID)
+	if err != nil {
+		logger.Warn("Failed to restore server", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Server restored successfully", zap.String("server_id", serverID))
+	s.presenter.Updated(c, "Server restored successfully", server)
+}
+
 // UpdateServer godoc
 // @Summary Update server
 // @Description Update server information
@@ -117,43 +146,79 @@ func (s *Controller) Delete(c *gin.Context) {
 // @Param updateInfo body dto.UpdateServerParams true "Server update information"
 // @Success 200 {object} response.APIResponse{data=dto.ServerResponse}
 // @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
 // @Failure 404 {object} response.APIResponse
 // @Failure 409 {object} response.APIResponse
 // @Failure 500 {object} response.APIResponse
 // @Security BearerAuth
 // @Router /server/{id} [put]
 func (s *Controller) Update(c *gin.Context) {
-	s.logger.Info("Update server request received")
+	logger := log.LoggerWithContext(c.Request.Context(), s.logger)
+	logger.Info("Update server request received")
 
 	serverID := c.Param("id")
 	var req dto.UpdateServerParams
 	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
-		s.logger.Warn("Failed to bind request body", zap.Error(err))
-		s.presenter.InvalidRequest(c, "Invalid request body", err)
+		logger.Warn("Failed to bind request body", zap.Error(err))
+		c.Error(bindingError(err))
 		return
 	}
 
-	s.logger.Info("Updating server", zap.String("server_id", serverID), zap.Any("request", req))
+	logger.Info("Updating server", zap.String("server_id", serverID), zap.Any("request", req))
 
 	server, err := s.usecase.UpdateServer(c.Request.Context(), serverID, req)
 	if err != nil {
-		if errors.Is(err, domain.ErrServerNotFound) {
-			s.logger.Warn("Server not found", zap.String("server_id", serverID))
-			s.presenter.NotFound(c, "Server not found", err)
-		} else if errors.Is(err, domain.ErrServerExist) {
-			s.logger.Warn("Server already exists", zap.Any("request update", req))
-			s.presenter.Conflict(c, "Server already exists", err)
-		} else {
-			s.logger.Error("Failed to update server", zap.Error(err))
-			s.presenter.InternalError(c, "Failed to update server", err)
-		}
+		logger.Warn("Failed to update server", zap.Error(err))
+		c.Error(err)
 		return
 	}
 
-	s.logger.Info("Server updated successfully", zap.String("server_id", serverID))
+	logger.Info("Server updated successfully", zap.String("server_id", serverID))
 	s.presenter.Updated(c, "Server updated successfully", server)
 }
 
+// PatchServer godoc
+// @Summary Patch server
+// @Description Partially update a server with JSON merge patch (RFC 7396) semantics; null clears location, os, group and labels
+// @Tags server
+// @Accept application/merge-patch+json
+// @Accept json
+// @Produce json
+// @Param id path string true "Server ID"
+// @Param patch body dto.PatchServerParams true "Merge patch"
+// @Success 200 {object} response.APIResponse{data=dto.ServerResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /server/{id} [patch]
+func (s *Controller) Patch(c *gin.Context) {
+	logger := log.LoggerWithContext(c.Request.Context(), s.logger)
+	logger.Info("Patch server request received")
+
+	serverID := c.Param("id")
+	var req dto.PatchServerParams
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		logger.Warn("Failed to bind request body", zap.Error(err))
+		c.Error(bindingError(err))
+		return
+	}
+
+	logger.Info("Patching server", zap.String("server_id", serverID))
+
+	server, err := s.usecase.PatchServer(c.Request.Context(), serverID, req)
+	if err != nil {
+		logger.Warn("Failed to patch server", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Server patched successfully", zap.String("server_id", serverID))
+	s.presenter.Updated(c, "Server patched successfully", server)
+}
+
 // ViewServers godoc
 // @Summary View servers
 // @Description Get list of servers with optional filters and pagination
@@ -162,6 +227,7 @@ func (s *Controller) Update(c *gin.Context) {
 // @Produce json
 // @Param server_name query string false "Filter by server name"
 // @Param status query string false "Filter by status"
+// @Param include_deleted query bool false "Include soft-deleted servers"
 // @Param page query int false "Page number"
 // @Param page_size query int false "Page size"
 // @Param sort_by query string false "Sort field"
@@ -172,7 +238,8 @@ func (s *Controller) Update(c *gin.Context) {
 // @Security BearerAuth
 // @Router /server [get]
 func (s *Controller) View(c *gin.Context) {
-	s.logger.Info("View server request received")
+	logger := log.LoggerWithContext(c.Request.Context(), s.logger)
+	logger.Info("View server request received")
 
 	var (
 		filter     dto.ServerFilterOptions
@@ -181,82 +248,142 @@ func (s *Controller) View(c *gin.Context) {
 	defaults.SetDefaults(&pagination)
 
 	if err := c.ShouldBindQuery(&filter); err != nil {
-		s.logger.Warn("Failed to bind filter options", zap.Error(err))
-		s.presenter.InvalidRequest(c, "Invalid filter options", err)
+		logger.Warn("Failed to bind filter options", zap.Error(err))
+		c.Error(bindingError(err))
 		return
 	}
 
 	if err := c.ShouldBindQuery(&pagination); err != nil {
-		s.logger.Warn("Failed to bind pagination options", zap.Error(err))
-		s.presenter.InvalidRequest(c, "Invalid pagination options", err)
+		logger.Warn("Failed to bind pagination options", zap.Error(err))
+		c.Error(bindingError(err))
 		return
 	}
 
-	s.logger.Info("Retrieving servers", zap.Any("filter", filter), zap.Any("pagination", pagination))
+	logger.Info("Retrieving servers", zap.Any("filter", filter), zap.Any("pagination", pagination))
 
 	server, total, err := s.usecase.ViewServer(c.Request.Context(), filter, pagination)
 	if err != nil {
-		s.logger.Error("Failed to view servers", zap.Error(err))
-		s.presenter.InternalError(c, "Failed to view servers", err)
+		logger.Error("Failed to view servers", zap.Error(err))
+		c.Error(err)
 		return
 	}
-	s.logger.Info("Servers retrieved successfully", zap.Int("total", total))
+	logger.Info("Servers retrieved successfully", zap.Int("total", total))
 	s.presenter.Retrived(c, "Servers retrieved successfully", map[string]interface{}{
 		"servers": server,
 		"total":   total,
 	})
 }
 
+// BulkServers godoc
+// @Summary Bulk operation on servers
+// @Description Delete, patch or set the status of many servers, selected by IDs or by filter, in one transaction
+// @Tags server
+// @Accept json
+// @Produce json
+// @Param bulk body dto.BulkOperationParams true "Bulk operation"
+// @Success 200 {object} response.APIResponse{data=dto.BulkOperationResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 403 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /server/bulk [post]
+func (s *Controller) Bulk(c *gin.Context) {
+	logger := log.LoggerWithContext(c.Request.Context(), s.logger)
+	logger.Info("Bulk server request received")
+
+	var req dto.BulkOperationParams
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		logger.Warn("Failed to bind request body", zap.Error(err))
+		c.Error(bindingError(err))
+		return
+	}
+
+	result, err := s.usecase.BulkOperation(c.Request.Context(), req)
+	if err != nil {
+		logger.Warn("Failed to run bulk operation", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Bulk operation completed", zap.Int("matched", result.Matched), zap.Int("applied", result.Applied))
+	s.presenter.Updated(c, "Bulk operation completed", result)
+}
+
 // ImportServers godoc
 // @Summary Import servers from Excel file
-// @Description Import multiple servers from an Excel file
+// @Description Import multiple servers from an Excel file laid out like the workbook from GET /server/import/template
 // @Tags server
 // @Accept multipart/form-data
 // @Produce json
 // @Param file formData file true "Excel file"
+// @Param Idempotency-Key header string false "Retries with the same key and file within 24h replay the first response"
 // @Success 200 {object} response.APIResponse{data=dto.ImportServerResponse}
 // @Failure 400 {object} response.APIResponse
+// @Failure 409 {object} response.APIResponse
+// @Failure 422 {object} response.APIResponse
+// @Failure 429 {object} response.APIResponse
 // @Failure 500 {object} response.APIResponse
 // @Security BearerAuth
 // @Router /server/import [post]
 func (s *Controller) Import(c *gin.Context) {
-	s.logger.Info("Import server request received")
+	logger := log.LoggerWithContext(c.Request.Context(), s.logger)
+	logger.Info("Import server request received")
 
 	file, err := c.FormFile("file")
 	if err != nil {
-		s.logger.Warn("Failed to get file from request", zap.Error(err))
-		s.presenter.InvalidRequest(c, "Invalid file", err)
+		logger.Warn("Failed to get file from request", zap.Error(err))
+		c.Error(domain.ErrInvalidFile.Wrap(err))
 		return
 	}
 
 	filePath := fmt.Sprintf("/tmp/%s_%s", uuid.New().String(), file.Filename)
 	if err := c.SaveUploadedFile(file, filePath); err != nil {
-		s.logger.Error("Failed to save uploaded file", zap.String("file_path", filePath), zap.Error(err))
-		s.presenter.InternalError(c, "Failed to save uploaded file", err)
+		logger.Error("Failed to save uploaded file", zap.String("file_path", filePath), zap.Error(err))
+		c.Error(domain.ErrInternalServer.Wrap(err))
 		return
 	}
 
-	s.logger.Info("Importing server from file", zap.String("file_path", filePath))
+	logger.Info("Importing server from file", zap.String("file_path", filePath))
 
 	result, err := s.usecase.ImportServer(c.Request.Context(), filePath)
 	if err != nil {
-		if errors.Is(err, domain.ErrInvalidFile) {
-			s.logger.Warn("Invalid file format", zap.Error(err))
-			s.presenter.InvalidRequest(c, "Invalid file format", err)
-		} else {
-			s.logger.Error("Failed to import server", zap.Error(err))
-			s.presenter.InternalError(c, "Failed to import server", err)
-		}
+		logger.Warn("Failed to import server", zap.Error(err))
+		c.Error(err)
 		return
 	}
 
-	s.logger.Info("Server imported successfully", zap.String("file_name", file.Filename))
+	logger.Info("Server imported successfully", zap.String("file_name", file.Filename))
 	s.presenter.Imported(c, "Server imported successfully", result)
 }
 
+// ImportTemplate godoc
+// @Summary Download the import template
+// @Description Download an Excel workbook with the headers, example rows and input validation expected by the import, plus an instructions sheet
+// @Tags servers
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Success 200 {file} binary
+// @Failure 500 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /server/import/template [get]
+func (s *Controller) ImportTemplate(c *gin.Context) {
+	logger := log.LoggerWithContext(c.Request.Context(), s.logger)
+	logger.Info("Import template request received")
+
+	content, err := s.usecase.ImportTemplate(c.Request.Context())
+	if err != nil {
+		logger.Error("Failed to build import template", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	c.Header("Content-Description", "File Transfer")
+	c.Header("Content-Disposition", "attachment; filename=servers_import_template.xlsx")
+	c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", content)
+}
+
 // ExportServers godoc
 // @Summary Export servers to Excel file
-// @Description Export servers to an Excel file with optional filters
+// @Description Export servers to an Excel file with optional filters. The Servers sheet can be edited and imported again, except for its read-only status column; a Summary sheet counts the servers by status and location.
 // @Tags servers
 // @Accept json
 // @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
@@ -268,11 +395,13 @@ func (s *Controller) Import(c *gin.Context) {
 // @Param sort_order query string false "Sort order" default(asc)
 // @Success 200 {file} binary
 // @Failure 400 {object} response.APIResponse
+// @Failure 429 {object} response.APIResponse
 // @Failure 500 {object} response.APIResponse
 // @Security BearerAuth
 // @Router /server/export [get]
 func (s *Controller) Export(c *gin.Context) {
-	s.logger.Info("Export server request received")
+	logger := log.LoggerWithContext(c.Request.Context(), s.logger)
+	logger.Info("Export server request received")
 
 	var (
 		filter     dto.ServerFilterOptions
@@ -281,27 +410,27 @@ func (s *Controller) Export(c *gin.Context) {
 	defaults.SetDefaults(&pagination)
 
 	if err := c.ShouldBindQuery(&filter); err != nil {
-		s.logger.Warn("Failed to bind filter options", zap.Error(err))
-		s.presenter.InvalidRequest(c, "Invalid filter options", err)
+		logger.Warn("Failed to bind filter options", zap.Error(err))
+		c.Error(bindingError(err))
 		return
 	}
 
 	if err := c.ShouldBindQuery(&pagination); err != nil {
-		s.logger.Warn("Failed to bind pagination options", zap.Error(err))
-		s.presenter.InvalidRequest(c, "Invalid pagination options", err)
+		logger.Warn("Failed to bind pagination options", zap.Error(err))
+		c.Error(bindingError(err))
 		return
 	}
 
-	s.logger.Info("Exporting servers", zap.Any("filter", filter), zap.Any("pagination", pagination))
+	logger.Info("Exporting servers", zap.Any("filter", filter), zap.Any("pagination", pagination))
 
 	filePath, err := s.usecase.ExportServer(c.Request.Context(), filter, pagination)
 	if err != nil {
-		s.logger.Error("Failed to export servers", zap.Error(err))
-		s.presenter.InternalError(c, "Failed to export servers", err)
+		logger.Error("Failed to export servers", zap.Error(err))
+		c.Error(err)
 		return
 	}
 
-	s.logger.Info("Servers exported successfully", zap.String("file_path", filePath))
+	logger.Info("Servers exported successfully", zap.String("file_path", filePath))
 	c.Header("Content-Description", "File Transfer")
 	c.Header("Content-Transfer-Encoding", "binary")
 	c.Header("Content-Disposition", "attachment; filename=servers.xlsx")
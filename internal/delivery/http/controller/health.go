@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/health"
+	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	"go.uber.org/zap"
+)
+
+type HealthController struct {
+	health health.Service
+	logger *zap.Logger
+}
+
+func NewHealthController(health health.Service, logger *zap.Logger) *HealthController {
+	return &HealthController{
+		health: health,
+		logger: logger,
+	}
+}
+
+// Live godoc
+// @Summary Liveness probe
+// @Description Report that the process is up. No dependency is checked.
+// @Tags health
+// @Produce json
+// @Success 200 {object} dto.HealthReport
+// @Router /health/live [get]
+func (h *HealthController) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, h.health.Live())
+}
+
+// Ready godoc
+// @Summary Readiness probe
+// @Description Check the database, the Kafka brokers and the status consumer, with the latency of each check
+// @Tags health
+// @Produce json
+// @Success 200 {object} dto.HealthReport
+// @Failure 503 {object} dto.HealthReport
+// @Router /health/ready [get]
+func (h *HealthController) Ready(c *gin.Context) {
+	report := h.health.Ready(c.Request.Context())
+	if report.Status != dto.HealthStatusOK {
+		log.LoggerWithContext(c.Request.Context(), h.logger).Warn("Readiness check failed", zap.Any("components", report.Components))
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
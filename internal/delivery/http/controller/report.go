@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/http/presenter"
+	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	report_usecase "github.com/th1enq/ViettelSMS_ServerService/internal/usecase/report"
+	"go.uber.org/zap"
+)
+
+type ReportController struct {
+	usecase   report_usecase.UseCase
+	logger    *zap.Logger
+	presenter presenter.Presenter
+}
+
+func NewReportController(
+	usecase report_usecase.UseCase,
+	logger *zap.Logger,
+	presenter presenter.Presenter,
+) *ReportController {
+	return &ReportController{
+		usecase:   usecase,
+		logger:    logger,
+		presenter: presenter,
+	}
+}
+
+// ListReports godoc
+// @Summary List generated reports
+// @Description List the scheduled inventory, status and uptime reports of the tenant, newest first
+// @Tags report
+// @Produce json
+// @Success 200 {object} response.APIResponse{data=[]dto.ReportResponse}
+// @Failure 403 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /server/reports [get]
+func (r *ReportController) List(c *gin.Context) {
+	logger := log.LoggerWithContext(c.Request.Context(), r.logger)
+	logger.Info("List reports request received")
+
+	reports, err := r.usecase.ListReports(c.Request.Context())
+	if err != nil {
+		logger.Warn("Failed to list reports", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	r.presenter.Retrived(c, "Reports retrieved successfully", reports)
+}
+
+// DownloadReport godoc
+// @Summary Download a generated report
+// @Tags report
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param name path string true "Report name"
+// @Success 200 {file} binary
+// @Failure 403 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /server/reports/{name} [get]
+func (r *ReportController) Download(c *gin.Context) {
+	logger := log.LoggerWithContext(c.Request.Context(), r.logger)
+	logger.Info("Download report request received")
+
+	name := c.Param("name")
+	content, err := r.usecase.ReportContent(c.Request.Context(), name)
+	if err != nil {
+		logger.Warn("Failed to download report", zap.String("name", name), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	c.Header("Content-Description", "File Transfer")
+	c.Header("Content-Transfer-Encoding", "binary")
+	c.Header("Content-Disposition", "attachment; filename="+name)
+	c.Data(http.StatusOK, report_usecase.XLSX_CONTENT_TYPE, content)
+}
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/http/presenter"
+	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	stats_usecase "github.com/th1enq/ViettelSMS_ServerService/internal/usecase/stats"
+	"go.uber.org/zap"
+)
+
+type StatsController struct {
+	usecase   stats_usecase.UseCase
+	logger    *zap.Logger
+	presenter presenter.Presenter
+}
+
+func NewStatsController(
+	usecase stats_usecase.UseCase,
+	logger *zap.Logger,
+	presenter presenter.Presenter,
+) *StatsController {
+	return &StatsController{
+		usecase:   usecase,
+		logger:    logger,
+		presenter: presenter,
+	}
+}
+
+// ViewStats godoc
+// @Summary View server statistics
+// @Description Counts of the visible servers by status, location, OS and interval bucket, servers created per day, servers that changed status in the last hour and the servers changing status most often. Results may be a few seconds old.
+// @Tags server
+// @Produce json
+// @Success 200 {object} response.APIResponse{data=dto.ServerStats}
+// @Failure 500 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /server/stats [get]
+func (s *StatsController) View(c *gin.Context) {
+	logger := log.LoggerWithContext(c.Request.Context(), s.logger)
+	logger.Info("View server stats request received")
+
+	stats, err := s.usecase.ViewStats(c.Request.Context())
+	if err != nil {
+		logger.Error("Failed to view server stats", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Server stats retrieved successfully")
+	s.presenter.Retrived(c, "Server stats retrieved successfully", stats)
+}
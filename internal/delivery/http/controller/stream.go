@@ -0,0 +1,230 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/http/presenter"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	stream_usecase "github.com/th1enq/ViettelSMS_ServerService/internal/usecase/stream"
+	"go.uber.org/zap"
+)
+
+// STREAM_WRITE_TIMEOUT bounds a single write to a WebSocket client.
+const STREAM_WRITE_TIMEOUT = 10 * time.Second
+
+type StreamController struct {
+	usecase   stream_usecase.UseCase
+	heartbeat time.Duration
+	upgrader  websocket.Upgrader
+	logger    *zap.Logger
+	presenter presenter.Presenter
+}
+
+func NewStreamController(
+	usecase stream_usecase.UseCase,
+	heartbeat time.Duration,
+	logger *zap.Logger,
+	presenter presenter.Presenter,
+) *StreamController {
+	return &StreamController{
+		usecase:   usecase,
+		heartbeat: heartbeat,
+		upgrader: websocket.Upgrader{
+			// Credentials travel in headers rather than cookies, so a foreign
+			// origin gains nothing a direct client could not do.
+			CheckOrigin: func(*http.Request) bool { return true },
+		},
+		logger:    logger,
+		presenter: presenter,
+	}
+}
+
+// heartbeatMessage is the payload of a stream.heartbeat or stream.resync message.
+type heartbeatMessage struct {
+	Type dto.ServerEventType `json:"type"`
+	Time time.Time           `json:"time"`
+}
+
+// Stream godoc
+// @Summary Stream server events
+// @Description Server-Sent Events of servers being created, updated, deleted, restored or changing status. Each event is named after its type and carries a server event as data; a stream.heartbeat event is sent while idle and stream.resync before a subscriber that fell behind is disconnected. include_deleted has no effect: deletions are always sent as server.deleted.
+// @Tags server
+// @Produce text/event-stream
+// @Param server_name query string false "Filter by server name"
+// @Param status query string false "Filter by status"
+// @Success 200 {object} dto.ServerEvent
+// @Failure 400 {object} response.APIResponse
+// @Failure 503 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /server/stream [get]
+func (s *StreamController) SSE(c *gin.Context) {
+	ctx := c.Request.Context()
+	logger := log.LoggerWithContext(ctx, s.logger)
+
+	subscription, ok := s.subscribe(c)
+	if !ok {
+		return
+	}
+	defer subscription.Unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+	logger.Info("Server event stream opened")
+
+	heartbeat := time.NewTicker(s.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		var err error
+		select {
+		case <-ctx.Done():
+			logger.Info("Server event stream closed by client")
+			return
+		case now := <-heartbeat.C:
+			err = writeSSE(c, "", dto.StreamHeartbeat, heartbeatMessage{Type: dto.StreamHeartbeat, Time: now})
+		case event, ok := <-subscription.Events:
+			if !ok {
+				if subscription.Lagged() {
+					_ = writeSSE(c, "", dto.StreamResync, heartbeatMessage{Type: dto.StreamResync, Time: time.Now()})
+				}
+				logger.Info("Server event stream ended", zap.Bool("lagged", subscription.Lagged()))
+				return
+			}
+			err = writeSSE(c, event.ID, event.Type, event)
+		}
+		if err != nil {
+			logger.Info("Server event stream write failed", zap.Error(err))
+			return
+		}
+	}
+}
+
+// WebSocket godoc
+// @Summary Stream server events over WebSocket
+// @Description WebSocket equivalent of /server/stream. Each text message is a JSON server event; the connection is kept alive with ping frames and closed by the server after a stream.resync message when the client falls behind.
+// @Tags server
+// @Param server_name query string false "Filter by server name"
+// @Param status query string false "Filter by status"
+// @Success 101 {object} dto.ServerEvent
+// @Failure 400 {object} response.APIResponse
+// @Failure 503 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /server/stream/ws [get]
+func (s *StreamController) WebSocket(c *gin.Context) {
+	logger := log.LoggerWithContext(c.Request.Context(), s.logger)
+
+	subscription, ok := s.subscribe(c)
+	if !ok {
+		return
+	}
+	defer subscription.Unsubscribe()
+
+	conn, err := s.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already answered the request.
+		logger.Warn("Failed to upgrade to websocket", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+	logger.Info("Server event websocket opened")
+
+	// The client is not expected to send anything; reading handles pongs and
+	// notices when the peer goes away or stops answering pings.
+	gone := make(chan struct{})
+	conn.SetReadLimit(512)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * s.heartbeat))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * s.heartbeat))
+	})
+	go func() {
+		defer close(gone)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(s.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		var err error
+		select {
+		case <-gone:
+			logger.Info("Server event websocket closed by client")
+			return
+		case <-heartbeat.C:
+			err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(STREAM_WRITE_TIMEOUT))
+		case event, ok := <-subscription.Events:
+			if !ok {
+				code, reason := websocket.CloseGoingAway, "server shutting down"
+				if subscription.Lagged() {
+					_ = writeWebSocket(conn, heartbeatMessage{Type: dto.StreamResync, Time: time.Now()})
+					code, reason = websocket.CloseTryAgainLater, "subscriber fell behind"
+				}
+				_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(STREAM_WRITE_TIMEOUT))
+				logger.Info("Server event websocket ended", zap.Bool("lagged", subscription.Lagged()))
+				return
+			}
+			err = writeWebSocket(conn, event)
+		}
+		if err != nil {
+			logger.Info("Server event websocket write failed", zap.Error(err))
+			return
+		}
+	}
+}
+
+// subscribe binds the filter and registers the subscription, answering the
+// request itself on failure.
+func (s *StreamController) subscribe(c *gin.Context) (*stream_usecase.Subscription, bool) {
+	logger := log.LoggerWithContext(c.Request.Context(), s.logger)
+
+	var filter dto.ServerFilterOptions
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		logger.Warn("Failed to bind filter options", zap.Error(err))
+		c.Error(bindingError(err))
+		return nil, false
+	}
+
+	subscription, err := s.usecase.Subscribe(c.Request.Context(), filter)
+	if err != nil {
+		logger.Warn("Failed to subscribe to server events", zap.Error(err))
+		c.Error(err)
+		return nil, false
+	}
+	return subscription, true
+}
+
+func writeSSE(c *gin.Context, id string, event dto.ServerEventType, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if id != "" {
+		if _, err := fmt.Fprintf(c.Writer, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	c.Writer.Flush()
+	return nil
+}
+
+func writeWebSocket(conn *websocket.Conn, data interface{}) error {
+	_ = conn.SetWriteDeadline(time.Now().Add(STREAM_WRITE_TIMEOUT))
+	return conn.WriteJSON(data)
+}
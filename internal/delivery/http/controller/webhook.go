@@ -0,0 +1,231 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/http/presenter"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	webhook_usecase "github.com/th1enq/ViettelSMS_ServerService/internal/usecase/webhook"
+	"go.uber.org/zap"
+)
+
+type WebhookController struct {
+	usecase   webhook_usecase.UseCase
+	logger    *zap.Logger
+	presenter presenter.Presenter
+}
+
+func NewWebhookController(
+	usecase webhook_usecase.UseCase,
+	logger *zap.Logger,
+	presenter presenter.Presenter,
+) *WebhookController {
+	return &WebhookController{
+		usecase:   usecase,
+		logger:    logger,
+		presenter: presenter,
+	}
+}
+
+// CreateWebhook godoc
+// @Summary Create a webhook
+// @Description Subscribe a URL to server status changes, optionally filtered by server IDs, groups and labels. Payloads are signed with HMAC-SHA256 in the X-Webhook-Signature header; the secret is only returned here.
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Param webhook body dto.CreateWebhookParams true "Webhook"
+// @Success 201 {object} response.APIResponse{data=dto.WebhookResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /server/webhooks [post]
+func (w *WebhookController) Create(c *gin.Context) {
+	logger := log.LoggerWithContext(c.Request.Context(), w.logger)
+	logger.Info("Create webhook request received")
+
+	var req dto.CreateWebhookParams
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		logger.Warn("Failed to bind request body", zap.Error(err))
+		c.Error(bindingError(err))
+		return
+	}
+
+	webhook, err := w.usecase.CreateWebhook(c.Request.Context(), req)
+	if err != nil {
+		logger.Error("Failed to create webhook", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Webhook created successfully", zap.Uint("webhook_id", webhook.ID))
+	w.presenter.Created(c, "Webhook created successfully", webhook)
+}
+
+// ListWebhooks godoc
+// @Summary List webhooks
+// @Description List the webhooks of the caller's tenant
+// @Tags webhook
+// @Produce json
+// @Success 200 {object} response.APIResponse{data=[]dto.WebhookResponse}
+// @Failure 500 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /server/webhooks [get]
+func (w *WebhookController) List(c *gin.Context) {
+	logger := log.LoggerWithContext(c.Request.Context(), w.logger)
+	logger.Info("List webhooks request received")
+
+	webhooks, err := w.usecase.ListWebhooks(c.Request.Context())
+	if err != nil {
+		logger.Error("Failed to list webhooks", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	w.presenter.Retrived(c, "Webhooks retrieved successfully", webhooks)
+}
+
+// GetWebhook godoc
+// @Summary Get a webhook
+// @Tags webhook
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} response.APIResponse{data=dto.WebhookResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /server/webhooks/{id} [get]
+func (w *WebhookController) Get(c *gin.Context) {
+	logger := log.LoggerWithContext(c.Request.Context(), w.logger)
+	logger.Info("Get webhook request received")
+
+	id, ok := w.webhookID(c)
+	if !ok {
+		return
+	}
+
+	webhook, err := w.usecase.GetWebhook(c.Request.Context(), id)
+	if err != nil {
+		w.fail(c, "Failed to get webhook", err)
+		return
+	}
+
+	w.presenter.Retrived(c, "Webhook retrieved successfully", webhook)
+}
+
+// UpdateWebhook godoc
+// @Summary Update a webhook
+// @Description Replace the given fields. Re-enabling a disabled webhook resets its failure count.
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param webhook body dto.UpdateWebhookParams true "Webhook update"
+// @Success 200 {object} response.APIResponse{data=dto.WebhookResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /server/webhooks/{id} [put]
+func (w *WebhookController) Update(c *gin.Context) {
+	logger := log.LoggerWithContext(c.Request.Context(), w.logger)
+	logger.Info("Update webhook request received")
+
+	id, ok := w.webhookID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateWebhookParams
+	if err := c.ShouldBindBodyWithJSON(&req); err != nil {
+		logger.Warn("Failed to bind request body", zap.Error(err))
+		c.Error(bindingError(err))
+		return
+	}
+
+	webhook, err := w.usecase.UpdateWebhook(c.Request.Context(), id, req)
+	if err != nil {
+		w.fail(c, "Failed to update webhook", err)
+		return
+	}
+
+	logger.Info("Webhook updated successfully", zap.Uint("webhook_id", id))
+	w.presenter.Updated(c, "Webhook updated successfully", webhook)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook
+// @Description Delete a webhook and its delivery log
+// @Tags webhook
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} response.APIResponse
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /server/webhooks/{id} [delete]
+func (w *WebhookController) Delete(c *gin.Context) {
+	logger := log.LoggerWithContext(c.Request.Context(), w.logger)
+	logger.Info("Delete webhook request received")
+
+	id, ok := w.webhookID(c)
+	if !ok {
+		return
+	}
+
+	if err := w.usecase.DeleteWebhook(c.Request.Context(), id); err != nil {
+		w.fail(c, "Failed to delete webhook", err)
+		return
+	}
+
+	logger.Info("Webhook deleted successfully", zap.Uint("webhook_id", id))
+	w.presenter.Deleted(c, "Webhook deleted successfully")
+}
+
+// ListWebhookDeliveries godoc
+// @Summary List webhook deliveries
+// @Description List the most recent delivery attempts of a webhook, newest first
+// @Tags webhook
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} response.APIResponse{data=[]dto.WebhookDeliveryResponse}
+// @Failure 400 {object} response.APIResponse
+// @Failure 404 {object} response.APIResponse
+// @Failure 500 {object} response.APIResponse
+// @Security BearerAuth
+// @Router /server/webhooks/{id}/deliveries [get]
+func (w *WebhookController) Deliveries(c *gin.Context) {
+	logger := log.LoggerWithContext(c.Request.Context(), w.logger)
+	logger.Info("List webhook deliveries request received")
+
+	id, ok := w.webhookID(c)
+	if !ok {
+		return
+	}
+
+	deliveries, err := w.usecase.ListDeliveries(c.Request.Context(), id)
+	if err != nil {
+		w.fail(c, "Failed to list webhook deliveries", err)
+		return
+	}
+
+	w.presenter.Retrived(c, "Webhook deliveries retrieved successfully", deliveries)
+}
+
+func (w *WebhookController) webhookID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		log.LoggerWithContext(c.Request.Context(), w.logger).Warn("Invalid webhook ID", zap.String("id", c.Param("id")))
+		c.Error(invalidID(err))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func (w *WebhookController) fail(c *gin.Context, message string, err error) {
+	log.LoggerWithContext(c.Request.Context(), w.logger).Warn(message, zap.String("id", c.Param("id")), zap.Error(err))
+	c.Error(err)
+}
@@ -1,90 +1,111 @@
 package middleware
 
 import (
-	"fmt"
-	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
-	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/http/presenter"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/auth"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/authz"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
 )
 
 type JWTMiddleware interface {
 	RequireAuth() gin.HandlerFunc
 	RequireScope(requireScope string) gin.HandlerFunc
+	// RequireBulkScope requires the scope mapped to the operation named in a
+	// bulk request body.
+	RequireBulkScope(scopes map[dto.BulkOperation]string) gin.HandlerFunc
 }
 
+// jwtMiddleware reports failures with c.Error; the Errors middleware writes them.
 type jwtMiddleware struct {
-	presenter presenter.Presenter
-	jwtSecret []byte
+	authenticator auth.Authenticator
 }
 
 func NewJWTMiddleware(
-	presenter presenter.Presenter,
-	jwtSecret []byte,
+	jwt config.JWT,
+	apiKeys config.APIKey,
+	tls config.TLS,
 ) JWTMiddleware {
 	return &jwtMiddleware{
-		presenter: presenter,
-		jwtSecret: jwtSecret,
+		authenticator: auth.NewAuthenticator(jwt, apiKeys, tls),
 	}
 }
 
 func (s *jwtMiddleware) RequireAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		apiKey := c.GetHeader("X-API-Key")
-		if apiKey != "" {
-			if apiKey == "<API_KEY>" {
-				c.Next()
-				return
-			}
-		}
-
-		token := s.extractTokenFromHeader(c)
-		if token == "" {
-			s.presenter.Unauthorized(c, "Missing or malformed token", fmt.Errorf("missing or malformed token"))
-			c.Abort()
-			return
-		}
-
-		claims, err := s.validateToken(token)
+		identity, err := s.authenticator.Authenticate(auth.Credentials{
+			APIKey:     c.GetHeader("X-API-Key"),
+			Token:      s.extractTokenFromHeader(c),
+			CommonName: s.clientCommonName(c),
+		})
 		if err != nil {
-			s.presenter.Unauthorized(c, "Invalid token", err)
-			c.Abort()
-			return
-		}
-
-		if claims.Blocked {
-			s.presenter.Forbidden(c, "User is blocked", fmt.Errorf("user is blocked"))
+			c.Error(err)
 			c.Abort()
 			return
 		}
 
-		c.Set("userID", claims.Sub)
-		c.Set("scopes", claims.Scopes)
-
+		s.authenticate(c, identity)
 		c.Next()
 	}
 }
 
+// authenticate stores the caller identity on both the gin and the request context.
+func (s *jwtMiddleware) authenticate(c *gin.Context, identity *auth.Identity) {
+	ctx := auth.NewContext(c.Request.Context(), identity)
+
+	c.Set("clientID", identity.ClientID)
+	c.Set("userID", identity.UserID)
+	c.Set("scopes", identity.Principal.Scopes)
+	c.Set("principal", identity.Principal)
+	c.Set("tenantID", tenant.FromContext(ctx))
+	c.Request = c.Request.WithContext(ctx)
+}
+
 func (s *jwtMiddleware) RequireScope(requireScope string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		scopes, exists := c.Get("scopes")
-		if !exists {
-			s.presenter.Forbidden(c, "Insufficient scope", fmt.Errorf("insufficient scope"))
-			c.Abort()
+		principal := authz.FromContext(c.Request.Context())
+		if principal.HasScope(requireScope) {
+			c.Next()
 			return
 		}
 
-		if slices.Contains(scopes.([]string), requireScope) {
+		c.Error(domain.ErrInsufficientScope)
+		c.Abort()
+	}
+}
+
+// RequireBulkScope peeks at the operation of a bulk request and requires its
+// scope. The body stays cached for the handler; a body without a known
+// operation is passed on so binding reports it as invalid.
+func (s *jwtMiddleware) RequireBulkScope(scopes map[dto.BulkOperation]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body struct {
+			Operation dto.BulkOperation `json:"operation"`
+		}
+		if err := c.ShouldBindBodyWithJSON(&body); err != nil {
 			c.Next()
 			return
 		}
+		requireScope, ok := scopes[body.Operation]
+		if !ok {
+			c.Next()
+			return
+		}
+		s.RequireScope(requireScope)(c)
+	}
+}
 
-		s.presenter.Forbidden(c, "Insufficient scope", fmt.Errorf("insufficient scope"))
-		c.Abort()
+// clientCommonName returns the common name of the verified client certificate,
+// or "" when the connection carries none.
+func (s *jwtMiddleware) clientCommonName(c *gin.Context) string {
+	if c.Request.TLS == nil || len(c.Request.TLS.VerifiedChains) == 0 || len(c.Request.TLS.VerifiedChains[0]) == 0 {
+		return ""
 	}
+	return c.Request.TLS.VerifiedChains[0][0].Subject.CommonName
 }
 
 func (s *jwtMiddleware) extractTokenFromHeader(c *gin.Context) string {
@@ -99,22 +120,3 @@ func (s *jwtMiddleware) extractTokenFromHeader(c *gin.Context) string {
 
 	return strings.TrimPrefix(authHeader, "Bearer ")
 }
-
-func (s *jwtMiddleware) validateToken(token string) (*dto.Claims, error) {
-	accessToken, err := jwt.ParseWithClaims(token, &dto.Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected token signing method: %v", token.Header["alg"])
-		}
-		return s.jwtSecret, nil
-	})
-
-	if err != nil {
-		return nil, fmt.Errorf("invalid token: %w", err)
-	}
-
-	if claims, ok := accessToken.Claims.(*dto.Claims); ok && accessToken.Valid {
-		return claims, nil
-	}
-
-	return nil, fmt.Errorf("invalid token claims: %w", err)
-}
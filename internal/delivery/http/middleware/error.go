@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/http/presenter"
+	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	"go.uber.org/zap"
+)
+
+// Errors writes the last error handlers attached with c.Error through the
+// presenter, so every failure leaves the API in the same envelope. It must run
+// before the middleware and handlers that report errors.
+func Errors(presenter presenter.Presenter, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 {
+			return
+		}
+		err := c.Errors.Last().Err
+		if c.Writer.Written() {
+			log.LoggerWithContext(c.Request.Context(), logger).
+				Warn("Error raised after the response was written", zap.Error(err))
+			return
+		}
+		presenter.Error(c, err)
+	}
+}
@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/http/presenter"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/response"
+	"go.uber.org/zap"
+)
+
+func serveError(t *testing.T, err error) (*httptest.ResponseRecorder, response.APIResponse) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Errors(presenter.NewPresenter(), zap.NewNop()))
+	router.GET("/", func(c *gin.Context) {
+		c.Error(err)
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var body response.APIResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	return rec, body
+}
+
+func TestErrors_MapsCatalogue(t *testing.T) {
+	cause := fmt.Errorf("pq: relation \"servers\" does not exist")
+	cases := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"not found", domain.ErrServerNotFound, http.StatusNotFound, response.CodeNotFound},
+		{"wrapped conflict", fmt.Errorf("create: %w", domain.ErrServerExist), http.StatusConflict, response.CodeConflict},
+		{"database", domain.ErrDatabase.Wrap(cause), http.StatusInternalServerError, response.CodeDatabaseError},
+		{"unknown", cause, http.StatusInternalServerError, response.CodeInternalServerError},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec, body := serveError(t, tc.err)
+			if rec.Code != tc.status || body.Code != tc.code || body.Error.Code != tc.code {
+				t.Fatalf("got %d %s, want %d %s", rec.Code, body.Code, tc.status, tc.code)
+			}
+			if strings.Contains(rec.Body.String(), "pq:") {
+				t.Fatalf("cause leaked into the response: %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestErrors_ValidationFields(t *testing.T) {
+	err := domain.NewValidationError(domain.FieldError{Field: "ipv4", Message: "must be a valid IPv4 address"}).
+		Wrap(errors.New("Key: 'CreateServerParams.IPv4' Error:Field validation for 'IPv4' failed on the 'ipv4' tag"))
+	if !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("validation error does not match its kind")
+	}
+
+	rec, body := serveError(t, err)
+	if rec.Code != http.StatusBadRequest || body.Code != response.CodeValidationError {
+		t.Fatalf("got %d %s", rec.Code, body.Code)
+	}
+	fields, _ := body.Error.Details.([]interface{})
+	if len(fields) != 1 || strings.Contains(rec.Body.String(), "CreateServerParams") {
+		t.Fatalf("unexpected details: %s", rec.Body.String())
+	}
+}
+
+func TestRequireAuth_TokenErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	secret := []byte("secret")
+	router := gin.New()
+	router.Use(Errors(presenter.NewPresenter(), zap.NewNop()))
+	router.GET("/", NewJWTMiddleware(config.JWT{Secret: string(secret)}, config.APIKey{}, config.TLS{}).RequireAuth(), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	sign := func(expires time.Time) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &dto.Claims{
+			Sub:              1,
+			RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(expires)},
+		}).SignedString(secret)
+		if err != nil {
+			t.Fatalf("sign token: %v", err)
+		}
+		return token
+	}
+
+	cases := []struct {
+		name   string
+		header string
+		status int
+		code   string
+	}{
+		{"missing", "", http.StatusUnauthorized, response.CodeUnauthorized},
+		{"expired", "Bearer " + sign(time.Now().Add(-time.Minute)), http.StatusUnauthorized, response.CodeTokenExpired},
+		{"tampered", "Bearer " + sign(time.Now().Add(time.Hour)) + "x", http.StatusUnauthorized, response.CodeInvalidToken},
+		{"valid", "Bearer " + sign(time.Now().Add(time.Hour)), http.StatusNoContent, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("got status %d, want %d: %s", rec.Code, tc.status, rec.Body.String())
+			}
+			if tc.code == "" {
+				return
+			}
+			var body response.APIResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if body.Code != tc.code {
+				t.Fatalf("got code %s, want %s", body.Code, tc.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/authz"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/idempotency"
+	"go.uber.org/zap"
+)
+
+const (
+	IDEMPOTENCY_KEY_HEADER = "Idempotency-Key"
+	// IDEMPOTENT_REPLAYED_HEADER marks a response replayed from storage.
+	IDEMPOTENT_REPLAYED_HEADER = "Idempotent-Replayed"
+	MAX_IDEMPOTENCY_KEY_LENGTH = 255
+)
+
+// Idempotency runs a request sent with an Idempotency-Key header at most once
+// per key. Retries with the same payload get the stored response back; the
+// same key with another payload is rejected. Requests that fail are not
+// stored, so they can be retried with the same key. It must run after
+// RequireAuth, since keys belong to the caller's tenant.
+func Idempotency(usecase idempotency.UseCase, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := strings.TrimSpace(c.GetHeader(IDEMPOTENCY_KEY_HEADER))
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > MAX_IDEMPOTENCY_KEY_LENGTH {
+			c.Error(domain.NewValidationError(domain.FieldError{
+				Field:   IDEMPOTENCY_KEY_HEADER,
+				Message: fmt.Sprintf("must be at most %d characters", MAX_IDEMPOTENCY_KEY_LENGTH),
+			}))
+			c.Abort()
+			return
+		}
+
+		hash, cleanup, err := requestHash(c)
+		if err != nil {
+			c.Error(domain.ErrInvalidRequest.Wrap(err))
+			c.Abort()
+			return
+		}
+		defer cleanup()
+
+		stored, err := usecase.Begin(c.Request.Context(), key, hash)
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		if stored != nil {
+			c.Header(IDEMPOTENT_REPLAYED_HEADER, "true")
+			c.Data(stored.StatusCode, stored.ContentType, stored.Body)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// The key outlives a caller that hung up, so the outcome is saved regardless.
+		ctx := context.WithoutCancel(c.Request.Context())
+		if len(c.Errors) > 0 || !recorder.Written() || recorder.Status() >= http.StatusInternalServerError {
+			if err := usecase.Release(ctx, key); err != nil {
+				log.LoggerWithContext(ctx, logger).Warn("Idempotency key left reserved until it expires", zap.String("idempotency_key", key))
+			}
+			return
+		}
+		if err := usecase.Complete(ctx, key, recorder.Status(), recorder.Header().Get("Content-Type"), recorder.body.Bytes()); err != nil {
+			log.LoggerWithContext(ctx, logger).Warn("Idempotent response not stored", zap.String("idempotency_key", key))
+		}
+	}
+}
+
+// requestHash identifies the request by route, caller and payload. The body
+// is spooled to a temporary file while it is hashed, so uploads are not held
+// in memory, and handed back to the handler from there until cleanup removes
+// it. Multipart parts are hashed one by one, since the boundary changes
+// between attempts.
+func requestHash(c *gin.Context) (string, func(), error) {
+	spool, err := os.CreateTemp("", "idempotency-*")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() {
+		spool.Close()
+		os.Remove(spool.Name())
+	}
+
+	var userID uint
+	if principal := authz.FromContext(c.Request.Context()); principal != nil {
+		userID = principal.UserID
+	}
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s %d\n", c.Request.Method, c.FullPath(), userID)
+
+	body := io.TeeReader(c.Request.Body, spool)
+	mediaType, params, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		err = hashParts(hash, multipart.NewReader(body, params["boundary"]))
+		if err == nil {
+			// Anything after the closing boundary still belongs to the body.
+			_, err = io.Copy(io.Discard, body)
+		}
+	} else {
+		_, err = io.Copy(hash, body)
+	}
+	if err == nil {
+		_, err = spool.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	c.Request.Body = spool
+	return hex.EncodeToString(hash.Sum(nil)), cleanup, nil
+}
+
+func hashParts(hash io.Writer, reader *multipart.Reader) error {
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		content := sha256.New()
+		if _, err := io.Copy(content, part); err != nil {
+			return err
+		}
+		fmt.Fprintf(hash, "%q %q %x\n", part.FormName(), part.FileName(), content.Sum(nil))
+	}
+}
+
+// responseRecorder keeps a copy of the body written to the client.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(data string) (int, error) {
+	r.body.WriteString(data)
+	return r.ResponseWriter.WriteString(data)
+}
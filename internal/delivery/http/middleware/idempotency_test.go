@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/http/presenter"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	"go.uber.org/zap"
+)
+
+type memoryIdempotency struct {
+	keys map[string]*entity.IdempotencyKey
+}
+
+func (m *memoryIdempotency) Begin(ctx context.Context, key, requestHash string) (*entity.IdempotencyKey, error) {
+	stored, ok := m.keys[key]
+	switch {
+	case !ok:
+		m.keys[key] = &entity.IdempotencyKey{Key: key, RequestHash: requestHash}
+		return nil, nil
+	case stored.RequestHash != requestHash:
+		return nil, domain.ErrIdempotencyKeyReused
+	case !stored.Completed():
+		return nil, domain.ErrIdempotencyKeyInProgress
+	}
+	return stored, nil
+}
+
+func (m *memoryIdempotency) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	m.keys[key].StatusCode, m.keys[key].ContentType, m.keys[key].Body = statusCode, contentType, body
+	return nil
+}
+
+func (m *memoryIdempotency) Release(ctx context.Context, key string) error {
+	delete(m.keys, key)
+	return nil
+}
+
+func (m *memoryIdempotency) PurgeExpired(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func newIdempotentRouter(calls *int, fail *bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Errors(presenter.NewPresenter(), zap.NewNop()))
+	router.POST("/", Idempotency(&memoryIdempotency{keys: map[string]*entity.IdempotencyKey{}}, zap.NewNop()), func(c *gin.Context) {
+		*calls++
+		if *fail {
+			c.Error(domain.ErrInternalServer)
+			return
+		}
+		presenter.NewPresenter().Created(c, "created", map[string]int{"call": *calls})
+	})
+	return router
+}
+
+func post(router *gin.Engine, key, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	if key != "" {
+		req.Header.Set(IDEMPOTENCY_KEY_HEADER, key)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotency_ReplaysAndRejectsMismatch(t *testing.T) {
+	var calls int
+	var fail bool
+	router := newIdempotentRouter(&calls, &fail)
+
+	first := post(router, "k1", "application/json", `{"server_id":"a"}`)
+	replay := post(router, "k1", "application/json", `{"server_id":"a"}`)
+	if calls != 1 || first.Code != http.StatusCreated || replay.Code != http.StatusCreated {
+		t.Fatalf("want one call and two 201s, got %d calls, %d and %d", calls, first.Code, replay.Code)
+	}
+	if replay.Body.String() != first.Body.String() || replay.Header().Get(IDEMPOTENT_REPLAYED_HEADER) != "true" {
+		t.Fatalf("replay differs: %s vs %s", replay.Body.String(), first.Body.String())
+	}
+
+	if rec := post(router, "k1", "application/json", `{"server_id":"b"}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("want 422 for a different payload, got %d", rec.Code)
+	}
+
+	post(router, "", "application/json", `{"server_id":"a"}`)
+	post(router, "", "application/json", `{"server_id":"a"}`)
+	if calls != 3 {
+		t.Fatalf("requests without a key must always run, got %d calls", calls)
+	}
+}
+
+func TestIdempotency_MultipartIgnoresBoundary(t *testing.T) {
+	var calls int
+	var fail bool
+	router := newIdempotentRouter(&calls, &fail)
+
+	upload := func(boundary string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		if err := writer.SetBoundary(boundary); err != nil {
+			t.Fatalf("set boundary: %v", err)
+		}
+		part, _ := writer.CreateFormFile("file", "servers.xlsx")
+		part.Write([]byte("workbook"))
+		writer.Close()
+		return post(router, "import-1", writer.FormDataContentType(), body.String())
+	}
+
+	upload("first-boundary")
+	if rec := upload("second-boundary"); calls != 1 || rec.Header().Get(IDEMPOTENT_REPLAYED_HEADER) != "true" {
+		t.Fatalf("same file with another boundary should replay, got %d calls", calls)
+	}
+}
+
+func TestIdempotency_ReleasesFailedRequest(t *testing.T) {
+	var calls int
+	fail := true
+	router := newIdempotentRouter(&calls, &fail)
+
+	if rec := post(router, "k1", "application/json", `{}`); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("want 500, got %d", rec.Code)
+	}
+	fail = false
+	if rec := post(router, "k1", "application/json", `{}`); rec.Code != http.StatusCreated || calls != 2 {
+		t.Fatalf("failed request should be retried, got %d after %d calls", rec.Code, calls)
+	}
+}
+
+func TestIdempotency_SpoolsBodyForHandler(t *testing.T) {
+	spool := t.TempDir()
+	t.Setenv("TMPDIR", spool)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	var received string
+	router.POST("/", Idempotency(&memoryIdempotency{keys: map[string]*entity.IdempotencyKey{}}, zap.NewNop()), func(c *gin.Context) {
+		header, err := c.FormFile("file")
+		if err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		file, _ := header.Open()
+		defer file.Close()
+		content, _ := io.ReadAll(file)
+		received = string(content)
+		c.Status(http.StatusOK)
+	})
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, _ := writer.CreateFormFile("file", "servers.xlsx")
+	part.Write([]byte("workbook"))
+	writer.Close()
+
+	if rec := post(router, "import-1", writer.FormDataContentType(), body.String()); rec.Code != http.StatusOK || received != "workbook" {
+		t.Fatalf("handler got %d and %q, want the uploaded file", rec.Code, received)
+	}
+	if entries, _ := os.ReadDir(spool); len(entries) != 0 {
+		t.Fatalf("spooled body left behind: %v", entries)
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/metrics"
+)
+
+func TestMetrics_LabelsByRouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := metrics.NewMetrics()
+	router := gin.New()
+	router.Use(Metrics(m))
+	router.GET("/server/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, path := range []string{"/server/a", "/server/b", "/missing"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	if got := testutil.ToFloat64(m.HTTPRequests.WithLabelValues(http.MethodGet, "/server/:id", "200")); got != 2 {
+		t.Fatalf("got %v requests for the route template, want 2", got)
+	}
+	if got := testutil.ToFloat64(m.HTTPRequests.WithLabelValues(http.MethodGet, "unmatched", "404")); got != 1 {
+		t.Fatalf("got %v unmatched requests, want 1", got)
+	}
+}
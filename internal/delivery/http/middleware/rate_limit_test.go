@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/http/presenter"
+	"go.uber.org/zap"
+)
+
+// countingLimiter allows limit requests per key and never refills.
+type countingLimiter struct {
+	counts map[string]int
+	err    error
+}
+
+func (l *countingLimiter) Allow(ctx context.Context, key string, limit int, period time.Duration) (bool, time.Duration, error) {
+	if l.err != nil {
+		return false, 0, l.err
+	}
+	l.counts[key]++
+	if l.counts[key] > limit {
+		return false, 1500 * time.Millisecond, nil
+	}
+	return true, 0, nil
+}
+
+func newLimitedRouter(limiter *countingLimiter) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	limit := RateLimit(limiter, config.RateLimit{
+		Enabled: true,
+		Default: config.Limit{Requests: 3, Period: time.Minute},
+		Routes:  map[string]config.Limit{"GET:/export": {Requests: 1, Period: time.Minute}},
+	}, zap.NewNop())
+
+	router := gin.New()
+	router.Use(Errors(presenter.NewPresenter(), zap.NewNop()))
+	auth := func(c *gin.Context) { c.Set("clientID", c.GetHeader("X-Client")) }
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	router.GET("/export", auth, limit, ok)
+	router.GET("/view", auth, limit, ok)
+	router.GET("/stats", auth, limit, ok)
+	return router
+}
+
+func get(router *gin.Engine, path, client string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("X-Client", client)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimit_PerRouteAndClient(t *testing.T) {
+	router := newLimitedRouter(&countingLimiter{counts: map[string]int{}})
+
+	if rec := get(router, "/export", "user:1"); rec.Code != http.StatusNoContent {
+		t.Fatalf("first export rejected: %d", rec.Code)
+	}
+	rec := get(router, "/export", "user:1")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "2" {
+		t.Fatalf("want 429 with Retry-After 2, got %d %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if rec := get(router, "/export", "user:2"); rec.Code != http.StatusNoContent {
+		t.Fatalf("another client shares the bucket: %d", rec.Code)
+	}
+
+	// Routes without a limit of their own share the default bucket.
+	for _, path := range []string{"/view", "/stats", "/view"} {
+		if rec := get(router, path, "user:1"); rec.Code != http.StatusNoContent {
+			t.Fatalf("%s within the default limit rejected: %d", path, rec.Code)
+		}
+	}
+	if rec := get(router, "/stats", "user:1"); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("default bucket not shared: %d", rec.Code)
+	}
+}
+
+func TestRateLimit_FailsOpen(t *testing.T) {
+	router := newLimitedRouter(&countingLimiter{err: errors.New("store down")})
+	for i := 0; i < 3; i++ {
+		if rec := get(router, "/export", "user:1"); rec.Code != http.StatusNoContent {
+			t.Fatalf("limiter failure should let requests through, got %d", rec.Code)
+		}
+	}
+}
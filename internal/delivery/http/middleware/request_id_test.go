@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/http/presenter"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/requestid"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/response"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/", func(c *gin.Context) {
+		presenter.NewPresenter().Retrived(c, "ok", nil)
+	})
+
+	cases := []struct {
+		name     string
+		incoming string
+		echoed   bool
+	}{
+		{"accepts caller id", "abc-123", true},
+		{"generates when missing", "", false},
+		{"replaces unsafe id", "bad id\n", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.incoming != "" {
+				req.Header.Set(requestid.Header, tc.incoming)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			got := rec.Header().Get(requestid.Header)
+			if got == "" || (got == tc.incoming) != tc.echoed {
+				t.Fatalf("unexpected request id %q for incoming %q", got, tc.incoming)
+			}
+
+			var body response.APIResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if body.RequestID != got {
+				t.Fatalf("body request id %q, header %q", body.RequestID, got)
+			}
+		})
+	}
+}
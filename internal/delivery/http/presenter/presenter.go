@@ -1,21 +1,21 @@
 package presenter
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/requestid"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/response"
 )
 
 type (
 	Presenter interface {
 		// Error responses
-		InvalidRequest(c *gin.Context, message string, err error)
-		Conflict(c *gin.Context, message string, err error)
-		InternalError(c *gin.Context, message string, err error)
-		NotFound(c *gin.Context, message string, err error)
-		Unauthorized(c *gin.Context, message string, err error)
-		Forbidden(c *gin.Context, message string, err error)
+		Error(c *gin.Context, err error)
 
 		// Success responses
 		Created(c *gin.Context, message string, data interface{})
@@ -32,48 +32,39 @@ func NewPresenter() Presenter {
 	return &presenter{}
 }
 
-func (p *presenter) Unauthorized(c *gin.Context, message string, err error) {
-	c.JSON(http.StatusUnauthorized, response.NewErrorResponse(
-		response.CodeUnauthorized,
-		message,
-		err.Error(),
-	))
-}
-
-func (p *presenter) Forbidden(c *gin.Context, message string, err error) {
-	c.JSON(http.StatusForbidden, response.NewErrorResponse(
-		response.CodeForbidden,
-		message,
-		err.Error(),
-	))
+// respond writes the envelope tagged with the request ID set by the RequestID middleware.
+func (p *presenter) respond(c *gin.Context, status int, body *response.APIResponse) {
+	body.RequestID = requestid.FromContext(c.Request.Context())
+	c.JSON(status, body)
 }
 
-func (p *presenter) InvalidRequest(c *gin.Context, message string, err error) {
-	c.JSON(http.StatusBadRequest, response.NewErrorResponse(
-		response.CodeBadRequest,
-		message,
-		err.Error(),
-	))
-}
+// Error writes the code, status, message and field errors of the catalogued
+// error in err, with a Retry-After header when it asks the caller to wait.
+// Anything outside the catalogue is reported as an internal error, so causes
+// never reach the caller.
+func (p *presenter) Error(c *gin.Context, err error) {
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) {
+		domainErr = domain.ErrInternalServer
+	}
 
-func (p *presenter) Conflict(c *gin.Context, message string, err error) {
-	c.JSON(http.StatusConflict, response.NewErrorResponse(
-		response.CodeConflict,
-		message,
-		err.Error(),
-	))
-}
+	if domainErr.RetryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(domainErr.RetryAfter.Seconds()))))
+	}
 
-func (p *presenter) InternalError(c *gin.Context, message string, err error) {
-	c.JSON(http.StatusInternalServerError, response.NewErrorResponse(
-		response.CodeInternalServerError,
-		message,
-		err.Error(),
+	var details interface{}
+	if len(domainErr.Fields) > 0 {
+		details = domainErr.Fields
+	}
+	p.respond(c, domainErr.Status, response.NewErrorResponse(
+		domainErr.Code,
+		domainErr.Message,
+		details,
 	))
 }
 
 func (p *presenter) Created(c *gin.Context, message string, data interface{}) {
-	c.JSON(http.StatusCreated, response.NewSuccessResponse(
+	p.respond(c, http.StatusCreated, response.NewSuccessResponse(
 		response.CodeCreated,
 		message,
 		data,
@@ -81,23 +72,15 @@ func (p *presenter) Created(c *gin.Context, message string, data interface{}) {
 }
 
 func (p *presenter) Deleted(c *gin.Context, message string) {
-	c.JSON(http.StatusOK, response.NewSuccessResponse(
+	p.respond(c, http.StatusOK, response.NewSuccessResponse(
 		response.CodeDeleted,
 		message,
 		nil,
 	))
 }
 
-func (p *presenter) NotFound(c *gin.Context, message string, err error) {
-	c.JSON(http.StatusNotFound, response.NewErrorResponse(
-		response.CodeNotFound,
-		message,
-		err.Error(),
-	))
-}
-
 func (p *presenter) Updated(c *gin.Context, message string, data interface{}) {
-	c.JSON(http.StatusOK, response.NewSuccessResponse(
+	p.respond(c, http.StatusOK, response.NewSuccessResponse(
 		response.CodeUpdated,
 		message,
 		data,
@@ -105,7 +88,7 @@ func (p *presenter) Updated(c *gin.Context, message string, data interface{}) {
 }
 
 func (p *presenter) Retrived(c *gin.Context, message string, data interface{}) {
-	c.JSON(http.StatusOK, response.NewSuccessResponse(
+	p.respond(c, http.StatusOK, response.NewSuccessResponse(
 		response.CodeUpdated,
 		message,
 		data,
@@ -113,7 +96,7 @@ func (p *presenter) Retrived(c *gin.Context, message string, data interface{}) {
 }
 
 func (p *presenter) Imported(c *gin.Context, message string, data interface{}) {
-	c.JSON(http.StatusOK, response.NewSuccessResponse(
+	p.respond(c, http.StatusOK, response.NewSuccessResponse(
 		response.CodeSuccess,
 		message,
 		data,
@@ -2,6 +2,7 @@ package http
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -12,74 +13,190 @@ import (
 	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/http/controller"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/http/middleware"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/delivery/http/presenter"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/authz"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	srv "github.com/th1enq/ViettelSMS_ServerService/internal/domain/service"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/certificate"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/metrics"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/idempotency"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.uber.org/zap"
 )
 
+// bulkScopes is the scope a caller needs for each bulk operation.
+var bulkScopes = map[dto.BulkOperation]string{
+	dto.BulkOperationDelete:    authz.ScopeServerDelete,
+	dto.BulkOperationPatch:     authz.ScopeServerUpdate,
+	dto.BulkOperationSetStatus: authz.ScopeServerUpdate,
+}
+
 type (
 	Server interface {
+		// Start serves until Shutdown is called and returns any other error.
 		Start(ctx context.Context) error
+		// Shutdown stops accepting connections and waits for in-flight requests
+		// until ctx expires, then closes the remaining connections.
+		Shutdown(ctx context.Context) error
 	}
 
 	server struct {
-		config     *config.Config
-		controller *controller.Controller
-		middleware middleware.JWTMiddleware
-		logger     *zap.Logger
+		httpServer  *http.Server
+		config      *config.Config
+		controller  *controller.Controller
+		health      *controller.HealthController
+		webhook     *controller.WebhookController
+		alert       *controller.AlertController
+		stream      *controller.StreamController
+		stats       *controller.StatsController
+		report      *controller.ReportController
+		middleware  middleware.JWTMiddleware
+		presenter   presenter.Presenter
+		idempotency idempotency.UseCase
+		limiter     srv.RateLimiter
+		// certificates is nil when TLS is disabled.
+		certificates certificate.Reloader
+		metrics      *metrics.Metrics
+		logger       *zap.Logger
 	}
 )
 
 func NewHttpServer(
 	config *config.Config,
 	controller *controller.Controller,
+	health *controller.HealthController,
+	webhook *controller.WebhookController,
+	alert *controller.AlertController,
+	stream *controller.StreamController,
+	stats *controller.StatsController,
+	report *controller.ReportController,
 	middleware middleware.JWTMiddleware,
+	presenter presenter.Presenter,
+	idempotency idempotency.UseCase,
+	limiter srv.RateLimiter,
+	certificates certificate.Reloader,
+	metrics *metrics.Metrics,
 	logger *zap.Logger,
 ) Server {
-	return &server{
-		config:     config,
-		controller: controller,
-		middleware: middleware,
-		logger:     logger,
+	s := &server{
+		config:       config,
+		controller:   controller,
+		health:       health,
+		webhook:      webhook,
+		alert:        alert,
+		stream:       stream,
+		stats:        stats,
+		report:       report,
+		middleware:   middleware,
+		presenter:    presenter,
+		idempotency:  idempotency,
+		limiter:      limiter,
+		certificates: certificates,
+		metrics:      metrics,
+		logger:       logger,
 	}
+	s.httpServer = &http.Server{
+		Addr:    fmt.Sprintf("%s:%d", config.Server.Host, config.Server.Port),
+		Handler: s.RegisterRoutes(),
+	}
+	return s
 }
 
 func (s *server) RegisterRoutes() *gin.Engine {
 	router := gin.New()
 	router.Use(gin.Recovery())
+	router.Use(otelgin.Middleware(s.config.Tracing.ServiceName))
+	router.Use(middleware.RequestID())
+	router.Use(middleware.Metrics(s.metrics))
 	router.Use(cors.New(cors.Config{
-		AllowOrigins: []string{"*"},
-		AllowMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders: []string{"Origin", "Content-Type", "Authorization"},
+		AllowOrigins:     s.config.CORS.AllowOrigins,
+		AllowMethods:     s.config.CORS.AllowMethods,
+		AllowHeaders:     s.config.CORS.AllowHeaders,
+		ExposeHeaders:    s.config.CORS.ExposeHeaders,
+		AllowCredentials: s.config.CORS.AllowCredentials,
+		MaxAge:           s.config.CORS.MaxAge,
 	}))
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status": "ok",
-		})
-	})
+	router.Use(middleware.Errors(s.presenter, s.logger))
+	idempotent := middleware.Idempotency(s.idempotency, s.logger)
+	limit := middleware.RateLimit(s.limiter, s.config.RateLimit, s.logger)
+	router.GET("/health", s.health.Live)
+	router.GET("/health/live", s.health.Live)
+	router.GET("/health/ready", s.health.Ready)
+	router.GET("/metrics", gin.WrapH(s.metrics.Handler()))
 
 	server := router.Group("/server")
 	{
 		server.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-		server.POST("/", s.middleware.RequireAuth(), s.controller.Create)
-		server.DELETE("/:id", s.middleware.RequireAuth(), s.middleware.RequireScope("server:delete"), s.controller.Delete)
-		server.PUT("/:id", s.middleware.RequireAuth(), s.middleware.RequireScope("server:update"), s.controller.Update)
-		server.GET("/", s.middleware.RequireAuth(), s.middleware.RequireScope("server:view"), s.controller.View)
+		server.POST("/", s.middleware.RequireAuth(), limit, s.middleware.RequireScope(authz.ScopeServerCreate), idempotent, s.controller.Create)
+		server.DELETE("/:id", s.middleware.RequireAuth(), limit, s.middleware.RequireScope(authz.ScopeServerDelete), s.controller.Delete)
+		server.POST("/:id/restore", s.middleware.RequireAuth(), limit, s.middleware.RequireScope(authz.ScopeServerRestore), s.controller.Restore)
+		server.PUT("/:id", s.middleware.RequireAuth(), limit, s.middleware.RequireScope(authz.ScopeServerUpdate), s.controller.Update)
+		server.PATCH("/:id", s.middleware.RequireAuth(), limit, s.middleware.RequireScope(authz.ScopeServerUpdate), s.controller.Patch)
+		server.GET("/", s.middleware.RequireAuth(), limit, s.middleware.RequireScope(authz.ScopeServerView), s.controller.View)
+		server.GET("/stats", s.middleware.RequireAuth(), limit, s.middleware.RequireScope(authz.ScopeServerView), s.stats.View)
+		server.GET("/stream", s.middleware.RequireAuth(), limit, s.middleware.RequireScope(authz.ScopeServerView), s.stream.SSE)
+		server.GET("/stream/ws", s.middleware.RequireAuth(), limit, s.middleware.RequireScope(authz.ScopeServerView), s.stream.WebSocket)
+
+		server.POST("/bulk", s.middleware.RequireAuth(), limit, s.middleware.RequireBulkScope(bulkScopes), s.controller.Bulk)
+
+		server.POST("/import", s.middleware.RequireAuth(), limit, s.middleware.RequireScope(authz.ScopeServerImport), idempotent, s.controller.Import)
+		server.GET("/import/template", s.middleware.RequireAuth(), limit, s.middleware.RequireScope(authz.ScopeServerImport), s.controller.ImportTemplate)
+		server.GET("/export", s.middleware.RequireAuth(), limit, s.middleware.RequireScope(authz.ScopeServerExport), s.controller.Export)
+
+		webhooks := server.Group("/webhooks", s.middleware.RequireAuth(), limit)
+		webhooks.POST("", s.middleware.RequireScope(authz.ScopeWebhookManage), s.webhook.Create)
+		webhooks.GET("", s.middleware.RequireScope(authz.ScopeWebhookView), s.webhook.List)
+		webhooks.GET("/:id", s.middleware.RequireScope(authz.ScopeWebhookView), s.webhook.Get)
+		webhooks.PUT("/:id", s.middleware.RequireScope(authz.ScopeWebhookManage), s.webhook.Update)
+		webhooks.DELETE("/:id", s.middleware.RequireScope(authz.ScopeWebhookManage), s.webhook.Delete)
+		webhooks.GET("/:id/deliveries", s.middleware.RequireScope(authz.ScopeWebhookView), s.webhook.Deliveries)
+
+		reports := server.Group("/reports", s.middleware.RequireAuth(), limit, s.middleware.RequireScope(authz.ScopeReportView))
+		reports.GET("", s.report.List)
+		reports.GET("/:name", s.report.Download)
+
+		server.GET("/alerts", s.middleware.RequireAuth(), limit, s.middleware.RequireScope(authz.ScopeAlertView), s.alert.View)
 
-		server.POST("/import", s.middleware.RequireAuth(), s.middleware.RequireScope("server:import"), s.controller.Import)
-		server.GET("/export", s.middleware.RequireAuth(), s.middleware.RequireScope("server:export"), s.controller.Export)
+		maintenance := server.Group("/maintenance", s.middleware.RequireAuth(), limit)
+		maintenance.POST("", s.middleware.RequireScope(authz.ScopeMaintenanceManage), s.alert.CreateMaintenanceWindow)
+		maintenance.GET("", s.middleware.RequireScope(authz.ScopeMaintenanceView), s.alert.ListMaintenanceWindows)
+		maintenance.DELETE("/:id", s.middleware.RequireScope(authz.ScopeMaintenanceManage), s.alert.DeleteMaintenanceWindow)
 	}
 
 	return router
 }
 
 func (s *server) Start(ctx context.Context) error {
-	server := &http.Server{
-		Addr:    fmt.Sprintf("%s:%d", s.config.Server.Host, s.config.Server.Port),
-		Handler: s.RegisterRoutes(),
+	if s.certificates == nil {
+		s.logger.Info("HTTP server starting", zap.String("host", s.config.Server.Host), zap.Int("port", s.config.Server.Port))
+		return serveError(s.httpServer.ListenAndServe())
+	}
+
+	s.logger.Info("HTTPS server starting", zap.String("host", s.config.Server.Host), zap.Int("port", s.config.Server.Port),
+		zap.Bool("client_cert", s.config.TLS.ClientCAFile != ""))
+	s.httpServer.TLSConfig = s.certificates.TLSConfig()
+	go s.certificates.Start(ctx)
+	// The certificate comes from TLSConfig, so no files are passed here.
+	return serveError(s.httpServer.ListenAndServeTLS("", ""))
+}
+
+// serveError hides the error returned once Shutdown is called.
+func serveError(err error) error {
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to start HTTP server: %w", err)
 	}
-	s.logger.Info("HTTP server starting", zap.String("host", s.config.Server.Host), zap.Int("port", s.config.Server.Port))
+	return nil
+}
+
+func (s *server) Shutdown(ctx context.Context) error {
+	s.logger.Info("HTTP server shutting down")
 
-	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		s.logger.Fatal("failed to start HTTP server", zap.Error(err))
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		s.logger.Warn("HTTP server did not drain in time, closing connections", zap.Error(err))
+		if closeErr := s.httpServer.Close(); closeErr != nil {
+			return closeErr
+		}
+		return err
 	}
 	return nil
 }
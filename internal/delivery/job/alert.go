@@ -0,0 +1,60 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/alert"
+	"go.uber.org/zap"
+)
+
+type (
+	AlertJob interface {
+		Start(ctx context.Context) error
+	}
+
+	alertJob struct {
+		config  *config.Config
+		usecase alert.UseCase
+		logger  *zap.Logger
+	}
+)
+
+func NewAlertJob(
+	config *config.Config,
+	usecase alert.UseCase,
+	logger *zap.Logger,
+) AlertJob {
+	return &alertJob{
+		config:  config,
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+// Start evaluates the alert rules on every tick until the context is cancelled.
+func (j *alertJob) Start(ctx context.Context) error {
+	interval := j.config.Alert.EvaluationInterval
+	if interval <= 0 {
+		j.logger.Info("Alert job disabled")
+		return nil
+	}
+
+	j.logger.Info("Alert job started", zap.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Alert job stopped")
+			return nil
+		case <-ticker.C:
+			if err := j.usecase.Evaluate(ctx); err != nil && ctx.Err() == nil {
+				j.logger.Error("Failed to evaluate alert rules", zap.Error(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gammazero/workerpool"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/probe"
+	"go.uber.org/zap"
+)
+
+// PROBE_TICK is the resolution of the probe schedule.
+const PROBE_TICK = time.Second
+
+type (
+	ProbeJob interface {
+		Start(ctx context.Context) error
+	}
+
+	probeJob struct {
+		config  *config.Config
+		usecase probe.UseCase
+		logger  *zap.Logger
+	}
+)
+
+func NewProbeJob(
+	config *config.Config,
+	usecase probe.UseCase,
+	logger *zap.Logger,
+) ProbeJob {
+	return &probeJob{
+		config:  config,
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+// Start probes every live server once per IntervalTime on a bounded worker
+// pool until the context is cancelled. A server whose previous probe is still
+// running is skipped rather than queued twice.
+func (j *probeJob) Start(ctx context.Context) error {
+	if !j.config.Prober.Enabled {
+		j.logger.Info("Probe job disabled")
+		return nil
+	}
+
+	j.logger.Info("Probe job started",
+		zap.String("type", j.config.Prober.Type),
+		zap.Int("workers", j.config.Prober.Workers),
+		zap.Duration("refresh_interval", j.config.Prober.RefreshInterval))
+
+	workerPool := workerpool.New(j.config.Prober.Workers)
+	defer workerPool.StopWait()
+
+	schedule := newSchedule()
+	j.refresh(ctx, schedule)
+
+	refresh := time.NewTicker(j.config.Prober.RefreshInterval)
+	defer refresh.Stop()
+	tick := time.NewTicker(PROBE_TICK)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Probe job stopped")
+			return nil
+		case <-refresh.C:
+			j.refresh(ctx, schedule)
+		case now := <-tick.C:
+			j.dispatch(ctx, workerPool, schedule, now)
+		}
+	}
+}
+
+func (j *probeJob) refresh(ctx context.Context, schedule *schedule) {
+	servers, err := j.usecase.Targets(ctx)
+	if err != nil {
+		j.logger.Error("Failed to refresh probe targets", zap.Error(err))
+		return
+	}
+	schedule.sync(servers, time.Now())
+}
+
+func (j *probeJob) dispatch(ctx context.Context, workerPool *workerpool.WorkerPool, schedule *schedule, now time.Time) {
+	for _, target := range schedule.due(now) {
+		if !target.inFlight.CompareAndSwap(false, true) {
+			continue
+		}
+
+		target := target
+		server := target.server
+		workerPool.Submit(func() {
+			defer target.inFlight.Store(false)
+
+			_, err := j.usecase.Check(ctx, &server)
+			switch {
+			case err == nil, ctx.Err() != nil:
+			case errors.Is(err, domain.ErrServerDeleted), errors.Is(err, domain.ErrServerNotFound):
+				// Removed since the last refresh; the next refresh drops it.
+			default:
+				j.logger.Error("Probe failed",
+					zap.String("tenant_id", server.TenantID),
+					zap.String("server_id", server.ServerID),
+					zap.Error(err))
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/report"
+	"go.uber.org/zap"
+)
+
+type (
+	ReportJob interface {
+		Start(ctx context.Context) error
+	}
+
+	reportJob struct {
+		config   *config.Config
+		usecase  report.UseCase
+		schedule cron.Schedule
+		logger   *zap.Logger
+	}
+)
+
+// NewReportJob returns the job generating reports on the configured cron
+// schedule. schedule may be nil when reports are disabled.
+func NewReportJob(
+	config *config.Config,
+	usecase report.UseCase,
+	schedule cron.Schedule,
+	logger *zap.Logger,
+) ReportJob {
+	return &reportJob{
+		config:   config,
+		usecase:  usecase,
+		schedule: schedule,
+		logger:   logger,
+	}
+}
+
+// ParseReportSchedule parses a five-field cron expression, which may start
+// with CRON_TZ=<zone>.
+func ParseReportSchedule(spec string) (cron.Schedule, error) {
+	return cron.ParseStandard(spec)
+}
+
+// Start generates the reports each time the schedule fires until the context
+// is cancelled. Runs missed while the service was down are not caught up, but
+// a run that failed is retried until the next one is due.
+func (j *reportJob) Start(ctx context.Context) error {
+	if !j.config.Report.Enabled {
+		j.logger.Info("Report job disabled")
+		return nil
+	}
+
+	next := j.schedule.Next(time.Now())
+	j.logger.Info("Report job started",
+		zap.String("schedule", j.config.Report.Schedule),
+		zap.Duration("period", j.config.Report.Period),
+		zap.Time("next_run", next))
+
+	end := next
+	for {
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			j.logger.Info("Report job stopped")
+			return nil
+		case <-timer.C:
+		}
+
+		// Every replica passes the same scheduled time, which the usecase
+		// claims so that only one of them generates each report.
+		if err := j.usecase.Generate(ctx, end); err != nil && ctx.Err() == nil {
+			j.logger.Error("Failed to generate reports", zap.Error(err))
+
+			// Failed reports are released, so any replica may retry them;
+			// the reports already generated stay claimed.
+			retry := time.Now().Add(j.config.Report.RetryInterval)
+			if j.config.Report.RetryInterval > 0 && retry.Before(j.schedule.Next(time.Now())) {
+				next = retry
+				j.logger.Info("Report retry scheduled", zap.Time("period_end", end), zap.Time("retry_at", next))
+				continue
+			}
+		}
+		next = j.schedule.Next(time.Now())
+		end = next
+		j.logger.Info("Next report scheduled", zap.Time("next_run", next))
+	}
+}
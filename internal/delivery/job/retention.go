@@ -0,0 +1,75 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/idempotency"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/server"
+	"go.uber.org/zap"
+)
+
+type (
+	RetentionJob interface {
+		Start(ctx context.Context) error
+	}
+
+	retentionJob struct {
+		config      *config.Config
+		usecase     server.UseCase
+		idempotency idempotency.UseCase
+		logger      *zap.Logger
+	}
+)
+
+func NewRetentionJob(
+	config *config.Config,
+	usecase server.UseCase,
+	idempotency idempotency.UseCase,
+	logger *zap.Logger,
+) RetentionJob {
+	return &retentionJob{
+		config:      config,
+		usecase:     usecase,
+		idempotency: idempotency,
+		logger:      logger,
+	}
+}
+
+// Start purges soft-deleted servers older than the retention period and
+// expired idempotency keys on every tick until the context is cancelled.
+// A non-positive retention period keeps deleted servers forever.
+func (j *retentionJob) Start(ctx context.Context) error {
+	days := j.config.Retention.DeletedServerDays
+	if j.config.Retention.PurgeInterval <= 0 {
+		j.logger.Info("Retention job disabled")
+		return nil
+	}
+	retention := time.Duration(days) * 24 * time.Hour
+
+	j.logger.Info("Retention job started",
+		zap.Int("retention_days", days),
+		zap.Duration("interval", j.config.Retention.PurgeInterval))
+
+	ticker := time.NewTicker(j.config.Retention.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		if days > 0 {
+			if _, err := j.usecase.PurgeDeletedServers(ctx, retention); err != nil {
+				j.logger.Error("Retention purge failed", zap.Error(err))
+			}
+		}
+		if _, err := j.idempotency.PurgeExpired(ctx); err != nil {
+			j.logger.Error("Idempotency key purge failed", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Retention job stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
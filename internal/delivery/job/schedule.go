@@ -0,0 +1,92 @@
+package job
+
+import (
+	"hash/fnv"
+	"sync/atomic"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+)
+
+type (
+	// schedule tracks when each server is next due. It is owned by a single
+	// goroutine; only inFlight may be touched by workers.
+	schedule struct {
+		targets map[string]*scheduledServer
+	}
+
+	scheduledServer struct {
+		key      string
+		server   entity.Server
+		next     time.Time
+		inFlight atomic.Bool
+	}
+)
+
+func newSchedule() *schedule {
+	return &schedule{targets: make(map[string]*scheduledServer)}
+}
+
+func scheduleKey(server *entity.Server) string {
+	return server.TenantID + "/" + server.ServerID
+}
+
+// sync replaces the scheduled servers with the current inventory.
+func (s *schedule) sync(servers []*entity.Server, now time.Time) {
+	seen := make(map[string]bool, len(servers))
+	for _, server := range servers {
+		key := scheduleKey(server)
+		seen[key] = true
+
+		target, ok := s.targets[key]
+		if !ok {
+			s.targets[key] = &scheduledServer{
+				key:    key,
+				server: *server,
+				next:   nextRun(key, server.CheckInterval(), now),
+			}
+			continue
+		}
+		if target.server.IntervalTime != server.IntervalTime {
+			target.next = nextRun(key, server.CheckInterval(), now)
+		}
+		target.server = *server
+	}
+	for key := range s.targets {
+		if !seen[key] {
+			delete(s.targets, key)
+		}
+	}
+}
+
+// due returns the servers whose run has come and moves each to its next slot.
+func (s *schedule) due(now time.Time) []*scheduledServer {
+	var due []*scheduledServer
+	for _, target := range s.targets {
+		if now.Before(target.next) {
+			continue
+		}
+		target.next = nextRun(target.key, target.server.CheckInterval(), now)
+		due = append(due, target)
+	}
+	return due
+}
+
+// nextRun returns the first slot after the given time. Slots are spaced by
+// interval and offset by a phase derived from the key, so servers with the
+// same interval are spread over it instead of firing together, and every
+// replica computes the same slots for a server.
+func nextRun(key string, interval time.Duration, after time.Time) time.Time {
+	if interval <= 0 {
+		return after
+	}
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	phase := time.Duration(h.Sum64() % uint64(interval))
+
+	next := after.Truncate(interval).Add(phase)
+	if !next.After(after) {
+		next = next.Add(interval)
+	}
+	return next
+}
@@ -0,0 +1,56 @@
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+)
+
+func TestNextRun_StableSlotsWithinInterval(t *testing.T) {
+	interval := 30 * time.Second
+	now := time.Date(2025, 1, 1, 10, 0, 7, 0, time.UTC)
+
+	first := nextRun("acme/s1", interval, now)
+	if !first.After(now) || first.Sub(now) > interval {
+		t.Fatalf("next run %s not within (now, now+interval]", first)
+	}
+	// Another replica asking later, but before the slot, gets the same slot.
+	if again := nextRun("acme/s1", interval, first.Add(-time.Millisecond)); !again.Equal(first) {
+		t.Fatalf("expected %s, got %s", first, again)
+	}
+	if next := nextRun("acme/s1", interval, first); !next.Equal(first.Add(interval)) {
+		t.Fatalf("expected %s, got %s", first.Add(interval), next)
+	}
+}
+
+func TestSchedule_SpreadsServersOverInterval(t *testing.T) {
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	var servers []*entity.Server
+	for _, id := range []string{"s1", "s2", "s3", "s4", "s5", "s6", "s7", "s8"} {
+		servers = append(servers, &entity.Server{TenantID: "acme", ServerID: id, IntervalTime: 60})
+	}
+
+	s := newSchedule()
+	s.sync(servers, now)
+
+	slots := make(map[time.Time]bool)
+	for _, target := range s.targets {
+		slots[target.next] = true
+	}
+	if len(slots) < 2 {
+		t.Fatalf("expected servers on different slots, got %d distinct", len(slots))
+	}
+
+	if due := s.due(now.Add(time.Minute)); len(due) != len(servers) {
+		t.Fatalf("expected all %d servers due after one interval, got %d", len(servers), len(due))
+	}
+	if due := s.due(now.Add(time.Minute)); len(due) != 0 {
+		t.Fatalf("expected nothing due twice in the same slot, got %d", len(due))
+	}
+
+	s.sync(servers[:2], now)
+	if len(s.targets) != 2 {
+		t.Fatalf("expected removed servers dropped, got %d", len(s.targets))
+	}
+}
@@ -0,0 +1,111 @@
+package job
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/kafka/membership"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/probe"
+	"go.uber.org/zap"
+)
+
+type (
+	SchedulerJob interface {
+		Start(ctx context.Context) error
+	}
+
+	schedulerJob struct {
+		config     *config.Config
+		usecase    probe.UseCase
+		membership membership.Membership
+		logger     *zap.Logger
+	}
+)
+
+// NewSchedulerJob returns the job that publishes health check requests.
+// membership may be nil when the scheduler is disabled.
+func NewSchedulerJob(
+	config *config.Config,
+	usecase probe.UseCase,
+	membership membership.Membership,
+	logger *zap.Logger,
+) SchedulerJob {
+	return &schedulerJob{
+		config:     config,
+		usecase:    usecase,
+		membership: membership,
+		logger:     logger,
+	}
+}
+
+// Start publishes a health check request for every live server once per
+// IntervalTime until the context is cancelled. Every replica tracks the full
+// schedule but only publishes for the servers in the shards it owns.
+func (j *schedulerJob) Start(ctx context.Context) error {
+	if !j.config.Scheduler.Enabled {
+		j.logger.Info("Scheduler job disabled")
+		return nil
+	}
+
+	j.logger.Info("Scheduler job started",
+		zap.String("topic", j.config.Scheduler.Topic),
+		zap.String("shard_topic", j.config.Scheduler.ShardTopic),
+		zap.Duration("refresh_interval", j.config.Scheduler.RefreshInterval))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := j.membership.Start(ctx); err != nil {
+			j.logger.Error("Failed to leave scheduler group", zap.Error(err))
+		}
+	}()
+	defer wg.Wait()
+
+	schedule := newSchedule()
+	j.refresh(ctx, schedule)
+
+	refresh := time.NewTicker(j.config.Scheduler.RefreshInterval)
+	defer refresh.Stop()
+	tick := time.NewTicker(PROBE_TICK)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Scheduler job stopped")
+			return nil
+		case <-refresh.C:
+			j.refresh(ctx, schedule)
+		case now := <-tick.C:
+			j.publish(ctx, schedule, now)
+		}
+	}
+}
+
+func (j *schedulerJob) refresh(ctx context.Context, schedule *schedule) {
+	servers, err := j.usecase.Targets(ctx)
+	if err != nil {
+		j.logger.Error("Failed to refresh scheduled servers", zap.Error(err))
+		return
+	}
+	schedule.sync(servers, time.Now())
+}
+
+// publish sends the requests that are due. A failed publish is not retried:
+// the server is requested again at its next slot.
+func (j *schedulerJob) publish(ctx context.Context, schedule *schedule, now time.Time) {
+	for _, target := range schedule.due(now) {
+		if !j.membership.Owns(target.key) {
+			continue
+		}
+		if err := j.usecase.RequestCheck(ctx, &target.server); err != nil && ctx.Err() == nil {
+			j.logger.Warn("Health check request not published",
+				zap.String("tenant_id", target.server.TenantID),
+				zap.String("server_id", target.server.ServerID),
+				zap.Error(err))
+		}
+	}
+}
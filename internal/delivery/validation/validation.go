@@ -0,0 +1,102 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+)
+
+// Field errors name the member the caller sent, not the Go struct field.
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			for _, tag := range []string{"json", "form", "uri"} {
+				name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+				if name != "" && name != "-" {
+					return name
+				}
+			}
+			return field.Name
+		})
+	}
+}
+
+// Struct applies the binding rules of a DTO built by hand rather than bound
+// from a request, so every transport rejects the same input the same way.
+func Struct(obj interface{}) error {
+	if err := binding.Validator.ValidateStruct(obj); err != nil {
+		return Error(err)
+	}
+	return nil
+}
+
+// Error turns a failed bind into a catalogued error. Rule violations and type
+// mismatches become field errors; anything else is an invalid request. The
+// binder text is kept as the cause for logs only.
+func Error(err error) error {
+	var domainErr *domain.Error
+	if errors.As(err, &domainErr) {
+		return err
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]domain.FieldError, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			fields = append(fields, domain.FieldError{
+				Field:   fieldPath(fieldErr),
+				Message: ruleMessage(fieldErr),
+			})
+		}
+		return domain.NewValidationError(fields...).Wrap(err)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return domain.NewValidationError(domain.FieldError{
+			Field:   typeErr.Field,
+			Message: "has an invalid type",
+		}).Wrap(err)
+	}
+
+	return domain.ErrInvalidRequest.Wrap(err)
+}
+
+// fieldPath drops the struct name from the namespace, e.g. "patch.interval_time".
+func fieldPath(fieldErr validator.FieldError) string {
+	if _, path, ok := strings.Cut(fieldErr.Namespace(), "."); ok {
+		return path
+	}
+	return fieldErr.Field()
+}
+
+func ruleMessage(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required", "required_without", "required_if":
+		return "is required"
+	case "excluded_with":
+		return "must not be combined with " + strings.ToLower(fieldErr.Param())
+	case "min":
+		return "must be at least " + fieldErr.Param()
+	case "max":
+		return "must be at most " + fieldErr.Param()
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(fieldErr.Param()), ", ")
+	case "ipv4":
+		return "must be a valid IPv4 address"
+	case "url":
+		return "must be a valid URL"
+	case "startswith":
+		return "must start with " + fieldErr.Param()
+	case "gtfield":
+		return "must be after " + strings.ToLower(fieldErr.Param())
+	default:
+		return fmt.Sprintf("failed the %s rule", fieldErr.Tag())
+	}
+}
@@ -0,0 +1,165 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+)
+
+const (
+	ScopeServerCreate  = "server:create"
+	ScopeServerView    = "server:view"
+	ScopeServerUpdate  = "server:update"
+	ScopeServerDelete  = "server:delete"
+	ScopeServerRestore = "server:restore"
+	ScopeServerImport  = "server:import"
+	ScopeServerExport  = "server:export"
+
+	ScopeWebhookView   = "webhook:view"
+	ScopeWebhookManage = "webhook:manage"
+
+	ScopeAlertView         = "alert:view"
+	ScopeMaintenanceView   = "maintenance:view"
+	ScopeMaintenanceManage = "maintenance:manage"
+
+	ScopeReportView = "report:view"
+
+	wildcard        = "*"
+	scopeSeparator  = ":"
+	constraintSplit = "="
+)
+
+// Constraint keys that may appear in a token's constraints claim.
+const (
+	ConstraintLocation = "location"
+	ConstraintOS       = "os"
+	ConstraintGroup    = "group"
+)
+
+// ConstraintColumns maps constraint keys to the servers table columns they restrict.
+var ConstraintColumns = map[string]string{
+	ConstraintLocation: "location",
+	ConstraintOS:       "os",
+	ConstraintGroup:    "group_name",
+}
+
+// Constraints restricts a principal to servers whose attributes match one of
+// the allowed values for every key.
+type Constraints map[string][]string
+
+type Principal struct {
+	UserID      uint
+	Scopes      []string
+	Constraints Constraints
+}
+
+type principalKey struct{}
+
+// ParseConstraints parses "key=value" entries, e.g. "location=HN".
+// Repeated keys widen the allowed values for that key.
+func ParseConstraints(raw []string) (Constraints, error) {
+	constraints := make(Constraints)
+	for _, entry := range raw {
+		key, value, ok := strings.Cut(entry, constraintSplit)
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if !ok || key == "" || value == "" {
+			return nil, fmt.Errorf("malformed constraint %q", entry)
+		}
+		if _, supported := ConstraintColumns[key]; !supported {
+			return nil, fmt.Errorf("unsupported constraint key %q", key)
+		}
+		if !slices.Contains(constraints[key], value) {
+			constraints[key] = append(constraints[key], value)
+		}
+	}
+	return constraints, nil
+}
+
+// MatchScope reports whether a granted scope satisfies the required one.
+// Segments are separated by ":"; a "*" segment matches exactly one segment,
+// and a trailing "*" matches any remaining segments, so "server:*" grants
+// "server:view" and "*" grants everything.
+func MatchScope(granted, required string) bool {
+	grantedParts := strings.Split(granted, scopeSeparator)
+	requiredParts := strings.Split(required, scopeSeparator)
+
+	for i, part := range grantedParts {
+		if part == wildcard && i == len(grantedParts)-1 {
+			return len(requiredParts) > i
+		}
+		if i >= len(requiredParts) {
+			return false
+		}
+		if part != wildcard && part != requiredParts[i] {
+			return false
+		}
+	}
+	return len(grantedParts) == len(requiredParts)
+}
+
+func (p *Principal) HasScope(required string) bool {
+	if p == nil {
+		return false
+	}
+	for _, granted := range p.Scopes {
+		if MatchScope(granted, required) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAccess reports whether the server satisfies every constraint of the principal.
+// A nil principal is an internal caller and is not restricted.
+func (p *Principal) CanAccess(server *entity.Server) bool {
+	if p == nil {
+		return true
+	}
+	for key, allowed := range p.Constraints {
+		if !slices.Contains(allowed, attribute(server, key)) {
+			return false
+		}
+	}
+	return true
+}
+
+// CanAccessGroup reports whether every server of the group, including those
+// added to it later, satisfies the constraints of the principal. Only a group
+// constraint guarantees that; any other constraint may exclude some of them.
+func (p *Principal) CanAccessGroup(group string) bool {
+	if p == nil {
+		return true
+	}
+	for key, allowed := range p.Constraints {
+		if key != ConstraintGroup || !slices.Contains(allowed, group) {
+			return false
+		}
+	}
+	return true
+}
+
+func attribute(server *entity.Server, key string) string {
+	switch key {
+	case ConstraintLocation:
+		return server.Location
+	case ConstraintOS:
+		return server.OS
+	case ConstraintGroup:
+		return server.Group
+	default:
+		return ""
+	}
+}
+
+func NewContext(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+func FromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
@@ -0,0 +1,79 @@
+package authz
+
+import (
+	"testing"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+)
+
+func TestMatchScope(t *testing.T) {
+	cases := []struct {
+		granted, required string
+		want              bool
+	}{
+		{"server:view", "server:view", true},
+		{"server:view", "server:update", false},
+		{"server:*", "server:view", true},
+		{"server:*", "server:import:xlsx", true},
+		{"server:*", "server", false},
+		{"*", "server:delete", true},
+		{"server:*:read", "server:logs:read", true},
+		{"server:*:read", "server:logs:write", false},
+		{"server", "server:view", false},
+		{"user:*", "server:view", false},
+	}
+	for _, tc := range cases {
+		if got := MatchScope(tc.granted, tc.required); got != tc.want {
+			t.Errorf("MatchScope(%q, %q) = %v, want %v", tc.granted, tc.required, got, tc.want)
+		}
+	}
+}
+
+func TestParseConstraints(t *testing.T) {
+	constraints, err := ParseConstraints([]string{"location=HN", "location=HCM", "group=billing"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(constraints[ConstraintLocation]) != 2 || constraints[ConstraintGroup][0] != "billing" {
+		t.Fatalf("unexpected constraints: %+v", constraints)
+	}
+
+	for _, raw := range []string{"location", "=HN", "location=", "rack=7"} {
+		if _, err := ParseConstraints([]string{raw}); err == nil {
+			t.Fatalf("want error for %q", raw)
+		}
+	}
+}
+
+func TestCanAccess(t *testing.T) {
+	principal := &Principal{Constraints: Constraints{ConstraintLocation: {"HN"}, ConstraintGroup: {"billing"}}}
+
+	if !principal.CanAccess(&entity.Server{Location: "HN", Group: "billing"}) {
+		t.Fatalf("want access to matching server")
+	}
+	if principal.CanAccess(&entity.Server{Location: "HN", Group: "sms"}) {
+		t.Fatalf("want no access when one constraint fails")
+	}
+
+	var internal *Principal
+	if !internal.CanAccess(&entity.Server{Location: "HCM"}) {
+		t.Fatalf("nil principal must not be restricted")
+	}
+}
+
+func TestCanAccessGroup(t *testing.T) {
+	if !(&Principal{Constraints: Constraints{ConstraintGroup: {"billing", "sms"}}}).CanAccessGroup("sms") {
+		t.Fatalf("want access to a permitted group")
+	}
+	if (&Principal{Constraints: Constraints{ConstraintGroup: {"billing"}}}).CanAccessGroup("sms") {
+		t.Fatalf("want no access to another group")
+	}
+	if (&Principal{Constraints: Constraints{ConstraintGroup: {"sms"}, ConstraintLocation: {"HN"}}}).CanAccessGroup("sms") {
+		t.Fatalf("want no access when the group may hold servers elsewhere")
+	}
+
+	var internal *Principal
+	if !internal.CanAccessGroup("sms") {
+		t.Fatalf("nil principal must not be restricted")
+	}
+}
@@ -0,0 +1,91 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+)
+
+type (
+	AlertFilterOptions struct {
+		State   *entity.AlertState `form:"state" json:"state" binding:"omitempty,oneof=firing resolved"`
+		Rule    *entity.AlertRule  `form:"rule" json:"rule" binding:"omitempty,oneof=offline group_offline flapping"`
+		Subject *string            `form:"subject" json:"subject"`
+	}
+
+	AlertPaginationOptions struct {
+		Page     int `form:"page" binding:"min=1" default:"1"`
+		PageSize int `form:"page_size" binding:"min=1,max=100" default:"20"`
+	}
+
+	AlertResponse struct {
+		ID         uint              `json:"id"`
+		Rule       entity.AlertRule  `json:"rule"`
+		Subject    string            `json:"subject"`
+		State      entity.AlertState `json:"state"`
+		Message    string            `json:"message"`
+		Value      float64           `json:"value"`
+		StartedAt  time.Time         `json:"started_at"`
+		ResolvedAt *time.Time        `json:"resolved_at,omitempty"`
+	}
+
+	// CreateMaintenanceWindowParams targets either a server or a group, never both.
+	CreateMaintenanceWindowParams struct {
+		ServerID *string   `json:"server_id" binding:"required_without=Group,excluded_with=Group,omitempty,min=1"`
+		Group    *string   `json:"group" binding:"omitempty,min=1"`
+		StartsAt time.Time `json:"starts_at" binding:"required"`
+		EndsAt   time.Time `json:"ends_at" binding:"required,gtfield=StartsAt"`
+		Reason   string    `json:"reason"`
+	}
+
+	MaintenanceWindowFilterOptions struct {
+		IncludeExpired bool `form:"include_expired" json:"include_expired"`
+	}
+
+	MaintenanceWindowResponse struct {
+		ID        uint      `json:"id"`
+		ServerID  string    `json:"server_id,omitempty"`
+		Group     string    `json:"group,omitempty"`
+		StartsAt  time.Time `json:"starts_at"`
+		EndsAt    time.Time `json:"ends_at"`
+		Reason    string    `json:"reason,omitempty"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+)
+
+func ToAlertsResponse(alerts []*entity.Alert) []*AlertResponse {
+	responses := make([]*AlertResponse, len(alerts))
+	for i, alert := range alerts {
+		responses[i] = &AlertResponse{
+			ID:         alert.ID,
+			Rule:       alert.Rule,
+			Subject:    alert.Subject,
+			State:      alert.State,
+			Message:    alert.Message,
+			Value:      alert.Value,
+			StartedAt:  alert.StartedAt,
+			ResolvedAt: alert.ResolvedAt,
+		}
+	}
+	return responses
+}
+
+func ToMaintenanceWindowResponse(window *entity.MaintenanceWindow) *MaintenanceWindowResponse {
+	return &MaintenanceWindowResponse{
+		ID:        window.ID,
+		ServerID:  window.ServerID,
+		Group:     window.Group,
+		StartsAt:  window.StartsAt,
+		EndsAt:    window.EndsAt,
+		Reason:    window.Reason,
+		CreatedAt: window.CreatedAt,
+	}
+}
+
+func ToMaintenanceWindowsResponse(windows []*entity.MaintenanceWindow) []*MaintenanceWindowResponse {
+	responses := make([]*MaintenanceWindowResponse, len(windows))
+	for i, window := range windows {
+		responses[i] = ToMaintenanceWindowResponse(window)
+	}
+	return responses
+}
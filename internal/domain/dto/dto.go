@@ -7,27 +7,56 @@ import (
 	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
 )
 
+type (
+	BulkOperation   string
+	BulkResult      string
+	HealthStatus    string
+	ComponentStatus string
+)
+
+const (
+	BulkOperationDelete    BulkOperation = "delete"
+	BulkOperationPatch     BulkOperation = "patch"
+	BulkOperationSetStatus BulkOperation = "set_status"
+
+	BulkResultApplied    BulkResult = "applied"
+	BulkResultWouldApply BulkResult = "would_apply"
+	BulkResultNotFound   BulkResult = "not_found"
+	BulkResultForbidden  BulkResult = "forbidden"
+
+	HealthStatusOK          HealthStatus = "ok"
+	HealthStatusUnavailable HealthStatus = "unavailable"
+
+	ComponentStatusUp   ComponentStatus = "up"
+	ComponentStatusDown ComponentStatus = "down"
+)
+
 type (
 	CreateServerParams struct {
-		ServerID     string  `json:"server_id" binding:"required"`
-		ServerName   string  `json:"server_name" binding:"required"`
-		IPv4         string  `json:"ipv4" binding:"required,ipv4"`
-		Location     *string `json:"location"`
-		OS           *string `json:"os"`
-		IntervalTime int     `json:"interval_time" binding:"required,min=1,max=60"`
+		ServerID     string        `json:"server_id" binding:"required"`
+		ServerName   string        `json:"server_name" binding:"required"`
+		IPv4         string        `json:"ipv4" binding:"required,ipv4"`
+		Location     *string       `json:"location"`
+		OS           *string       `json:"os"`
+		Group        *string       `json:"group"`
+		Labels       entity.Labels `json:"labels"`
+		IntervalTime int           `json:"interval_time" binding:"required,min=1,max=60"`
 	}
 
 	UpdateServerParams struct {
-		ServerName   *string `json:"server_name"`
-		IPv4         *string `json:"ipv4"`
-		Location     *string `json:"location"`
-		OS           *string `json:"os"`
-		IntervalTime *int    `json:"interval_time"`
+		ServerName   *string       `json:"server_name" binding:"omitempty,min=1"`
+		IPv4         *string       `json:"ipv4" binding:"omitempty,ipv4"`
+		Location     *string       `json:"location"`
+		OS           *string       `json:"os"`
+		Group        *string       `json:"group"`
+		Labels       entity.Labels `json:"labels"`
+		IntervalTime *int          `json:"interval_time" binding:"omitempty,min=1,max=60"`
 	}
 
 	ServerFilterOptions struct {
-		ServerName *string              `form:"server_name"`
-		Status     *entity.ServerStatus `form:"status" binding:"omitempty,oneof=ONLINE OFFLINE UNKNOWN"`
+		ServerName     *string              `form:"server_name" json:"server_name"`
+		Status         *entity.ServerStatus `form:"status" json:"status" binding:"omitempty,oneof=ONLINE OFFLINE UNKNOWN"`
+		IncludeDeleted bool                 `form:"include_deleted" json:"include_deleted"`
 	}
 
 	ServerPaginationOptions struct {
@@ -52,7 +81,58 @@ type (
 		Status       entity.ServerStatus `json:"status"`
 		Location     string              `json:"location"`
 		OS           string              `json:"os"`
+		Group        string              `json:"group"`
+		Labels       entity.Labels       `json:"labels"`
 		IntervalTime int                 `json:"interval_time"`
+		DeletedAt    *time.Time          `json:"deleted_at,omitempty"`
+	}
+
+	// BulkOperationParams targets servers either by ID or by filter, never both.
+	BulkOperationParams struct {
+		ServerIDs []string             `json:"server_ids" binding:"required_without=Filter,excluded_with=Filter,dive,required"`
+		Filter    *ServerFilterOptions `json:"filter"`
+		Operation BulkOperation        `json:"operation" binding:"required,oneof=delete patch set_status"`
+		Patch     *BulkPatch           `json:"patch" binding:"required_if=Operation patch"`
+		Status    *entity.ServerStatus `json:"status" binding:"required_if=Operation set_status,omitempty,oneof=ONLINE OFFLINE UNKNOWN"`
+		DryRun    bool                 `json:"dry_run"`
+	}
+
+	BulkPatch struct {
+		Location     *string       `json:"location"`
+		OS           *string       `json:"os"`
+		IntervalTime *int          `json:"interval_time" binding:"omitempty,min=1,max=60"`
+		Labels       entity.Labels `json:"labels"`
+	}
+
+	BulkOperationResponse struct {
+		Operation BulkOperation      `json:"operation"`
+		DryRun    bool               `json:"dry_run"`
+		Matched   int                `json:"matched"`
+		Applied   int                `json:"applied"`
+		Results   []BulkServerResult `json:"results"`
+	}
+
+	BulkServerResult struct {
+		ServerID string     `json:"server_id"`
+		Result   BulkResult `json:"result"`
+		Error    string     `json:"error,omitempty"`
+	}
+
+	StatusCount struct {
+		TenantID string
+		Status   entity.ServerStatus
+		Count    int64
+	}
+
+	HealthReport struct {
+		Status     HealthStatus               `json:"status"`
+		Components map[string]ComponentHealth `json:"components,omitempty"`
+	}
+
+	ComponentHealth struct {
+		Status    ComponentStatus `json:"status"`
+		LatencyMs float64         `json:"latency_ms"`
+		Error     string          `json:"error,omitempty"`
 	}
 
 	UpdateStatusMessage struct {
@@ -61,24 +141,43 @@ type (
 		Timestamp time.Time           `json:"timestamp"`
 	}
 
+	// HealthCheckRequestMessage asks a probe agent to check a server. Agents
+	// should drop requests received after Deadline, when the next one is due.
+	HealthCheckRequestMessage struct {
+		TenantID     string    `json:"tenant_id"`
+		ServerID     string    `json:"server_id"`
+		IPv4         string    `json:"ipv4"`
+		IntervalTime int       `json:"interval_time"`
+		RequestedAt  time.Time `json:"requested_at"`
+		Deadline     time.Time `json:"deadline"`
+	}
+
 	Claims struct {
-		Sub     uint     `json:"sub"`
-		Scopes  []string `json:"scopes"`
-		Blocked bool     `json:"blocked"`
+		Sub         uint     `json:"sub"`
+		TenantID    string   `json:"tenant_id"`
+		Scopes      []string `json:"scopes"`
+		Constraints []string `json:"constraints"`
+		Blocked     bool     `json:"blocked"`
 		jwt.RegisteredClaims
 	}
 )
 
 func ToServerResponse(server *entity.Server) *ServerResponse {
-	return &ServerResponse{
+	response := &ServerResponse{
 		ServerID:     server.ServerID,
 		ServerName:   server.ServerName,
 		IPv4:         server.IPv4,
 		Status:       server.Status,
 		Location:     server.Location,
 		OS:           server.OS,
+		Group:        server.Group,
+		Labels:       server.Labels,
 		IntervalTime: server.IntervalTime,
 	}
+	if server.DeletedAt.Valid {
+		response.DeletedAt = &server.DeletedAt.Time
+	}
+	return response
 }
 
 func ToServersResponse(servers []*entity.Server) []*ServerResponse {
@@ -0,0 +1,164 @@
+package dto
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+)
+
+const (
+	MinIntervalTime = 1
+	MaxIntervalTime = 60
+)
+
+// Patch is one member of an RFC 7396 merge patch. Set is false when the member
+// is absent, Null is true when it was explicitly sent as null.
+type Patch[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+func (p *Patch[T]) UnmarshalJSON(data []byte) error {
+	p.Set = true
+	if string(data) == "null" {
+		p.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &p.Value)
+}
+
+// PatchServerParams is a merge patch for a server. Nullable attributes are
+// cleared by null; labels are merged key by key and a null label removes it.
+type PatchServerParams struct {
+	ServerName   Patch[string]             `json:"server_name" swaggertype:"string"`
+	IPv4         Patch[string]             `json:"ipv4" swaggertype:"string"`
+	Location     Patch[string]             `json:"location" swaggertype:"string"`
+	OS           Patch[string]             `json:"os" swaggertype:"string"`
+	Group        Patch[string]             `json:"group" swaggertype:"string"`
+	IntervalTime Patch[int]                `json:"interval_time" swaggertype:"integer"`
+	Labels       Patch[map[string]*string] `json:"labels" swaggertype:"object,string"`
+}
+
+// UnmarshalJSON decodes member by member so type mismatches are reported per field.
+func (p *PatchServerParams) UnmarshalJSON(data []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	if members == nil {
+		return errors.New("merge patch must be a JSON object")
+	}
+
+	targets := map[string]json.Unmarshaler{
+		"server_name":   &p.ServerName,
+		"ipv4":          &p.IPv4,
+		"location":      &p.Location,
+		"os":            &p.OS,
+		"group":         &p.Group,
+		"interval_time": &p.IntervalTime,
+		"labels":        &p.Labels,
+	}
+
+	var fields []domain.FieldError
+	for key, raw := range members {
+		target, ok := targets[key]
+		if !ok {
+			continue
+		}
+		if err := target.UnmarshalJSON(raw); err != nil {
+			fields = append(fields, domain.FieldError{Field: key, Message: "has an invalid type"})
+		}
+	}
+
+	if len(fields) > 0 {
+		sort.Slice(fields, func(i, j int) bool { return fields[i].Field < fields[j].Field })
+		return domain.NewValidationError(fields...)
+	}
+	return nil
+}
+
+// Validate applies the same rules as CreateServerParams to the members present in the patch.
+func (p PatchServerParams) Validate() error {
+	var fields []domain.FieldError
+
+	if p.ServerName.Set && (p.ServerName.Null || strings.TrimSpace(p.ServerName.Value) == "") {
+		fields = append(fields, domain.FieldError{Field: "server_name", Message: "must not be empty"})
+	}
+	if p.IPv4.Set {
+		if p.IPv4.Null {
+			fields = append(fields, domain.FieldError{Field: "ipv4", Message: "must not be null"})
+		} else if ip := net.ParseIP(p.IPv4.Value); ip == nil || ip.To4() == nil || !strings.Contains(p.IPv4.Value, ".") {
+			fields = append(fields, domain.FieldError{Field: "ipv4", Message: "must be a valid IPv4 address"})
+		}
+	}
+	if p.IntervalTime.Set {
+		if p.IntervalTime.Null {
+			fields = append(fields, domain.FieldError{Field: "interval_time", Message: "must not be null"})
+		} else if p.IntervalTime.Value < MinIntervalTime || p.IntervalTime.Value > MaxIntervalTime {
+			fields = append(fields, domain.FieldError{
+				Field:   "interval_time",
+				Message: fmt.Sprintf("must be between %d and %d", MinIntervalTime, MaxIntervalTime),
+			})
+		}
+	}
+	if p.Labels.Set {
+		for key := range p.Labels.Value {
+			if strings.TrimSpace(key) == "" {
+				fields = append(fields, domain.FieldError{Field: "labels", Message: "label keys must not be empty"})
+				break
+			}
+		}
+	}
+
+	if len(fields) > 0 {
+		return domain.NewValidationError(fields...)
+	}
+	return nil
+}
+
+// Apply merges the patch into the server.
+func (p PatchServerParams) Apply(server *entity.Server) {
+	if p.ServerName.Set {
+		server.ServerName = p.ServerName.Value
+	}
+	if p.IPv4.Set {
+		server.IPv4 = p.IPv4.Value
+	}
+	if p.Location.Set {
+		server.Location = p.Location.Value
+	}
+	if p.OS.Set {
+		server.OS = p.OS.Value
+	}
+	if p.Group.Set {
+		server.Group = p.Group.Value
+	}
+	if p.IntervalTime.Set {
+		server.IntervalTime = p.IntervalTime.Value
+	}
+	if p.Labels.Set {
+		if p.Labels.Null {
+			server.Labels = entity.Labels{}
+			return
+		}
+		labels := make(entity.Labels, len(server.Labels)+len(p.Labels.Value))
+		for key, value := range server.Labels {
+			labels[key] = value
+		}
+		for key, value := range p.Labels.Value {
+			if value == nil {
+				delete(labels, key)
+				continue
+			}
+			labels[key] = *value
+		}
+		server.Labels = labels
+	}
+}
@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+)
+
+// ReportResponse describes a generated report file.
+type ReportResponse struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func ToReportsResponse(reports []*entity.Report) []ReportResponse {
+	responses := make([]ReportResponse, len(reports))
+	for i, report := range reports {
+		responses[i] = ReportResponse{
+			Name:      report.Name,
+			Size:      report.Size,
+			CreatedAt: report.CreatedAt,
+		}
+	}
+	return responses
+}
@@ -0,0 +1,44 @@
+package dto
+
+import "time"
+
+type (
+	// ServerStatsQuery sets the time windows of the statistics.
+	ServerStatsQuery struct {
+		CreatedSince  time.Time
+		ChangedSince  time.Time
+		FlappingSince time.Time
+		FlappingLimit int
+	}
+
+	// ValueCount is the number of servers sharing a value.
+	ValueCount struct {
+		Value string `json:"value"`
+		Count int64  `json:"count"`
+	}
+
+	// DailyCount is the number of servers created on a day (YYYY-MM-DD).
+	DailyCount struct {
+		Day   string `json:"day"`
+		Count int64  `json:"count"`
+	}
+
+	FlappingServer struct {
+		ServerID    string `json:"server_id"`
+		ServerName  string `json:"server_name"`
+		Transitions int64  `json:"transitions"`
+	}
+
+	// ServerStats summarises the live servers visible to the caller.
+	ServerStats struct {
+		Total           int64            `json:"total"`
+		ByStatus        []ValueCount     `json:"by_status"`
+		ByLocation      []ValueCount     `json:"by_location"`
+		ByOS            []ValueCount     `json:"by_os"`
+		ByInterval      []ValueCount     `json:"by_interval"`
+		CreatedPerDay   []DailyCount     `json:"created_per_day"`
+		ChangedLastHour int64            `json:"changed_last_hour"`
+		TopFlapping     []FlappingServer `json:"top_flapping"`
+		GeneratedAt     time.Time        `json:"generated_at"`
+	}
+)
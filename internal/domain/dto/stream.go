@@ -0,0 +1,75 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+)
+
+type ServerEventType string
+
+const (
+	ServerEventCreated       ServerEventType = "server.created"
+	ServerEventUpdated       ServerEventType = "server.updated"
+	ServerEventDeleted       ServerEventType = "server.deleted"
+	ServerEventRestored      ServerEventType = "server.restored"
+	ServerEventStatusChanged ServerEventType = "server.status_changed"
+
+	// StreamHeartbeat and StreamResync are sent by the stream itself. Resync
+	// precedes a disconnect for falling behind: events were lost and the
+	// client should reload the server list.
+	StreamHeartbeat ServerEventType = "stream.heartbeat"
+	StreamResync    ServerEventType = "stream.resync"
+)
+
+// ServerEvent is pushed to stream subscribers when a server changes. Server
+// holds its state after the change; for deletions, the last state before it.
+type ServerEvent struct {
+	ID             string              `json:"id"`
+	Type           ServerEventType     `json:"type"`
+	TenantID       string              `json:"tenant_id"`
+	Server         *ServerResponse     `json:"server"`
+	PreviousStatus entity.ServerStatus `json:"previous_status,omitempty"`
+	OccurredAt     time.Time           `json:"occurred_at"`
+}
+
+func NewServerEvent(eventType ServerEventType, server *entity.Server) ServerEvent {
+	return ServerEvent{
+		Type:       eventType,
+		TenantID:   server.TenantID,
+		Server:     ToServerResponse(server),
+		OccurredAt: time.Now(),
+	}
+}
+
+// Entity rebuilds the attributes stream filters and constraints match on.
+func (e ServerEvent) Entity() *entity.Server {
+	server := &entity.Server{TenantID: e.TenantID}
+	if e.Server != nil {
+		server.ServerID = e.Server.ServerID
+		server.ServerName = e.Server.ServerName
+		server.IPv4 = e.Server.IPv4
+		server.Status = e.Server.Status
+		server.Location = e.Server.Location
+		server.OS = e.Server.OS
+		server.Group = e.Server.Group
+		server.Labels = e.Server.Labels
+	}
+	return server
+}
+
+// Matches applies the filter to a single server the way GetServers applies it
+// to the table.
+func (f ServerFilterOptions) Matches(server *entity.Server) bool {
+	if f.ServerName != nil && !strings.Contains(server.ServerName, *f.ServerName) {
+		return false
+	}
+	if f.Status != nil && server.Status != *f.Status {
+		return false
+	}
+	if server.DeletedAt.Valid && !f.IncludeDeleted {
+		return false
+	}
+	return true
+}
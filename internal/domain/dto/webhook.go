@@ -0,0 +1,154 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+)
+
+type (
+	// CreateWebhookParams registers a webhook. A secret is generated when none
+	// is given; it is only returned by this call.
+	CreateWebhookParams struct {
+		URL       string        `json:"url" binding:"required,url,startswith=http"`
+		Secret    *string       `json:"secret" binding:"omitempty,min=16,max=128"`
+		ServerIDs []string      `json:"server_ids" binding:"omitempty,dive,required"`
+		Groups    []string      `json:"groups" binding:"omitempty,dive,required"`
+		Labels    entity.Labels `json:"labels"`
+	}
+
+	// UpdateWebhookParams replaces the given fields. Enabling a webhook resets
+	// its failure count.
+	UpdateWebhookParams struct {
+		URL       *string       `json:"url" binding:"omitempty,url,startswith=http"`
+		Secret    *string       `json:"secret" binding:"omitempty,min=16,max=128"`
+		ServerIDs []string      `json:"server_ids" binding:"omitempty,dive,required"`
+		Groups    []string      `json:"groups" binding:"omitempty,dive,required"`
+		Labels    entity.Labels `json:"labels"`
+		Enabled   *bool         `json:"enabled"`
+	}
+
+	WebhookResponse struct {
+		ID                  uint          `json:"id"`
+		URL                 string        `json:"url"`
+		Secret              string        `json:"secret,omitempty"`
+		ServerIDs           []string      `json:"server_ids"`
+		Groups              []string      `json:"groups"`
+		Labels              entity.Labels `json:"labels"`
+		Enabled             bool          `json:"enabled"`
+		ConsecutiveFailures int           `json:"consecutive_failures"`
+		DisabledAt          *time.Time    `json:"disabled_at,omitempty"`
+		CreatedAt           time.Time     `json:"created_at"`
+		UpdatedAt           time.Time     `json:"updated_at"`
+	}
+
+	WebhookDeliveryResponse struct {
+		ID         uint                `json:"id"`
+		EventID    string              `json:"event_id"`
+		Event      entity.WebhookEvent `json:"event"`
+		ServerID   string              `json:"server_id"`
+		Attempt    int                 `json:"attempt"`
+		StatusCode int                 `json:"status_code"`
+		Success    bool                `json:"success"`
+		Error      string              `json:"error,omitempty"`
+		DurationMs int64               `json:"duration_ms"`
+		CreatedAt  time.Time           `json:"created_at"`
+	}
+
+	// StatusChangedEvent is raised when a status report moves a server from
+	// one status to another.
+	StatusChangedEvent struct {
+		TenantID   string              `json:"tenant_id"`
+		ServerID   string              `json:"server_id"`
+		ServerName string              `json:"server_name"`
+		IPv4       string              `json:"ipv4"`
+		Location   string              `json:"location"`
+		OS         string              `json:"os"`
+		Group      string              `json:"group"`
+		Labels     entity.Labels       `json:"labels"`
+		Previous   entity.ServerStatus `json:"previous_status"`
+		Current    entity.ServerStatus `json:"status"`
+		ChangedAt  time.Time           `json:"changed_at"`
+	}
+
+	// WebhookPayload is the signed JSON body posted to webhooks.
+	WebhookPayload struct {
+		ID        string              `json:"id"`
+		Event     entity.WebhookEvent `json:"event"`
+		CreatedAt time.Time           `json:"created_at"`
+		Data      StatusChangedEvent  `json:"data"`
+	}
+)
+
+func NewStatusChangedEvent(server *entity.Server, previous entity.ServerStatus, changedAt time.Time) StatusChangedEvent {
+	return StatusChangedEvent{
+		TenantID:   server.TenantID,
+		ServerID:   server.ServerID,
+		ServerName: server.ServerName,
+		IPv4:       server.IPv4,
+		Location:   server.Location,
+		OS:         server.OS,
+		Group:      server.Group,
+		Labels:     server.Labels,
+		Previous:   previous,
+		Current:    server.Status,
+		ChangedAt:  changedAt,
+	}
+}
+
+// Server rebuilds the attributes webhook filters and constraints match on.
+func (e StatusChangedEvent) Server() *entity.Server {
+	return &entity.Server{
+		TenantID:   e.TenantID,
+		ServerID:   e.ServerID,
+		ServerName: e.ServerName,
+		IPv4:       e.IPv4,
+		Status:     e.Current,
+		Location:   e.Location,
+		OS:         e.OS,
+		Group:      e.Group,
+		Labels:     e.Labels,
+	}
+}
+
+func ToWebhookResponse(webhook *entity.Webhook) *WebhookResponse {
+	return &WebhookResponse{
+		ID:                  webhook.ID,
+		URL:                 webhook.URL,
+		ServerIDs:           webhook.ServerIDs,
+		Groups:              webhook.Groups,
+		Labels:              webhook.Labels,
+		Enabled:             webhook.Enabled,
+		ConsecutiveFailures: webhook.ConsecutiveFailures,
+		DisabledAt:          webhook.DisabledAt,
+		CreatedAt:           webhook.CreatedAt,
+		UpdatedAt:           webhook.UpdatedAt,
+	}
+}
+
+func ToWebhooksResponse(webhooks []*entity.Webhook) []*WebhookResponse {
+	responses := make([]*WebhookResponse, len(webhooks))
+	for i, webhook := range webhooks {
+		responses[i] = ToWebhookResponse(webhook)
+	}
+	return responses
+}
+
+func ToWebhookDeliveriesResponse(deliveries []*entity.WebhookDelivery) []*WebhookDeliveryResponse {
+	responses := make([]*WebhookDeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		responses[i] = &WebhookDeliveryResponse{
+			ID:         delivery.ID,
+			EventID:    delivery.EventID,
+			Event:      delivery.Event,
+			ServerID:   delivery.ServerID,
+			Attempt:    delivery.Attempt,
+			StatusCode: delivery.StatusCode,
+			Success:    delivery.Success,
+			Error:      delivery.Error,
+			DurationMs: delivery.DurationMs,
+			CreatedAt:  delivery.CreatedAt,
+		}
+	}
+	return responses
+}
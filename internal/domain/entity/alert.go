@@ -0,0 +1,62 @@
+package entity
+
+import "time"
+
+type (
+	AlertRule  string
+	AlertState string
+)
+
+const (
+	// AlertRuleOffline fires for a server offline longer than the threshold.
+	AlertRuleOffline AlertRule = "offline"
+	// AlertRuleGroupOffline fires when too large a share of a group is offline.
+	AlertRuleGroupOffline AlertRule = "group_offline"
+	// AlertRuleFlapping fires for a server changing status too often.
+	AlertRuleFlapping AlertRule = "flapping"
+
+	AlertStateFiring   AlertState = "firing"
+	AlertStateResolved AlertState = "resolved"
+)
+
+// Alert is raised by a rule for a subject: a server ID, or a group name for
+// AlertRuleGroupOffline.
+type Alert struct {
+	ID         uint       `gorm:"primaryKey"`
+	TenantID   string     `gorm:"not null;default:default"`
+	Rule       AlertRule  `gorm:"not null"`
+	Subject    string     `gorm:"not null"`
+	State      AlertState `gorm:"not null"`
+	Message    string     `gorm:"not null"`
+	Value      float64
+	StartedAt  time.Time `gorm:"not null"`
+	ResolvedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type StatusTransition struct {
+	ID             uint         `gorm:"primaryKey"`
+	TenantID       string       `gorm:"not null;default:default"`
+	ServerID       string       `gorm:"not null"`
+	PreviousStatus ServerStatus `gorm:"not null"`
+	Status         ServerStatus `gorm:"not null"`
+	ChangedAt      time.Time    `gorm:"not null"`
+}
+
+func (StatusTransition) TableName() string {
+	return "server_status_transitions"
+}
+
+// MaintenanceWindow suppresses new alerts for a server or a group between
+// StartsAt and EndsAt. Exactly one of ServerID and Group is set.
+type MaintenanceWindow struct {
+	ID        uint   `gorm:"primaryKey"`
+	TenantID  string `gorm:"not null;default:default"`
+	ServerID  string `gorm:"not null;default:''"`
+	Group     string `gorm:"column:group_name;not null;default:''"`
+	StartsAt  time.Time
+	EndsAt    time.Time
+	Reason    string
+	CreatedAt time.Time
+}
@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+// IdempotencyKey remembers the response to a request sent with an
+// Idempotency-Key header, so a retry with the same key gets the same answer.
+// StatusCode stays zero while the first request is still running, which it is
+// assumed to be until LockedUntil; after that another request may take the key
+// over.
+type IdempotencyKey struct {
+	TenantID    string `gorm:"primaryKey"`
+	Key         string `gorm:"column:idempotency_key;primaryKey"`
+	RequestHash string `gorm:"not null"`
+	StatusCode  int    `gorm:"not null;default:0"`
+	ContentType string `gorm:"not null"`
+	Body        []byte
+	CreatedAt   time.Time
+	LockedUntil time.Time `gorm:"not null"`
+	ExpiresAt   time.Time `gorm:"not null;index"`
+}
+
+// Completed reports whether the response of the first request was stored.
+func (k *IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
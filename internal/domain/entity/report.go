@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// ReportRun claims the report of a tenant for one scheduled run, so that only
+// one replica generates and emails it.
+type ReportRun struct {
+	TenantID  string    `gorm:"primaryKey"`
+	PeriodEnd time.Time `gorm:"primaryKey"`
+	ClaimedAt time.Time `gorm:"not null;autoCreateTime"`
+}
+
+// Report is a generated workbook of a tenant.
+type Report struct {
+	TenantID  string    `gorm:"primaryKey"`
+	Name      string    `gorm:"primaryKey"`
+	Size      int64     `gorm:"not null"`
+	Content   []byte    `gorm:"not null"`
+	CreatedAt time.Time `gorm:"not null;autoCreateTime"`
+}
@@ -2,6 +2,8 @@ package entity
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type ServerStatus string
@@ -13,13 +15,24 @@ const (
 )
 
 type Server struct {
-	ServerID     string       `gorm:"primaryKey"`
-	ServerName   string       `gorm:"not null;index;unique"`
-	IPv4         string       `gorm:"not null;unique"`
-	Status       ServerStatus `gorm:"not null;default:UNKNOWN"`
-	IntervalTime int          `gorm:"not null;default:5"`
-	Location     string
-	OS           string
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	TenantID        string       `gorm:"primaryKey;default:default;uniqueIndex:idx_servers_tenant_name;uniqueIndex:idx_servers_tenant_ipv4"`
+	ServerID        string       `gorm:"primaryKey"`
+	ServerName      string       `gorm:"not null;index;uniqueIndex:idx_servers_tenant_name"`
+	IPv4            string       `gorm:"not null;uniqueIndex:idx_servers_tenant_ipv4"`
+	Status          ServerStatus `gorm:"not null;default:UNKNOWN"`
+	IntervalTime    int          `gorm:"not null;default:5"`
+	Location        string
+	OS              string
+	Group           string `gorm:"column:group_name"`
+	Labels          Labels `gorm:"type:jsonb;not null;default:'{}'"`
+	StatusChangedAt *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	DeletedAt       gorm.DeletedAt `gorm:"index"`
+}
+
+// CheckInterval is the expected time between two status reports; IntervalTime
+// is expressed in seconds.
+func (s *Server) CheckInterval() time.Duration {
+	return time.Duration(s.IntervalTime) * time.Second
 }
@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+type WebhookEvent string
+
+const (
+	WebhookEventStatusChanged WebhookEvent = "server.status_changed"
+)
+
+// Webhook is a subscription to server status changes. Empty filters match
+// every server; non-empty ones must all match.
+type Webhook struct {
+	ID        uint       `gorm:"primaryKey"`
+	TenantID  string     `gorm:"not null;default:default;index"`
+	URL       string     `gorm:"not null"`
+	Secret    string     `gorm:"not null"`
+	ServerIDs StringList `gorm:"type:jsonb;not null;default:'[]'"`
+	Groups    StringList `gorm:"type:jsonb;not null;default:'[]'"`
+	Labels    Labels     `gorm:"type:jsonb;not null;default:'{}'"`
+	// Constraints are those of the caller that created or last updated the
+	// webhook, so it never receives servers that caller may not see.
+	Constraints         StringListMap `gorm:"type:jsonb;not null;default:'{}'"`
+	Enabled             bool          `gorm:"not null;default:true"`
+	ConsecutiveFailures int           `gorm:"not null;default:0"`
+	DisabledAt          *time.Time
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// Matches reports whether the server passes every filter of the webhook.
+func (w *Webhook) Matches(server *Server) bool {
+	if len(w.ServerIDs) > 0 && !slices.Contains(w.ServerIDs, server.ServerID) {
+		return false
+	}
+	if len(w.Groups) > 0 && !slices.Contains(w.Groups, server.Group) {
+		return false
+	}
+	for key, value := range w.Labels {
+		if server.Labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// WebhookDelivery is one attempt to deliver an event to a webhook.
+type WebhookDelivery struct {
+	ID         uint   `gorm:"primaryKey"`
+	TenantID   string `gorm:"not null;default:default"`
+	WebhookID  uint   `gorm:"not null;index"`
+	EventID    string `gorm:"not null"`
+	Event      WebhookEvent
+	ServerID   string `gorm:"not null"`
+	Attempt    int    `gorm:"not null"`
+	StatusCode int
+	Success    bool `gorm:"not null"`
+	Error      string
+	DurationMs int64
+	CreatedAt  time.Time
+}
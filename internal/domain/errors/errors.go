@@ -1,11 +1,141 @@
 package domain
 
-import "errors"
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/response"
+)
 
 var (
-	ErrInternalServer = errors.New("internal server error")
-	ErrServerExist    = errors.New("server already exists with the same name or ID")
-	ErrServerNotFound = errors.New("server not found")
+	ErrInternalServer = New(response.CodeInternalServerError, http.StatusInternalServerError, "internal server error")
+	ErrDatabase       = New(response.CodeDatabaseError, http.StatusInternalServerError, "database operation failed")
+
+	ErrInvalidRequest = New(response.CodeBadRequest, http.StatusBadRequest, "invalid request")
+	ErrValidation     = New(response.CodeValidationError, http.StatusBadRequest, "validation failed")
+
+	ErrUnauthorized      = New(response.CodeUnauthorized, http.StatusUnauthorized, "missing or malformed credentials")
+	ErrInvalidToken      = New(response.CodeInvalidToken, http.StatusUnauthorized, "invalid token")
+	ErrTokenExpired      = New(response.CodeTokenExpired, http.StatusUnauthorized, "token has expired")
+	ErrUserBlocked       = New(response.CodeForbidden, http.StatusForbidden, "user is blocked")
+	ErrInsufficientScope = New(response.CodeForbidden, http.StatusForbidden, "insufficient scope")
+
+	ErrServerExist    = New(response.CodeConflict, http.StatusConflict, "server already exists with the same name, ID or IPv4")
+	ErrServerNotFound = New(response.CodeNotFound, http.StatusNotFound, "server not found")
+	ErrServerDeleted  = New(response.CodeConflict, http.StatusConflict, "server has been deleted")
+	ErrForbidden      = New(response.CodeForbidden, http.StatusForbidden, "server is outside the caller's permitted resources")
+
+	ErrInvalidFile = New(response.CodeBadRequest, http.StatusBadRequest, "invalid file format or content")
+
+	ErrBulkLimitExceeded = New(response.CodeBadRequest, http.StatusBadRequest, "bulk operation exceeds the maximum number of servers")
+	ErrEmptyPatch        = New(response.CodeBadRequest, http.StatusBadRequest, "patch does not change any field")
 
-	ErrInvalidFile = errors.New("invalid file format or content")
+	ErrWebhookNotFound      = New(response.CodeNotFound, http.StatusNotFound, "webhook not found")
+	ErrWebhookURLNotAllowed = New(response.CodeValidationError, http.StatusBadRequest, "webhook URL must resolve to a public address")
+
+	ErrMaintenanceWindowNotFound = New(response.CodeNotFound, http.StatusNotFound, "maintenance window not found")
+
+	ErrReportNotFound   = New(response.CodeNotFound, http.StatusNotFound, "report not found")
+	ErrReportRestricted = New(response.CodeForbidden, http.StatusForbidden, "reports cover the whole inventory and are not available to restricted callers")
+
+	ErrIdempotencyKeyReused     = New(response.CodeIdempotencyKeyReused, http.StatusUnprocessableEntity, "idempotency key was already used with a different request")
+	ErrIdempotencyKeyInProgress = New(response.CodeConflict, http.StatusConflict, "a request with this idempotency key is still in progress")
+
+	ErrRateLimited = New(response.CodeTooManyRequests, http.StatusTooManyRequests, "rate limit exceeded, retry later")
+
+	ErrShuttingDown = New(response.CodeServiceUnavailable, http.StatusServiceUnavailable, "service is shutting down")
 )
+
+// FieldError describes why a single request field was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is a catalogued failure. Code, Status, Message, Fields and RetryAfter
+// are safe to show to the caller; the cause is kept for logs only.
+type Error struct {
+	Code    string
+	Status  int
+	Message string
+	Fields  []FieldError
+	// RetryAfter tells the caller how long to wait before trying again.
+	RetryAfter time.Duration
+
+	cause error
+	kind  *Error
+}
+
+// New registers a kind of error. Copies made by Wrap and WithFields still
+// match it with errors.Is.
+func New(code string, status int, message string) *Error {
+	return &Error{Code: code, Status: status, Message: message}
+}
+
+// NewValidationError reports every field that failed validation.
+func NewValidationError(fields ...FieldError) *Error {
+	return ErrValidation.WithFields(fields...)
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Message)
+	if len(e.Fields) > 0 {
+		messages := make([]string, 0, len(e.Fields))
+		for _, field := range e.Fields {
+			messages = append(messages, field.Field+" "+field.Message)
+		}
+		b.WriteString(": ")
+		b.WriteString(strings.Join(messages, "; "))
+	}
+	if e.cause != nil {
+		b.WriteString(": ")
+		b.WriteString(e.cause.Error())
+	}
+	return b.String()
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is matches errors of the same kind, whatever cause or fields they carry.
+func (e *Error) Is(target error) bool {
+	other, ok := target.(*Error)
+	return ok && e.root() == other.root()
+}
+
+// Wrap returns a copy of the error that records cause for logging.
+func (e *Error) Wrap(cause error) *Error {
+	copied := e.clone()
+	copied.cause = cause
+	return copied
+}
+
+// WithFields returns a copy of the error carrying the rejected fields.
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	copied := e.clone()
+	copied.Fields = append(append([]FieldError(nil), e.Fields...), fields...)
+	return copied
+}
+
+// WithRetryAfter returns a copy of the error asking the caller to wait.
+func (e *Error) WithRetryAfter(wait time.Duration) *Error {
+	copied := e.clone()
+	copied.RetryAfter = wait
+	return copied
+}
+
+func (e *Error) clone() *Error {
+	copied := *e
+	copied.kind = e.root()
+	return &copied
+}
+
+func (e *Error) root() *Error {
+	if e.kind != nil {
+		return e.kind
+	}
+	return e
+}
@@ -1,8 +1,16 @@
 package mq
 
+import "context"
+
 type Message struct {
 	Key     string
 	Headers map[string]string
 	Body    []byte
 	Topic   string
 }
+
+// Publisher sends messages to the broker. Usecases depend on it rather than on
+// the Kafka producer, which implements it.
+type Publisher interface {
+	Send(ctx context.Context, message Message) error
+}
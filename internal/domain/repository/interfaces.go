@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"time"
 
 	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
@@ -15,5 +16,94 @@ type ServerRepository interface {
 	Update(ctx context.Context, server *entity.Server) error
 	GetServers(ctx context.Context, filter dto.ServerFilterOptions, pagination dto.ServerPaginationOptions) ([]*entity.Server, int, error)
 	BatchCreate(ctx context.Context, servers []*entity.Server) ([]*string, error)
-	UpdateStatus(ctx context.Context, serverID string, status entity.ServerStatus) error
+	// UpdateStatus sets the status and returns the one it replaced.
+	UpdateStatus(ctx context.Context, serverID string, status entity.ServerStatus) (entity.ServerStatus, error)
+	GetDeletedByID(ctx context.Context, serverID string) (*entity.Server, error)
+	Restore(ctx context.Context, serverID string) error
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+	CountByStatus(ctx context.Context) ([]dto.StatusCount, error)
+	ListActive(ctx context.Context) ([]*entity.Server, error)
+	ListOfflineSince(ctx context.Context, before time.Time) ([]*entity.Server, error)
+	CountGroupByStatus(ctx context.Context, group string) ([]dto.StatusCount, error)
+	// GetStats aggregates the live servers visible to the caller.
+	GetStats(ctx context.Context, query dto.ServerStatsQuery) (*dto.ServerStats, error)
+
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+	GetByIDs(ctx context.Context, serverIDs []string) ([]*entity.Server, error)
+	BulkDelete(ctx context.Context, serverIDs []string) (int64, error)
+	BulkPatch(ctx context.Context, serverIDs []string, patch dto.BulkPatch) (int64, error)
+	BulkUpdateStatus(ctx context.Context, serverIDs []string, status entity.ServerStatus) (int64, error)
+}
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *entity.Webhook) error
+	GetByID(ctx context.Context, id uint) (*entity.Webhook, error)
+	List(ctx context.Context) ([]*entity.Webhook, error)
+	ListEnabled(ctx context.Context) ([]*entity.Webhook, error)
+	Update(ctx context.Context, webhook *entity.Webhook) error
+	Delete(ctx context.Context, id uint) error
+
+	RecordDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error
+	ListDeliveries(ctx context.Context, webhookID uint, limit int) ([]*entity.WebhookDelivery, error)
+	// MarkDelivered resets the consecutive failure count of the webhook.
+	MarkDelivered(ctx context.Context, id uint) error
+	// MarkFailed counts a failed delivery and disables the webhook once
+	// disableAfter consecutive deliveries have failed. It reports whether the
+	// webhook is now disabled.
+	MarkFailed(ctx context.Context, id uint, disableAfter int) (bool, error)
+}
+
+type AlertRepository interface {
+	RecordTransition(ctx context.Context, transition *entity.StatusTransition) error
+	CountTransitions(ctx context.Context, serverID string, since time.Time) (int64, error)
+	PruneTransitions(ctx context.Context, before time.Time) (int64, error)
+	// ListTransitionsSince returns the transitions of every tenant since the
+	// given time, oldest first.
+	ListTransitionsSince(ctx context.Context, since time.Time) ([]*entity.StatusTransition, error)
+
+	// Fire stores a firing alert unless one is already firing for the same
+	// rule and subject, and reports whether it was stored.
+	Fire(ctx context.Context, alert *entity.Alert) (bool, error)
+	// Resolve resolves the firing alert of the rule and subject, if any.
+	Resolve(ctx context.Context, rule entity.AlertRule, subject string, at time.Time) (bool, error)
+	// ListFiring returns the firing alerts of every tenant.
+	ListFiring(ctx context.Context) ([]*entity.Alert, error)
+	GetAlerts(ctx context.Context, filter dto.AlertFilterOptions, pagination dto.AlertPaginationOptions) ([]*entity.Alert, int, error)
+
+	CreateWindow(ctx context.Context, window *entity.MaintenanceWindow) error
+	ListWindows(ctx context.Context, includeExpired bool) ([]*entity.MaintenanceWindow, error)
+	DeleteWindow(ctx context.Context, id uint) error
+	// InMaintenance reports whether a window covering the server or its group
+	// is active at the given time.
+	InMaintenance(ctx context.Context, serverID, group string, at time.Time) (bool, error)
+}
+
+type IdempotencyRepository interface {
+	// Reserve stores the key unless an unexpired record already holds it,
+	// taking over a reservation whose lock expired. It returns the stored
+	// record and whether this call created it.
+	Reserve(ctx context.Context, key *entity.IdempotencyKey) (*entity.IdempotencyKey, bool, error)
+	// Complete stores the response of the request that reserved the key.
+	Complete(ctx context.Context, key *entity.IdempotencyKey) error
+	// Release drops a reservation so the request can be retried.
+	Release(ctx context.Context, key string) error
+	// PurgeExpired removes the keys of every tenant that expired before the given time.
+	PurgeExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+type ReportRepository interface {
+	// ClaimRun claims the report of the context's tenant for the run ending at
+	// periodEnd, and reports whether this call claimed it.
+	ClaimRun(ctx context.Context, periodEnd time.Time) (bool, error)
+	// ReleaseRun drops the claim of a run that failed so a later attempt can
+	// generate it.
+	ReleaseRun(ctx context.Context, periodEnd time.Time) error
+
+	// Save stores the report of the context's tenant, replacing one of the
+	// same name.
+	Save(ctx context.Context, report *entity.Report) error
+	// List returns the reports of the context's tenant without their
+	// content, newest first.
+	List(ctx context.Context) ([]*entity.Report, error)
+	GetByName(ctx context.Context, name string) (*entity.Report, error)
 }
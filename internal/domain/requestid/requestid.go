@@ -0,0 +1,41 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Header carries the request ID on HTTP requests, responses and Kafka messages.
+const Header = "X-Request-ID"
+
+const maxLength = 128
+
+type requestIDKey struct{}
+
+// New generates a fresh request ID.
+func New() string {
+	return uuid.New().String()
+}
+
+// Sanitize returns the incoming ID when it is safe to log and echo, or a fresh one.
+func Sanitize(id string) string {
+	if id == "" || len(id) > maxLength {
+		return New()
+	}
+	for _, r := range id {
+		if r < '!' || r > '~' {
+			return New()
+		}
+	}
+	return id
+}
+
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
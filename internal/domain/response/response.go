@@ -2,11 +2,12 @@ package response
 
 // APIResponse standard API response format
 type APIResponse struct {
-	Success bool        `json:"success"`
-	Code    string      `json:"code"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   *ErrorInfo  `json:"error,omitempty"`
+	Success   bool        `json:"success"`
+	Code      string      `json:"code"`
+	Message   string      `json:"message"`
+	Data      interface{} `json:"data,omitempty"`
+	Error     *ErrorInfo  `json:"error,omitempty"`
+	RequestID string      `json:"request_id,omitempty"`
 }
 
 // ErrorInfo contains error details
@@ -25,17 +26,20 @@ const (
 	CodeDeleted = "DELETED"
 
 	// Error codes
-	CodeBadRequest          = "BAD_REQUEST"
-	CodeUnauthorized        = "UNAUTHORIZED"
-	CodeForbidden           = "FORBIDDEN"
-	CodeNotFound            = "NOT_FOUND"
-	CodeConflict            = "CONFLICT"
-	CodeInternalServerError = "INTERNAL_SERVER_ERROR"
-	CodeValidationError     = "VALIDATION_ERROR"
-	CodeDatabaseError       = "DATABASE_ERROR"
-	CodeAuthError           = "AUTH_ERROR"
-	CodeTokenExpired        = "TOKEN_EXPIRED"
-	CodeInvalidToken        = "INVALID_TOKEN"
+	CodeBadRequest           = "BAD_REQUEST"
+	CodeUnauthorized         = "UNAUTHORIZED"
+	CodeForbidden            = "FORBIDDEN"
+	CodeNotFound             = "NOT_FOUND"
+	CodeConflict             = "CONFLICT"
+	CodeInternalServerError  = "INTERNAL_SERVER_ERROR"
+	CodeValidationError      = "VALIDATION_ERROR"
+	CodeDatabaseError        = "DATABASE_ERROR"
+	CodeAuthError            = "AUTH_ERROR"
+	CodeTokenExpired         = "TOKEN_EXPIRED"
+	CodeInvalidToken         = "INVALID_TOKEN"
+	CodeServiceUnavailable   = "SERVICE_UNAVAILABLE"
+	CodeIdempotencyKeyReused = "IDEMPOTENCY_KEY_REUSED"
+	CodeTooManyRequests      = "TOO_MANY_REQUESTS"
 )
 
 // NewSuccessResponse creates a new success response
@@ -1,9 +1,17 @@
 package srv
 
-import "github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+import (
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+)
 
 type XLSXService interface {
 	GetRows(filePath string) ([][]string, error)
 	Validate(row []string) error
 	Parse(row []string) (*entity.Server, error)
+	// Template returns an import workbook with the headers Validate expects.
+	Template() ([]byte, error)
+	// Export returns a workbook of the servers whose first sheet can be
+	// imported again.
+	Export(servers []*dto.ServerResponse) ([]byte, error)
 }
@@ -0,0 +1,47 @@
+package srv
+
+import (
+	"context"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+)
+
+// StatusNotifier is told about server status transitions. It runs on the
+// status update path, so anything slow belongs in the background.
+type StatusNotifier interface {
+	StatusChanged(ctx context.Context, event dto.StatusChangedEvent)
+}
+
+// StatusNotifiers passes every transition to each notifier in order.
+type StatusNotifiers []StatusNotifier
+
+func (n StatusNotifiers) StatusChanged(ctx context.Context, event dto.StatusChangedEvent) {
+	for _, notifier := range n {
+		notifier.StatusChanged(ctx, event)
+	}
+}
+
+// NotificationChannel delivers a rendered message to its recipients, such as
+// email addresses or phone numbers. Other gateways plug in by implementing it.
+type NotificationChannel interface {
+	Send(ctx context.Context, recipients []string, subject, body string) error
+}
+
+// Attachment is a file sent along with a message.
+type Attachment struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
+
+// AttachmentChannel is a NotificationChannel that can also deliver files.
+type AttachmentChannel interface {
+	NotificationChannel
+	SendWithAttachment(ctx context.Context, recipients []string, subject, body string, attachment Attachment) error
+}
+
+// ServerEventPublisher is told about every change to a server, including
+// status transitions. Like StatusNotifier it must not block.
+type ServerEventPublisher interface {
+	ServerChanged(ctx context.Context, event dto.ServerEvent)
+}
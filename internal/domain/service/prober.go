@@ -0,0 +1,13 @@
+package srv
+
+import (
+	"context"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+)
+
+// Prober checks whether a server is reachable. A nil error means the server
+// answered within the prober's timeout.
+type Prober interface {
+	Probe(ctx context.Context, server *entity.Server) error
+}
@@ -0,0 +1,16 @@
+package srv
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimiter keeps one token bucket per key. A bucket holds up to limit
+// tokens and refills limit tokens every period. The in-memory limiter suits a
+// single replica; a store shared by every replica can implement the same
+// interface.
+type RateLimiter interface {
+	// Allow takes a token from the bucket of key. When the bucket is empty it
+	// returns false and how long until the next token is available.
+	Allow(ctx context.Context, key string, limit int, period time.Duration) (bool, time.Duration, error)
+}
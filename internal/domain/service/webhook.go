@@ -0,0 +1,16 @@
+package srv
+
+import (
+	"context"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+)
+
+// WebhookSender posts a signed event to a webhook URL. It returns the response
+// status code, and an error for transport failures and non-2xx responses.
+type WebhookSender interface {
+	// CheckURL reports whether the URL may be registered: its host must only
+	// resolve to addresses deliveries are allowed to reach.
+	CheckURL(ctx context.Context, url string) error
+	Send(ctx context.Context, url, secret string, event entity.WebhookEvent, eventID string, body []byte) (int, error)
+}
@@ -0,0 +1,26 @@
+package tenant
+
+import "context"
+
+// Default is used for callers that carry no tenant, which keeps single-tenant
+// deployments working unchanged.
+const Default = "default"
+
+// Header is the message header carrying the tenant on Kafka messages.
+const Header = "X-Tenant-ID"
+
+type tenantKey struct{}
+
+func NewContext(ctx context.Context, tenantID string) context.Context {
+	if tenantID == "" {
+		tenantID = Default
+	}
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+func FromContext(ctx context.Context) string {
+	if tenantID, ok := ctx.Value(tenantKey{}).(string); ok && tenantID != "" {
+		return tenantID
+	}
+	return Default
+}
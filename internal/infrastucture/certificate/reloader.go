@@ -0,0 +1,174 @@
+package certificate
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"go.uber.org/zap"
+)
+
+// Reloader serves the server certificate and the client CAs from disk and
+// picks up new files, such as a renewed certificate, without a restart.
+type Reloader interface {
+	// TLSConfig returns a server configuration that always uses the latest
+	// certificate and client CAs.
+	TLSConfig() *tls.Config
+	// Start checks the files for changes on every tick until the context is
+	// cancelled. A failed reload keeps the previous certificate.
+	Start(ctx context.Context)
+}
+
+type reloader struct {
+	config config.TLS
+	logger *zap.Logger
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	versions  map[string]fileVersion
+}
+
+type fileVersion struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewReloader loads the certificate, key and client CA files once, so a
+// misconfiguration fails at startup.
+func NewReloader(config config.TLS, logger *zap.Logger) (Reloader, error) {
+	r := &reloader{config: config, logger: logger}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *reloader) TLSConfig() *tls.Config {
+	base := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: r.certificate,
+	}
+	if r.config.ClientCAFile == "" {
+		return base
+	}
+
+	clientAuth := tls.VerifyClientCertIfGiven
+	if r.config.RequireClientCert {
+		clientAuth = tls.RequireAndVerifyClientCert
+	}
+	base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+		return &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: r.certificate,
+			ClientAuth:     clientAuth,
+			ClientCAs:      r.clientCAs,
+		}, nil
+	}
+	return base
+}
+
+func (r *reloader) Start(ctx context.Context) {
+	if r.config.ReloadInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(r.config.ReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := r.changed()
+			if err != nil {
+				r.logger.Warn("Failed to check TLS files", zap.Error(err))
+				continue
+			}
+			if !changed {
+				continue
+			}
+			if err := r.reload(); err != nil {
+				r.logger.Error("Failed to reload TLS certificate, keeping the previous one", zap.Error(err))
+				continue
+			}
+			r.logger.Info("TLS certificate reloaded", zap.String("cert_file", r.config.CertFile))
+		}
+	}
+}
+
+func (r *reloader) certificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+func (r *reloader) reload() error {
+	versions, err := r.stat()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.config.CertFile, r.config.KeyFile)
+	if err != nil {
+		return fmt.Errorf("load certificate: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if r.config.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.config.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("read client CA: %w", err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("client CA file %q holds no PEM certificate", r.config.ClientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	r.versions = versions
+	return nil
+}
+
+func (r *reloader) changed() (bool, error) {
+	versions, err := r.stat()
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for file, version := range versions {
+		if r.versions[file] != version {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *reloader) stat() (map[string]fileVersion, error) {
+	versions := make(map[string]fileVersion)
+	for _, file := range []string{r.config.CertFile, r.config.KeyFile, r.config.ClientCAFile} {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		versions[file] = fileVersion{modTime: info.ModTime(), size: info.Size()}
+	}
+	return versions, nil
+}
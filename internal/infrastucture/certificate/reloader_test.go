@@ -0,0 +1,154 @@
+package certificate
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"go.uber.org/zap"
+)
+
+// writeCertificate writes a self-signed certificate for cn and its key, and
+// moves their modification time forward so a rewrite is always noticed.
+func writeCertificate(t *testing.T, certFile, keyFile, cn string, modTime time.Time) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+
+	files := map[string][]byte{
+		certFile: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyFile:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+	for file, content := range files {
+		if err := os.WriteFile(file, content, 0o600); err != nil {
+			t.Fatalf("write %s: %v", file, err)
+		}
+		if err := os.Chtimes(file, modTime, modTime); err != nil {
+			t.Fatalf("touch %s: %v", file, err)
+		}
+	}
+}
+
+func servedCommonName(t *testing.T, tlsConfig *tls.Config) string {
+	t.Helper()
+	cert, err := tlsConfig.GetCertificate(&tls.ClientHelloInfo{})
+	if err != nil {
+		t.Fatalf("GetCertificate() error = %v", err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+	return leaf.Subject.CommonName
+}
+
+func TestReloader_PicksUpRenewedCertificate(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.TLS{
+		CertFile:       filepath.Join(dir, "tls.crt"),
+		KeyFile:        filepath.Join(dir, "tls.key"),
+		ReloadInterval: 10 * time.Millisecond,
+	}
+	start := time.Now().Add(-time.Hour)
+	writeCertificate(t, cfg.CertFile, cfg.KeyFile, "first", start)
+
+	reloader, err := NewReloader(cfg, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewReloader() error = %v", err)
+	}
+	tlsConfig := reloader.TLSConfig()
+	if cn := servedCommonName(t, tlsConfig); cn != "first" {
+		t.Fatalf("got certificate %q, want %q", cn, "first")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go reloader.Start(ctx)
+
+	// A broken file keeps the previous certificate.
+	if err := os.WriteFile(cfg.CertFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("write certificate: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if cn := servedCommonName(t, tlsConfig); cn != "first" {
+		t.Fatalf("got certificate %q after a failed reload, want %q", cn, "first")
+	}
+
+	writeCertificate(t, cfg.CertFile, cfg.KeyFile, "renewed", start.Add(time.Minute))
+	deadline := time.Now().Add(2 * time.Second)
+	for servedCommonName(t, tlsConfig) != "renewed" {
+		if time.Now().After(deadline) {
+			t.Fatalf("renewed certificate was not picked up")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReloader_ClientCertificates(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.TLS{
+		CertFile:     filepath.Join(dir, "tls.crt"),
+		KeyFile:      filepath.Join(dir, "tls.key"),
+		ClientCAFile: filepath.Join(dir, "ca.crt"),
+	}
+	writeCertificate(t, cfg.CertFile, cfg.KeyFile, "server", time.Now())
+	writeCertificate(t, cfg.ClientCAFile, filepath.Join(dir, "ca.key"), "agents", time.Now())
+
+	for _, tc := range []struct {
+		require bool
+		want    tls.ClientAuthType
+	}{
+		{false, tls.VerifyClientCertIfGiven},
+		{true, tls.RequireAndVerifyClientCert},
+	} {
+		cfg.RequireClientCert = tc.require
+		reloader, err := NewReloader(cfg, zap.NewNop())
+		if err != nil {
+			t.Fatalf("NewReloader() error = %v", err)
+		}
+		perClient, err := reloader.TLSConfig().GetConfigForClient(&tls.ClientHelloInfo{})
+		if err != nil {
+			t.Fatalf("GetConfigForClient() error = %v", err)
+		}
+		if perClient.ClientAuth != tc.want || perClient.ClientCAs == nil {
+			t.Fatalf("got client auth %v with CAs %v, want %v", perClient.ClientAuth, perClient.ClientCAs, tc.want)
+		}
+	}
+
+	if err := os.WriteFile(cfg.ClientCAFile, []byte("garbage"), 0o600); err != nil {
+		t.Fatalf("write client CA: %v", err)
+	}
+	if _, err := NewReloader(cfg, zap.NewNop()); err == nil {
+		t.Fatalf("expected a client CA file without certificates to be rejected")
+	}
+}
@@ -0,0 +1,87 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+)
+
+// Checker reports whether a dependency is usable. Check must honour the
+// context deadline.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts a plain function to Checker.
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+type Service interface {
+	// Live reports that the process is up and serving; it checks no dependency.
+	Live() dto.HealthReport
+	// Ready runs every registered checker concurrently.
+	Ready(ctx context.Context) dto.HealthReport
+}
+
+type service struct {
+	timeout  time.Duration
+	checkers map[string]Checker
+}
+
+func NewService(timeout time.Duration, checkers map[string]Checker) Service {
+	return &service{
+		timeout:  timeout,
+		checkers: checkers,
+	}
+}
+
+func (s *service) Live() dto.HealthReport {
+	return dto.HealthReport{Status: dto.HealthStatusOK}
+}
+
+func (s *service) Ready(ctx context.Context) dto.HealthReport {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	report := dto.HealthReport{
+		Status:     dto.HealthStatusOK,
+		Components: make(map[string]dto.ComponentHealth, len(s.checkers)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for name, checker := range s.checkers {
+		wg.Add(1)
+		go func(name string, checker Checker) {
+			defer wg.Done()
+
+			start := time.Now()
+			err := checker.Check(ctx)
+			component := dto.ComponentHealth{
+				Status:    dto.ComponentStatusUp,
+				LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				component.Status = dto.ComponentStatusDown
+				component.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Components[name] = component
+			if err != nil {
+				report.Status = dto.HealthStatusUnavailable
+			}
+		}(name, checker)
+	}
+	wg.Wait()
+
+	return report
+}
@@ -0,0 +1,71 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+)
+
+func TestReady_AllUp(t *testing.T) {
+	s := NewService(time.Second, map[string]Checker{
+		"postgres": CheckerFunc(func(context.Context) error { return nil }),
+		"kafka":    CheckerFunc(func(context.Context) error { return nil }),
+	})
+
+	report := s.Ready(context.Background())
+	if report.Status != dto.HealthStatusOK {
+		t.Fatalf("expected ok, got %s", report.Status)
+	}
+	if len(report.Components) != 2 || report.Components["kafka"].Status != dto.ComponentStatusUp {
+		t.Fatalf("unexpected components: %+v", report.Components)
+	}
+}
+
+func TestReady_ComponentDown(t *testing.T) {
+	s := NewService(time.Second, map[string]Checker{
+		"postgres": CheckerFunc(func(context.Context) error { return errors.New("connection refused") }),
+		"consumer": CheckerFunc(func(context.Context) error { return nil }),
+	})
+
+	report := s.Ready(context.Background())
+	if report.Status != dto.HealthStatusUnavailable {
+		t.Fatalf("expected unavailable, got %s", report.Status)
+	}
+	postgres := report.Components["postgres"]
+	if postgres.Status != dto.ComponentStatusDown || postgres.Error != "connection refused" {
+		t.Fatalf("unexpected postgres status: %+v", postgres)
+	}
+	if report.Components["consumer"].Status != dto.ComponentStatusUp {
+		t.Fatalf("consumer should be up: %+v", report.Components["consumer"])
+	}
+}
+
+func TestReady_Timeout(t *testing.T) {
+	s := NewService(20*time.Millisecond, map[string]Checker{
+		"kafka": CheckerFunc(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+	})
+
+	report := s.Ready(context.Background())
+	if report.Status != dto.HealthStatusUnavailable {
+		t.Fatalf("expected unavailable, got %s", report.Status)
+	}
+	if report.Components["kafka"].Error != context.DeadlineExceeded.Error() {
+		t.Fatalf("unexpected error: %q", report.Components["kafka"].Error)
+	}
+}
+
+func TestLive_ChecksNothing(t *testing.T) {
+	s := NewService(time.Second, map[string]Checker{
+		"postgres": CheckerFunc(func(context.Context) error { return errors.New("down") }),
+	})
+
+	if report := s.Live(); report.Status != dto.HealthStatusOK || report.Components != nil {
+		t.Fatalf("unexpected live report: %+v", report)
+	}
+}
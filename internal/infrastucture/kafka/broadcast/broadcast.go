@@ -0,0 +1,164 @@
+package broadcast
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/mq"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/requestid"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/kafka/consumer"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.uber.org/zap"
+)
+
+// PARTITION_REFRESH_INTERVAL is how often the listener looks for partitions
+// added to the topic after it started.
+const PARTITION_REFRESH_INTERVAL = time.Minute
+
+// Listener delivers every message of a topic to every replica. Unlike a
+// consumer group it reads all partitions from the newest offset and commits
+// nothing, so a replica does not see what was published while it was down.
+type Listener interface {
+	// Listen calls handler for each message until ctx is cancelled. Handler
+	// errors are logged and the message is skipped.
+	Listen(ctx context.Context, handler consumer.HandlerFunc) error
+}
+
+type listener struct {
+	client   sarama.Client
+	consumer sarama.Consumer
+	topic    string
+	logger   *zap.Logger
+}
+
+func NewListener(cfg *config.Config, topic string, logger *zap.Logger) (Listener, error) {
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.ClientID = cfg.Kafka.ClientID
+	saramaConfig.Version = sarama.V2_6_0_0
+	saramaConfig.Consumer.Return.Errors = true
+
+	client, err := sarama.NewClient(cfg.Kafka.Address, saramaConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka client: %w", err)
+	}
+	saramaConsumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to create kafka consumer: %w", err)
+	}
+	return &listener{
+		client:   client,
+		consumer: saramaConsumer,
+		topic:    topic,
+		logger:   logger,
+	}, nil
+}
+
+func (l *listener) Listen(ctx context.Context, handler consumer.HandlerFunc) error {
+	var wg sync.WaitGroup
+	defer l.client.Close()
+	defer l.consumer.Close()
+	defer wg.Wait()
+
+	// The topic may not exist until the first event is published, and
+	// partitions added later are picked up on the next refresh.
+	consumed := make(map[int32]bool)
+	for {
+		if err := l.consumeNewPartitions(ctx, consumed, &wg, handler); err != nil {
+			l.logger.Warn("Failed to refresh broadcast partitions", zap.String("topic", l.topic), zap.Error(err))
+		}
+
+		wait := PARTITION_REFRESH_INTERVAL
+		if len(consumed) == 0 {
+			wait = 5 * time.Second
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+// consumeNewPartitions starts a consumer for each partition of the topic not
+// in consumed yet.
+func (l *listener) consumeNewPartitions(ctx context.Context, consumed map[int32]bool, wg *sync.WaitGroup, handler consumer.HandlerFunc) error {
+	if err := l.client.RefreshMetadata(l.topic); err != nil {
+		return fmt.Errorf("broadcast topic not available yet: %w", err)
+	}
+	partitions, err := l.client.Partitions(l.topic)
+	if err != nil {
+		return fmt.Errorf("failed to list partitions of %s: %w", l.topic, err)
+	}
+
+	// A partition added while listening holds only messages published since,
+	// so it is read from the start to not drop those before the refresh.
+	offset := sarama.OffsetNewest
+	if len(consumed) > 0 {
+		offset = sarama.OffsetOldest
+	}
+	for _, partition := range partitions {
+		if consumed[partition] {
+			continue
+		}
+		partitionConsumer, err := l.consumer.ConsumePartition(l.topic, partition, offset)
+		if err != nil {
+			return fmt.Errorf("failed to consume partition %d of %s: %w", partition, l.topic, err)
+		}
+		consumed[partition] = true
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.consumePartition(ctx, partitionConsumer, handler)
+		}()
+		l.logger.Info("Listening to broadcast partition", zap.String("topic", l.topic), zap.Int32("partition", partition))
+	}
+	return nil
+}
+
+func (l *listener) consumePartition(ctx context.Context, partitionConsumer sarama.PartitionConsumer, handler consumer.HandlerFunc) {
+	defer partitionConsumer.AsyncClose()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err, ok := <-partitionConsumer.Errors():
+			if ok {
+				l.logger.Error("Broadcast consumer error", zap.String("topic", l.topic), zap.Error(err))
+			}
+		case message, ok := <-partitionConsumer.Messages():
+			if !ok {
+				return
+			}
+			msg := toMessage(message)
+			msgCtx := otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(msg.Headers))
+			msgCtx = requestid.NewContext(msgCtx, requestid.Sanitize(msg.Headers[requestid.Header]))
+			if err := handler(msgCtx, msg); err != nil {
+				l.logger.Error("Failed to handle broadcast message",
+					zap.String("topic", message.Topic),
+					zap.Int32("partition", message.Partition),
+					zap.Int64("offset", message.Offset),
+					zap.Error(err))
+			}
+		}
+	}
+}
+
+func toMessage(message *sarama.ConsumerMessage) mq.Message {
+	headers := make(map[string]string, len(message.Headers))
+	for _, header := range message.Headers {
+		headers[string(header.Key)] = string(header.Value)
+	}
+	return mq.Message{
+		Key:     string(message.Key),
+		Headers: headers,
+		Body:    message.Value,
+		Topic:   message.Topic,
+	}
+}
@@ -0,0 +1,72 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+)
+
+// BrokerChecker verifies that at least one configured broker accepts a
+// connection and answers a metadata request.
+type BrokerChecker struct {
+	addresses []string
+	config    *sarama.Config
+}
+
+func NewBrokerChecker(cfg *config.Config) *BrokerChecker {
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.ClientID = cfg.Kafka.ClientID
+	saramaConfig.Net.DialTimeout = 2 * time.Second
+	saramaConfig.Net.ReadTimeout = 2 * time.Second
+	saramaConfig.Net.WriteTimeout = 2 * time.Second
+	saramaConfig.Version = sarama.V2_6_0_0
+
+	return &BrokerChecker{
+		addresses: cfg.Kafka.Address,
+		config:    saramaConfig,
+	}
+}
+
+func (b *BrokerChecker) Check(ctx context.Context) error {
+	if len(b.addresses) == 0 {
+		return errors.New("no kafka broker configured")
+	}
+
+	errs := make(chan error, len(b.addresses))
+	for _, address := range b.addresses {
+		go func(address string) {
+			errs <- b.checkBroker(address)
+		}(address)
+	}
+
+	var lastErr error
+	for range b.addresses {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errs:
+			if err == nil {
+				return nil
+			}
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+func (b *BrokerChecker) checkBroker(address string) error {
+	broker := sarama.NewBroker(address)
+	if err := broker.Open(b.config); err != nil {
+		return fmt.Errorf("broker %s: %w", address, err)
+	}
+	defer broker.Close()
+
+	if _, err := broker.GetMetadata(&sarama.MetadataRequest{Version: 1}); err != nil {
+		return fmt.Errorf("broker %s: %w", address, err)
+	}
+	return nil
+}
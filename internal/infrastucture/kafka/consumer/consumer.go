@@ -2,56 +2,107 @@ package consumer
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
-	"os/signal"
+	"strconv"
 	"sync"
-	"syscall"
+	"sync/atomic"
 	"time"
 
 	"github.com/IBM/sarama"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/mq"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/requestid"
 	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
-type HandlerFunc func(ctx context.Context, queueName string, payload []byte) error
+var tracer = otel.Tracer("github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/kafka/consumer")
+
+type HandlerFunc func(ctx context.Context, message mq.Message) error
 
 type consumerHandler struct {
 	handlerFunc HandlerFunc
 	logger      *zap.Logger
+	metrics     *metrics.Metrics
+	// sessions counts the group sessions currently joined, shared by every queue.
+	sessions *atomic.Int32
 }
 
 func newConsumerHandler(
 	handlerFunc HandlerFunc,
 	logger *zap.Logger,
+	metrics *metrics.Metrics,
+	sessions *atomic.Int32,
 ) *consumerHandler {
 	return &consumerHandler{
 		handlerFunc: handlerFunc,
 		logger:      logger,
+		metrics:     metrics,
+		sessions:    sessions,
 	}
 }
 
 func (h *consumerHandler) Setup(sarama.ConsumerGroupSession) error {
+	h.sessions.Add(1)
 	h.logger.Info("Consumer group session started")
 	return nil
 }
 
-func (h *consumerHandler) Cleanup(sarama.ConsumerGroupSession) error {
+// Cleanup commits the offsets marked during the session, so a rebalance or a
+// shutdown does not replay messages that were already processed.
+func (h *consumerHandler) Cleanup(session sarama.ConsumerGroupSession) error {
+	h.sessions.Add(-1)
+	session.Commit()
 	h.logger.Info("Consumer group session cleanup")
 	return nil
 }
 
 func (h *consumerHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
 	// Process messages
-	for message := range claim.Messages() {
+	for {
+		var message *sarama.ConsumerMessage
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			message = msg
+		case <-session.Context().Done():
+			return nil
+		}
+
 		h.logger.Debug("Processing message",
 			zap.String("topic", message.Topic),
 			zap.Int32("partition", message.Partition),
 			zap.Int64("offset", message.Offset))
 
+		h.metrics.StatusConsumed.WithLabelValues(message.Topic).Inc()
+		h.metrics.ConsumerLag.
+			WithLabelValues(message.Topic, strconv.Itoa(int(message.Partition))).
+			Set(float64(claim.HighWaterMarkOffset() - message.Offset - 1))
+
 		// Process message with retry logic
-		if err := h.processMessageWithRetry(session.Context(), message); err != nil {
+		start := time.Now()
+		err := h.processMessageWithRetry(session.Context(), message)
+		h.metrics.StatusProcessing.WithLabelValues(message.Topic).Observe(time.Since(start).Seconds())
+		if err != nil && session.Context().Err() != nil {
+			// Interrupted by shutdown or rebalance: leave the message unmarked
+			// so the next owner of the partition processes it again.
+			h.logger.Warn("Message processing interrupted",
+				zap.String("topic", message.Topic),
+				zap.Int64("offset", message.Offset),
+				zap.Error(err))
+			return nil
+		}
+		if err != nil {
+			h.metrics.StatusFailed.WithLabelValues(message.Topic).Inc()
 			h.logger.Error("Failed to process message after retries",
 				zap.String("topic", message.Topic),
 				zap.Int64("offset", message.Offset),
@@ -71,30 +122,56 @@ func (h *consumerHandler) ConsumeClaim(session sarama.ConsumerGroupSession, clai
 			session.Commit()
 		}
 	}
-
-	return nil
 }
 
-func (h *consumerHandler) processMessageWithRetry(ctx context.Context, message *sarama.ConsumerMessage) error {
+func (h *consumerHandler) processMessageWithRetry(ctx context.Context, message *sarama.ConsumerMessage) (err error) {
 	maxRetries := 3
 	var lastErr error
 
+	msg := toMessage(message)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(msg.Headers))
+	ctx, span := tracer.Start(ctx, message.Topic+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypeDeliver,
+			semconv.MessagingDestinationName(message.Topic),
+			semconv.MessagingDestinationPartitionID(strconv.Itoa(int(message.Partition))),
+			semconv.MessagingKafkaMessageOffset(int(message.Offset)),
+		))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	ctx = requestid.NewContext(ctx, requestid.Sanitize(msg.Headers[requestid.Header]))
+	logger := log.LoggerWithContext(ctx, h.logger)
+
+	// An attempt already running is allowed to finish during shutdown, only the
+	// wait before the next retry is cut short.
+	shutdown := ctx.Done()
+	ctx = context.WithoutCancel(ctx)
+
 	for i := 0; i < maxRetries; i++ {
 		if i > 0 {
 			// Exponential backoff
 			backoff := time.Duration(i*i) * time.Second
-			h.logger.Warn("Retrying message processing",
+			h.metrics.StatusRetried.WithLabelValues(message.Topic).Inc()
+			logger.Warn("Retrying message processing",
 				zap.Int("attempt", i+1),
 				zap.Duration("backoff", backoff))
 
 			select {
-			case <-ctx.Done():
-				return ctx.Err()
+			case <-shutdown:
+				return fmt.Errorf("retry aborted by shutdown: %w", lastErr)
 			case <-time.After(backoff):
 			}
 		}
 
-		if err := h.handlerFunc(ctx, message.Topic, message.Value); err != nil {
+		if err := h.handlerFunc(ctx, msg); err != nil {
 			lastErr = err
 			continue
 		}
@@ -105,25 +182,49 @@ func (h *consumerHandler) processMessageWithRetry(ctx context.Context, message *
 	return fmt.Errorf("failed after %d retries: %w", maxRetries, lastErr)
 }
 
+func toMessage(message *sarama.ConsumerMessage) mq.Message {
+	headers := make(map[string]string, len(message.Headers))
+	for _, header := range message.Headers {
+		headers[string(header.Key)] = string(header.Value)
+	}
+	return mq.Message{
+		Key:     string(message.Key),
+		Headers: headers,
+		Body:    message.Value,
+		Topic:   message.Topic,
+	}
+}
+
 type Consumer interface {
 	RegisterHandler(queueName string, handlerFunc HandlerFunc)
+	// Start consumes until the context is cancelled or Stop is called, then
+	// leaves the group after committing the marked offsets.
 	Start(ctx context.Context) error
-	Stop() error
+	// Stop asks Start to return and waits for it, or for ctx to expire.
+	Stop(ctx context.Context) error
+	// Healthy returns an error unless the consume loop is running and has
+	// joined the consumer group.
+	Healthy() error
 }
 
 type consumer struct {
 	saramaConsumer            sarama.ConsumerGroup
 	logger                    *zap.Logger
+	metrics                   *metrics.Metrics
 	queueNameToHandlerFuncMap map[string]HandlerFunc
 	cancelFunc                context.CancelFunc
+	done                      chan struct{}
 	wg                        sync.WaitGroup
 	mu                        sync.RWMutex
 	running                   bool
+	sessions                  atomic.Int32
+	lastErr                   atomic.Pointer[error]
 }
 
 func NewConsumer(
 	cfg *config.Config,
 	logger *zap.Logger,
+	metrics *metrics.Metrics,
 	consumerID string,
 ) (Consumer, error) {
 	config := sarama.NewConfig()
@@ -154,6 +255,7 @@ func NewConsumer(
 	return &consumer{
 		saramaConsumer:            saramaConsumer,
 		logger:                    logger,
+		metrics:                   metrics,
 		queueNameToHandlerFuncMap: make(map[string]HandlerFunc),
 	}, nil
 }
@@ -180,17 +282,16 @@ func (c *consumer) Start(ctx context.Context) error {
 		return fmt.Errorf("consumer already running")
 	}
 	c.running = true
-	c.mu.Unlock()
-
-	logger := log.LoggerWithContext(ctx, c.logger)
 
 	// Create a cancellable context
 	ctx, cancel := context.WithCancel(ctx)
 	c.cancelFunc = cancel
+	c.done = make(chan struct{})
+	done := c.done
+	c.mu.Unlock()
+	defer close(done)
 
-	// Handle OS signals for graceful shutdown
-	exitSignalChannel := make(chan os.Signal, 1)
-	signal.Notify(exitSignalChannel, os.Interrupt, syscall.SIGTERM)
+	logger := log.LoggerWithContext(ctx, c.logger)
 
 	// Start error handler
 	go func() {
@@ -208,7 +309,7 @@ func (c *consumer) Start(ctx context.Context) error {
 			logger.Info("Starting consumer for queue",
 				zap.String("queue_name", queueName))
 
-			handler := newConsumerHandler(handlerFunc, logger)
+			handler := newConsumerHandler(handlerFunc, logger, c.metrics, &c.sessions)
 
 			for {
 				// Check if context is cancelled
@@ -231,6 +332,7 @@ func (c *consumer) Start(ctx context.Context) error {
 						return
 					}
 
+					c.lastErr.Store(&err)
 					logger.Error("Failed to consume messages",
 						zap.String("queue_name", queueName),
 						zap.Error(err))
@@ -247,47 +349,61 @@ func (c *consumer) Start(ctx context.Context) error {
 		}(queueName, handlerFunc)
 	}
 
-	// Wait for signal or context cancellation
-	select {
-	case <-exitSignalChannel:
-		logger.Info("Received exit signal, shutting down...")
-		c.Stop()
-	case <-ctx.Done():
-		logger.Info("Context cancelled, shutting down...")
-		c.Stop()
-	}
+	<-ctx.Done()
+	logger.Info("Context cancelled, shutting down...")
 
-	// Wait for all goroutines to finish
+	// Wait for the sessions to end; their Cleanup commits the marked offsets
 	c.wg.Wait()
 
 	c.mu.Lock()
 	c.running = false
 	c.mu.Unlock()
 
+	// Close the consumer group
+	if err := c.saramaConsumer.Close(); err != nil {
+		logger.Error("Error closing consumer", zap.Error(err))
+		return err
+	}
+
 	logger.Info("Consumer stopped successfully")
 	return nil
 }
 
-func (c *consumer) Stop() error {
+func (c *consumer) Stop(ctx context.Context) error {
 	c.mu.RLock()
 	if !c.running {
 		c.mu.RUnlock()
 		return nil
 	}
+	cancel, done := c.cancelFunc, c.done
 	c.mu.RUnlock()
 
 	c.logger.Info("Stopping consumer...")
 
 	// Cancel context to stop all consumers
-	if c.cancelFunc != nil {
-		c.cancelFunc()
-	}
+	cancel()
 
-	// Close the consumer group
-	if err := c.saramaConsumer.Close(); err != nil {
-		c.logger.Error("Error closing consumer", zap.Error(err))
-		return err
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("consumer did not stop in time: %w", ctx.Err())
 	}
+}
 
-	return nil
+func (c *consumer) Healthy() error {
+	c.mu.RLock()
+	running := c.running
+	c.mu.RUnlock()
+
+	if !running {
+		return errors.New("consumer is not running")
+	}
+	if c.sessions.Load() > 0 {
+		return nil
+	}
+	if err := c.lastErr.Load(); err != nil {
+		return fmt.Errorf("consumer group session not established: %w", *err)
+	}
+	return errors.New("consumer group session not established")
 }
@@ -0,0 +1,130 @@
+package membership
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync/atomic"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"go.uber.org/zap"
+)
+
+// Membership splits keys across the replicas of the service. Every replica
+// joins the same consumer group on a shard topic; the partitions Kafka assigns
+// to a replica are the shards it owns, so each key has exactly one owner
+// between rebalances.
+type Membership interface {
+	// Start joins the group and tracks the assignment until ctx is cancelled.
+	Start(ctx context.Context) error
+	// Owns reports whether key falls in a shard assigned to this replica.
+	Owns(key string) bool
+}
+
+type assignment struct {
+	total      int32
+	partitions map[int32]bool
+}
+
+type membership struct {
+	client sarama.Client
+	group  sarama.ConsumerGroup
+	topic  string
+	logger *zap.Logger
+
+	current atomic.Pointer[assignment]
+}
+
+func NewMembership(cfg *config.Config, logger *zap.Logger) (Membership, error) {
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.ClientID = cfg.Kafka.ClientID
+	saramaConfig.Version = sarama.V2_6_0_0
+	saramaConfig.Consumer.Group.Rebalance.Strategy = sarama.BalanceStrategyRoundRobin
+	saramaConfig.Consumer.Offsets.Initial = sarama.OffsetNewest
+
+	client, err := sarama.NewClient(cfg.Kafka.Address, saramaConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka client: %w", err)
+	}
+	group, err := sarama.NewConsumerGroupFromClient(cfg.Scheduler.GroupID, client)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to create membership group: %w", err)
+	}
+
+	return &membership{
+		client: client,
+		group:  group,
+		topic:  cfg.Scheduler.ShardTopic,
+		logger: logger,
+	}, nil
+}
+
+func (m *membership) Start(ctx context.Context) error {
+	for ctx.Err() == nil {
+		if err := m.group.Consume(ctx, []string{m.topic}, m); err != nil && ctx.Err() == nil {
+			m.logger.Error("Membership session failed", zap.String("topic", m.topic), zap.Error(err))
+			select {
+			case <-ctx.Done():
+			case <-time.After(5 * time.Second):
+			}
+		}
+	}
+
+	m.current.Store(nil)
+	if err := m.group.Close(); err != nil {
+		return err
+	}
+	return m.client.Close()
+}
+
+func (m *membership) Owns(key string) bool {
+	current := m.current.Load()
+	if current == nil || current.total == 0 {
+		return false
+	}
+	return current.partitions[Shard(key, current.total)]
+}
+
+func (m *membership) Setup(session sarama.ConsumerGroupSession) error {
+	partitions, err := m.client.Partitions(m.topic)
+	if err != nil {
+		return err
+	}
+
+	owned := make(map[int32]bool)
+	for _, partition := range session.Claims()[m.topic] {
+		owned[partition] = true
+	}
+	m.current.Store(&assignment{total: int32(len(partitions)), partitions: owned})
+
+	m.logger.Info("Shard assignment updated",
+		zap.String("topic", m.topic),
+		zap.Int32s("owned", session.Claims()[m.topic]),
+		zap.Int("total", len(partitions)))
+	return nil
+}
+
+// Cleanup drops the assignment: during a rebalance no replica owns anything,
+// which may skip a beat but never schedules a key twice.
+func (m *membership) Cleanup(sarama.ConsumerGroupSession) error {
+	m.current.Store(nil)
+	return nil
+}
+
+// ConsumeClaim holds the claim for the session. The shard topic carries no
+// data; anything written to it is discarded.
+func (m *membership) ConsumeClaim(_ sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for range claim.Messages() {
+	}
+	return nil
+}
+
+// Shard maps a key to one of total partitions.
+func Shard(key string, total int32) int32 {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int32(h.Sum32() % uint32(total))
+}
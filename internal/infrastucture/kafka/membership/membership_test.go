@@ -0,0 +1,98 @@
+package membership
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"go.uber.org/zap"
+)
+
+// fakeClient embeds the interface; only Partitions is used by Setup.
+type fakeClient struct {
+	sarama.Client
+	partitions []int32
+}
+
+func (c *fakeClient) Partitions(topic string) ([]int32, error) {
+	return c.partitions, nil
+}
+
+// fakeSession embeds the interface; only Claims is used by Setup.
+type fakeSession struct {
+	sarama.ConsumerGroupSession
+	claims map[string][]int32
+}
+
+func (s *fakeSession) Claims() map[string][]int32 {
+	return s.claims
+}
+
+func newAssigned(t *testing.T, total int, owned ...int32) *membership {
+	t.Helper()
+	partitions := make([]int32, total)
+	for i := range partitions {
+		partitions[i] = int32(i)
+	}
+	m := &membership{
+		client: &fakeClient{partitions: partitions},
+		topic:  "shards",
+		logger: zap.NewNop(),
+	}
+	if err := m.Setup(&fakeSession{claims: map[string][]int32{"shards": owned}}); err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	return m
+}
+
+func TestOwns_WithoutAssignment(t *testing.T) {
+	m := &membership{topic: "shards", logger: zap.NewNop()}
+	if m.Owns("acme/s1") {
+		t.Fatal("a replica without an assignment must own nothing")
+	}
+}
+
+func TestOwns_SplitsKeysAcrossReplicas(t *testing.T) {
+	replicas := []*membership{
+		newAssigned(t, 4, 0, 2),
+		newAssigned(t, 4, 1),
+		newAssigned(t, 4, 3),
+	}
+
+	for i := 0; i < 200; i++ {
+		key := fmt.Sprintf("tenant/server-%d", i)
+		owners := 0
+		for _, replica := range replicas {
+			if replica.Owns(key) {
+				owners++
+			}
+		}
+		if owners != 1 {
+			t.Fatalf("key %q has %d owners, want exactly one", key, owners)
+		}
+	}
+}
+
+func TestOwns_DroppedOnCleanup(t *testing.T) {
+	m := newAssigned(t, 1, 0)
+	if !m.Owns("acme/s1") {
+		t.Fatal("the only replica must own every key")
+	}
+
+	// During a rebalance nothing is owned, so no key is scheduled twice.
+	if err := m.Cleanup(&fakeSession{}); err != nil {
+		t.Fatalf("cleanup: %v", err)
+	}
+	if m.Owns("acme/s1") {
+		t.Fatal("a replica must own nothing after cleanup")
+	}
+}
+
+func TestShard_Stable(t *testing.T) {
+	for _, total := range []int32{1, 3, 16} {
+		first := Shard("acme/s1", total)
+		if first < 0 || first >= total || Shard("acme/s1", total) != first {
+			t.Fatalf("shard %d out of range or unstable for %d partitions", first, total)
+		}
+	}
+}
@@ -1,15 +1,30 @@
 package producer
 
 import (
+	"context"
+	"maps"
+
 	"github.com/IBM/sarama"
 	"github.com/google/wire"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/mq"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/requestid"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
+var tracer = otel.Tracer("github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/kafka/producer")
+
 type MessageBroker interface {
-	Send(message mq.Message) error
+	mq.Publisher
+	// Close flushes and releases the producer.
+	Close() error
 }
 
 type messageBroker struct {
@@ -32,9 +47,37 @@ func NewBroker(cfg *config.Config, logger *zap.Logger) (MessageBroker, error) {
 
 var MessageBrokerSet = wire.NewSet(NewBroker)
 
-func (d messageBroker) Send(event mq.Message) error {
+// Send publishes the message, adding the request ID and tenant from the context
+// to its headers unless the caller already set them. The trace context of the
+// publish span is always injected.
+func (d messageBroker) Send(ctx context.Context, event mq.Message) error {
 	var headers []sarama.RecordHeader
 
+	ctx, span := tracer.Start(ctx, event.Topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypePublish,
+			semconv.MessagingDestinationName(event.Topic),
+		))
+	defer span.End()
+
+	// The caller may reuse the message or send it concurrently, so its
+	// headers are copied rather than written to.
+	event.Headers = maps.Clone(event.Headers)
+	if event.Headers == nil {
+		event.Headers = make(map[string]string)
+	}
+	if _, ok := event.Headers[requestid.Header]; !ok {
+		if id := requestid.FromContext(ctx); id != "" {
+			event.Headers[requestid.Header] = id
+		}
+	}
+	if _, ok := event.Headers[tenant.Header]; !ok {
+		event.Headers[tenant.Header] = tenant.FromContext(ctx)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(event.Headers))
+
 	for k, v := range event.Headers {
 		headers = append(headers, sarama.RecordHeader{
 			Key:   sarama.ByteEncoder(k),
@@ -47,13 +90,24 @@ func (d messageBroker) Send(event mq.Message) error {
 		Value:   sarama.ByteEncoder(event.Body),
 		Headers: headers,
 	}
+	if event.Key != "" {
+		msg.Key = sarama.StringEncoder(event.Key)
+	}
 
-	d.logger.Info("Sending message to broker",
+	log.LoggerWithContext(ctx, d.logger).Info("Sending message to broker",
 		zap.String("topic", event.Topic),
 		zap.ByteString("body", event.Body),
 	)
 
 	_, _, err := d.producer.SendMessage(msg)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
 
 	return err
 }
+
+func (d messageBroker) Close() error {
+	return d.producer.Close()
+}
@@ -0,0 +1,72 @@
+package producer
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/mq"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	"go.uber.org/zap"
+)
+
+func expectTenant(want string) mocks.MessageChecker {
+	return func(msg *sarama.ProducerMessage) error {
+		for _, header := range msg.Headers {
+			if string(header.Key) == tenant.Header {
+				if string(header.Value) != want {
+					return fmt.Errorf("got tenant header %q, want %q", header.Value, want)
+				}
+				return nil
+			}
+		}
+		return fmt.Errorf("message has no %s header", tenant.Header)
+	}
+}
+
+func TestSend_TenantHeader(t *testing.T) {
+	cases := []struct {
+		name    string
+		ctx     context.Context
+		headers map[string]string
+		want    string
+	}{
+		{"from context", tenant.NewContext(context.Background(), "acme"), nil, "acme"},
+		{"default without tenant", context.Background(), nil, tenant.Default},
+		{"caller header wins", tenant.NewContext(context.Background(), "acme"), map[string]string{tenant.Header: "other"}, "other"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			producer := mocks.NewSyncProducer(t, nil)
+			producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(expectTenant(tc.want))
+			broker := &messageBroker{producer: producer, logger: zap.NewNop()}
+
+			if err := broker.Send(tc.ctx, mq.Message{Topic: "server.status", Headers: tc.headers}); err != nil {
+				t.Fatalf("send: %v", err)
+			}
+			if err := broker.Close(); err != nil {
+				t.Fatalf("close: %v", err)
+			}
+		})
+	}
+}
+
+func TestSend_LeavesCallerHeaders(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, nil)
+	producer.ExpectSendMessageAndSucceed()
+	broker := &messageBroker{producer: producer, logger: zap.NewNop()}
+
+	headers := map[string]string{"kind": "status"}
+	ctx := tenant.NewContext(context.Background(), "acme")
+	if err := broker.Send(ctx, mq.Message{Topic: "server.status", Headers: headers}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if err := broker.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if len(headers) != 1 || headers["kind"] != "status" {
+		t.Fatalf("caller headers changed: %v", headers)
+	}
+}
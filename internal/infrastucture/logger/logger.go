@@ -6,6 +6,8 @@ import (
 
 	"github.com/google/wire"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/requestid"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"gopkg.in/natefinch/lumberjack.v2"
@@ -82,7 +84,15 @@ func getZapLoggerLevel(level string) zap.AtomicLevel {
 	}
 }
 
-func LoggerWithContext(_ context.Context, logger *zap.Logger) *zap.Logger {
-	//TODO: Add request ID to context
+// LoggerWithContext tags the logger with the request ID and the trace carried by the context.
+func LoggerWithContext(ctx context.Context, logger *zap.Logger) *zap.Logger {
+	if id := requestid.FromContext(ctx); id != "" {
+		logger = logger.With(zap.String("request_id", id))
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		logger = logger.With(
+			zap.String("trace_id", spanContext.TraceID().String()),
+			zap.String("span_id", spanContext.SpanID().String()))
+	}
 	return logger
 }
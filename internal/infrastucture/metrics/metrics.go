@@ -0,0 +1,193 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+)
+
+const namespace = "server_service"
+
+var MetricsSet = wire.NewSet(NewMetrics)
+
+// Metrics holds every collector exposed on /metrics. Each instance owns its
+// registry so tests can build as many as they need.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	HTTPRequests        *prometheus.CounterVec
+	HTTPRequestDuration *prometheus.HistogramVec
+
+	StatusConsumed   *prometheus.CounterVec
+	StatusFailed     *prometheus.CounterVec
+	StatusRetried    *prometheus.CounterVec
+	StatusIgnored    *prometheus.CounterVec
+	StatusProcessing *prometheus.HistogramVec
+	ConsumerLag      *prometheus.GaugeVec
+
+	ImportRows *prometheus.CounterVec
+
+	ProbeDuration *prometheus.HistogramVec
+
+	DBQueryDuration *prometheus.HistogramVec
+}
+
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+
+		HTTPRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+		HTTPRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+
+		StatusConsumed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "status_messages_consumed_total",
+			Help:      "Status messages consumed from Kafka.",
+		}, []string{"topic"}),
+		StatusFailed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "status_messages_failed_total",
+			Help:      "Status messages that failed after all retries.",
+		}, []string{"topic"}),
+		StatusRetried: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "status_messages_retried_total",
+			Help:      "Retry attempts made while processing status messages.",
+		}, []string{"topic"}),
+		StatusIgnored: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "status_messages_ignored_total",
+			Help:      "Status messages dropped without updating a server, by reason.",
+		}, []string{"reason"}),
+		StatusProcessing: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "status_message_processing_seconds",
+			Help:      "Time spent processing a status message, retries included.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"topic"}),
+		ConsumerLag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "consumer_lag_messages",
+			Help:      "Messages between the last processed offset and the partition high water mark.",
+		}, []string{"topic", "partition"}),
+
+		ImportRows: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "import_rows_total",
+			Help:      "Rows processed by server imports, by result.",
+		}, []string{"result"}),
+
+		ProbeDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "probe_duration_seconds",
+			Help:      "Active probe latency by resulting status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"status"}),
+
+		DBQueryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "GORM query latency by operation and table.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation", "table"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.HTTPRequests,
+		m.HTTPRequestDuration,
+		m.StatusConsumed,
+		m.StatusFailed,
+		m.StatusRetried,
+		m.StatusIgnored,
+		m.StatusProcessing,
+		m.ConsumerLag,
+		m.ImportRows,
+		m.ProbeDuration,
+		m.DBQueryDuration,
+	)
+	return m
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// RegisterDBStats exposes the connection pool statistics of the database.
+func (m *Metrics) RegisterDBStats(db *sql.DB, name string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// ServerCounter reports live servers grouped by tenant and status. It must
+// count every tenant: scrapes carry no tenant, and one scrape covers them all.
+type ServerCounter interface {
+	CountByStatus(ctx context.Context) ([]dto.StatusCount, error)
+}
+
+// RegisterServerCounts exposes server counts computed at scrape time, one
+// series per status. With perTenant the series are also split by tenant, so
+// the tenants become visible to whoever can scrape; sum over tenant for
+// deployment-wide totals.
+func (m *Metrics) RegisterServerCounts(counter ServerCounter, perTenant bool) {
+	help, labels := "Live servers by status.", []string{"status"}
+	if perTenant {
+		help, labels = "Live servers by tenant and status.", []string{"tenant", "status"}
+	}
+	m.registry.MustRegister(&serverCountCollector{
+		counter:   counter,
+		perTenant: perTenant,
+		desc:      prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "servers"), help, labels, nil),
+	})
+}
+
+type serverCountCollector struct {
+	counter   ServerCounter
+	perTenant bool
+	desc      *prometheus.Desc
+}
+
+func (c *serverCountCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *serverCountCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	counts, err := c.counter.CountByStatus(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+	if c.perTenant {
+		for _, count := range counts {
+			ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count.Count), count.TenantID, string(count.Status))
+		}
+		return
+	}
+
+	totals := make(map[string]int64)
+	for _, count := range counts {
+		totals[string(count.Status)] += count.Count
+	}
+	for status, total := range totals {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(total), status)
+	}
+}
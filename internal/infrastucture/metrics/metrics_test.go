@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+)
+
+type fakeCounter struct {
+	counts []dto.StatusCount
+	err    error
+}
+
+func (f *fakeCounter) CountByStatus(ctx context.Context) ([]dto.StatusCount, error) {
+	return f.counts, f.err
+}
+
+var tenantCounts = []dto.StatusCount{
+	{TenantID: "acme", Status: entity.ServerStatusOnline, Count: 3},
+	{TenantID: "acme", Status: entity.ServerStatusOffline, Count: 1},
+	{TenantID: "default", Status: entity.ServerStatusOnline, Count: 2},
+}
+
+func TestServerCounts_HideTenants(t *testing.T) {
+	m := NewMetrics()
+	m.RegisterServerCounts(&fakeCounter{counts: tenantCounts}, false)
+
+	expected := `
+# HELP server_service_servers Live servers by status.
+# TYPE server_service_servers gauge
+server_service_servers{status="OFFLINE"} 1
+server_service_servers{status="ONLINE"} 5
+`
+	if err := testutil.GatherAndCompare(m.registry, strings.NewReader(expected), "server_service_servers"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServerCounts_PerTenant(t *testing.T) {
+	m := NewMetrics()
+	m.RegisterServerCounts(&fakeCounter{counts: tenantCounts}, true)
+
+	expected := `
+# HELP server_service_servers Live servers by tenant and status.
+# TYPE server_service_servers gauge
+server_service_servers{status="OFFLINE",tenant="acme"} 1
+server_service_servers{status="ONLINE",tenant="acme"} 3
+server_service_servers{status="ONLINE",tenant="default"} 2
+`
+	if err := testutil.GatherAndCompare(m.registry, strings.NewReader(expected), "server_service_servers"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServerCounts_Error(t *testing.T) {
+	m := NewMetrics()
+	m.RegisterServerCounts(&fakeCounter{err: errors.New("database down")}, false)
+
+	if _, err := m.registry.Gather(); err == nil || !strings.Contains(err.Error(), "database down") {
+		t.Fatalf("want the counter error on gather, got %v", err)
+	}
+}
+
+func TestHandler_ExposesCollectors(t *testing.T) {
+	m := NewMetrics()
+	m.HTTPRequests.WithLabelValues("GET", "/server/:id", "200").Inc()
+	m.StatusIgnored.WithLabelValues("deleted").Inc()
+
+	if got := testutil.ToFloat64(m.HTTPRequests.WithLabelValues("GET", "/server/:id", "200")); got != 1 {
+		t.Fatalf("got %v requests, want 1", got)
+	}
+	if count, err := testutil.GatherAndCount(m.registry, "server_service_http_requests_total", "server_service_status_messages_ignored_total"); err != nil || count != 2 {
+		t.Fatalf("got %d series (%v), want 2", count, err)
+	}
+}
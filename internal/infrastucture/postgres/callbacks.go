@@ -0,0 +1,32 @@
+package postgres
+
+import "gorm.io/gorm"
+
+// registerCallbacks hooks before and after every GORM operation, so plugins can
+// measure or trace each statement.
+func registerCallbacks(db *gorm.DB, plugin string, before func(*gorm.DB), after func(operation string) func(*gorm.DB)) error {
+	callback := db.Callback()
+
+	registrations := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", callback.Create().Before("gorm:create").Register, callback.Create().After("gorm:create").Register},
+		{"query", callback.Query().Before("gorm:query").Register, callback.Query().After("gorm:query").Register},
+		{"update", callback.Update().Before("gorm:update").Register, callback.Update().After("gorm:update").Register},
+		{"delete", callback.Delete().Before("gorm:delete").Register, callback.Delete().After("gorm:delete").Register},
+		{"row", callback.Row().Before("gorm:row").Register, callback.Row().After("gorm:row").Register},
+		{"raw", callback.Raw().Before("gorm:raw").Register, callback.Raw().After("gorm:raw").Register},
+	}
+
+	for _, r := range registrations {
+		if err := r.before(plugin+":before_"+r.operation, before); err != nil {
+			return err
+		}
+		if err := r.after(plugin+":after_"+r.operation, after(r.operation)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,7 +1,13 @@
 package postgres
 
-import "gorm.io/gorm"
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
 
 type DBEngine interface {
 	GetDB() *gorm.DB
+	Ping(ctx context.Context) error
+	Close() error
 }
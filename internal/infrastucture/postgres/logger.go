@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+	"gorm.io/gorm/utils"
+)
+
+const slowQueryThreshold = 200 * time.Millisecond
+
+// zapGormLogger routes GORM output through zap so every query log line carries
+// the request ID of the context it ran under.
+type zapGormLogger struct {
+	logger *zap.Logger
+	level  gormLogger.LogLevel
+}
+
+func newZapGormLogger(logger *zap.Logger, level gormLogger.LogLevel) gormLogger.Interface {
+	return &zapGormLogger{
+		logger: logger,
+		level:  level,
+	}
+}
+
+func (l *zapGormLogger) LogMode(level gormLogger.LogLevel) gormLogger.Interface {
+	return &zapGormLogger{logger: l.logger, level: level}
+}
+
+func (l *zapGormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormLogger.Info {
+		log.LoggerWithContext(ctx, l.logger).Info(fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *zapGormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormLogger.Warn {
+		log.LoggerWithContext(ctx, l.logger).Warn(fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *zapGormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormLogger.Error {
+		log.LoggerWithContext(ctx, l.logger).Error(fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *zapGormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= gormLogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	sql, rows := fc()
+	logger := log.LoggerWithContext(ctx, l.logger).With(
+		zap.String("sql", sql),
+		zap.String("source", utils.FileWithLineNum()),
+		zap.Int64("rows", rows),
+		zap.Duration("elapsed", elapsed),
+	)
+
+	switch {
+	case err != nil && l.level >= gormLogger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		logger.Error("Query failed", zap.Error(err))
+	case elapsed > slowQueryThreshold && l.level >= gormLogger.Warn:
+		logger.Warn("Slow query")
+	case l.level >= gormLogger.Info:
+		logger.Debug("Query executed")
+	}
+}
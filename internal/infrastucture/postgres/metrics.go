@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+)
+
+const queryStartKey = "metrics:query_start"
+
+// queryMetricsPlugin records the duration of every GORM operation.
+type queryMetricsPlugin struct {
+	duration *prometheus.HistogramVec
+}
+
+func (p *queryMetricsPlugin) Name() string {
+	return "query_metrics"
+}
+
+func (p *queryMetricsPlugin) Initialize(db *gorm.DB) error {
+	return registerCallbacks(db, "metrics", p.start, p.observe)
+}
+
+func (p *queryMetricsPlugin) start(db *gorm.DB) {
+	db.InstanceSet(queryStartKey, time.Now())
+}
+
+func (p *queryMetricsPlugin) observe(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(queryStartKey)
+		if !ok {
+			return
+		}
+		start, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+		p.duration.WithLabelValues(operation, db.Statement.Table).Observe(time.Since(start).Seconds())
+	}
+}
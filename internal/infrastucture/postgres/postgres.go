@@ -1,9 +1,11 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/metrics"
 	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -14,7 +16,7 @@ type postgresDB struct {
 	db *gorm.DB
 }
 
-func NewPostgresDB(cfg *config.Config, logger *zap.Logger) (DBEngine, error) {
+func NewPostgresDB(cfg *config.Config, logger *zap.Logger, metrics *metrics.Metrics) (DBEngine, error) {
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=disable",
 		cfg.Postgres.Host,
 		cfg.Postgres.User,
@@ -23,18 +25,29 @@ func NewPostgresDB(cfg *config.Config, logger *zap.Logger) (DBEngine, error) {
 		cfg.Postgres.Port)
 
 	gormDB, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: gormLogger.Default.LogMode(gormLogger.Info),
+		Logger: newZapGormLogger(logger, gormLogger.Info),
+		// Unique violations surface as gorm.ErrDuplicatedKey, so usecases can
+		// report them as conflicts without knowing Postgres error codes.
+		TranslateError: true,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	if err := gormDB.Use(&queryMetricsPlugin{duration: metrics.DBQueryDuration}); err != nil {
+		return nil, fmt.Errorf("failed to register query metrics: %w", err)
+	}
+	if err := gormDB.Use(&queryTracingPlugin{}); err != nil {
+		return nil, fmt.Errorf("failed to register query tracing: %w", err)
+	}
+
 	sqlDB, err := gormDB.DB()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get database instance: %w", err)
 	}
 	sqlDB.SetMaxIdleConns(cfg.Postgres.MaxIdleConns)
 	sqlDB.SetMaxOpenConns(cfg.Postgres.MaxOpenConns)
+	metrics.RegisterDBStats(sqlDB, cfg.Postgres.DBName)
 
 	logger.Info("Database connected successfully",
 		zap.String("host", cfg.Postgres.Host),
@@ -47,3 +60,20 @@ func NewPostgresDB(cfg *config.Config, logger *zap.Logger) (DBEngine, error) {
 func (p *postgresDB) GetDB() *gorm.DB {
 	return p.db
 }
+
+// Ping checks that a connection to the database can be used.
+func (p *postgresDB) Ping(ctx context.Context) error {
+	sqlDB, err := p.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+func (p *postgresDB) Close() error {
+	sqlDB, err := p.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
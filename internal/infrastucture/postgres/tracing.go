@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const querySpanKey = "tracing:query_span"
+
+var tracer = otel.Tracer("github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/postgres")
+
+// queryTracingPlugin opens a client span around every GORM operation, as a
+// child of the span carried by the statement context.
+type queryTracingPlugin struct{}
+
+func (p *queryTracingPlugin) Name() string {
+	return "query_tracing"
+}
+
+func (p *queryTracingPlugin) Initialize(db *gorm.DB) error {
+	return registerCallbacks(db, "tracing", p.start, p.end)
+}
+
+func (p *queryTracingPlugin) start(db *gorm.DB) {
+	ctx, span := tracer.Start(db.Statement.Context, "gorm.query",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL))
+	db.Statement.Context = ctx
+	db.InstanceSet(querySpanKey, span)
+}
+
+func (p *queryTracingPlugin) end(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(querySpanKey)
+		if !ok {
+			return
+		}
+		span, ok := value.(trace.Span)
+		if !ok {
+			return
+		}
+		defer span.End()
+
+		span.SetName("gorm." + operation + " " + db.Statement.Table)
+		span.SetAttributes(
+			semconv.DBOperationName(operation),
+			semconv.DBCollectionName(db.Statement.Table),
+			semconv.DBQueryText(db.Statement.SQL.String()),
+			attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+		)
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			span.RecordError(db.Error)
+			span.SetStatus(codes.Error, db.Error.Error())
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package prober
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	srv "github.com/th1enq/ViettelSMS_ServerService/internal/domain/service"
+)
+
+type httpProber struct {
+	scheme string
+	port   int
+	path   string
+	client *http.Client
+}
+
+// NewHTTPProber considers a server up when GET scheme://ipv4:port/path answers
+// with a 2xx or 3xx status. Redirects are not followed.
+func NewHTTPProber(scheme string, port int, path string, timeout time.Duration) srv.Prober {
+	return &httpProber{
+		scheme: scheme,
+		port:   port,
+		path:   path,
+		client: &http.Client{
+			Timeout: timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (p *httpProber) Probe(ctx context.Context, server *entity.Server) error {
+	url := fmt.Sprintf("%s://%s%s", p.scheme, net.JoinHostPort(server.IPv4, strconv.Itoa(p.port)), p.path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+	return nil
+}
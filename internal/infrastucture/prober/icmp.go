@@ -0,0 +1,87 @@
+package prober
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	srv "github.com/th1enq/ViettelSMS_ServerService/internal/domain/service"
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+)
+
+const protocolICMP = 1
+
+type icmpProber struct {
+	privileged bool
+	timeout    time.Duration
+	seq        atomic.Uint32
+}
+
+// NewICMPProber pings the server. Unprivileged pings need the process group in
+// net.ipv4.ping_group_range; privileged pings need CAP_NET_RAW.
+func NewICMPProber(privileged bool, timeout time.Duration) srv.Prober {
+	return &icmpProber{
+		privileged: privileged,
+		timeout:    timeout,
+	}
+}
+
+func (p *icmpProber) Probe(ctx context.Context, server *entity.Server) error {
+	ip := net.ParseIP(server.IPv4).To4()
+	if ip == nil {
+		return fmt.Errorf("invalid IPv4 address %q", server.IPv4)
+	}
+
+	network, destination := "udp4", net.Addr(&net.UDPAddr{IP: ip})
+	if p.privileged {
+		network, destination = "ip4:icmp", &net.IPAddr{IP: ip}
+	}
+	conn, err := icmp.ListenPacket(network, "0.0.0.0")
+	if err != nil {
+		return fmt.Errorf("icmp not permitted: %w", err)
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if err := conn.SetDeadline(time.Now().Add(p.timeout)); err != nil {
+		return err
+	}
+
+	seq := int(p.seq.Add(1) & 0xffff)
+	request := icmp.Message{
+		Type: ipv4.ICMPTypeEcho,
+		Body: &icmp.Echo{ID: os.Getpid() & 0xffff, Seq: seq, Data: []byte("server-service")},
+	}
+	payload, err := request.Marshal(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := conn.WriteTo(payload, destination); err != nil {
+		return err
+	}
+
+	buffer := make([]byte, 1500)
+	for {
+		n, _, err := conn.ReadFrom(buffer)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		reply, err := icmp.ParseMessage(protocolICMP, buffer[:n])
+		if err != nil || reply.Type != ipv4.ICMPTypeEchoReply {
+			continue
+		}
+		// Raw sockets see every echo reply on the host, so match our sequence.
+		if echo, ok := reply.Body.(*icmp.Echo); ok && echo.Seq == seq {
+			return nil
+		}
+	}
+}
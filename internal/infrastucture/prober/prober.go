@@ -0,0 +1,28 @@
+package prober
+
+import (
+	"fmt"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	srv "github.com/th1enq/ViettelSMS_ServerService/internal/domain/service"
+)
+
+const (
+	TypeTCP  = "tcp"
+	TypeHTTP = "http"
+	TypeICMP = "icmp"
+)
+
+// New builds the prober selected by the configuration.
+func New(cfg config.Prober) (srv.Prober, error) {
+	switch cfg.Type {
+	case TypeTCP:
+		return NewTCPProber(cfg.TCPPort, cfg.Timeout), nil
+	case TypeHTTP:
+		return NewHTTPProber(cfg.HTTPScheme, cfg.HTTPPort, cfg.HTTPPath, cfg.Timeout), nil
+	case TypeICMP:
+		return NewICMPProber(cfg.ICMPPrivileged, cfg.Timeout), nil
+	default:
+		return nil, fmt.Errorf("unsupported probe type %q", cfg.Type)
+	}
+}
@@ -0,0 +1,95 @@
+package prober
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+)
+
+var loopback = &entity.Server{ServerID: "s1", IPv4: "127.0.0.1"}
+
+func listenerPort(t *testing.T, addr net.Addr) int {
+	t.Helper()
+	_, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+// closedPort returns a loopback port with nothing listening on it.
+func closedPort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listenerPort(t, l.Addr())
+	l.Close()
+	return port
+}
+
+func TestTCPProber(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := NewTCPProber(listenerPort(t, l.Addr()), time.Second).Probe(context.Background(), loopback); err != nil {
+		t.Fatalf("expected listener to be reachable: %v", err)
+	}
+	if err := NewTCPProber(closedPort(t), time.Second).Probe(context.Background(), loopback); err == nil {
+		t.Fatal("expected closed port to be unreachable")
+	}
+}
+
+func TestHTTPProber(t *testing.T) {
+	status := http.StatusOK
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	p := NewHTTPProber("http", listenerPort(t, ts.Listener.Addr()), "/healthz", time.Second)
+	if err := p.Probe(context.Background(), loopback); err != nil {
+		t.Fatalf("expected 200 to be up: %v", err)
+	}
+
+	status = http.StatusServiceUnavailable
+	if err := p.Probe(context.Background(), loopback); err == nil {
+		t.Fatal("expected 503 to be down")
+	}
+
+	if err := NewHTTPProber("http", closedPort(t), "/healthz", time.Second).Probe(context.Background(), loopback); err == nil {
+		t.Fatal("expected closed port to be down")
+	}
+}
+
+func TestICMPProber_Loopback(t *testing.T) {
+	err := NewICMPProber(false, time.Second).Probe(context.Background(), loopback)
+	if err != nil {
+		t.Skipf("unprivileged ICMP not permitted here: %v", err)
+	}
+}
+
+func TestNew_UnsupportedType(t *testing.T) {
+	if _, err := New(config.Prober{Type: "udp"}); err == nil {
+		t.Fatal("expected error for unsupported probe type")
+	}
+}
@@ -0,0 +1,33 @@
+package prober
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	srv "github.com/th1enq/ViettelSMS_ServerService/internal/domain/service"
+)
+
+type tcpProber struct {
+	port    int
+	timeout time.Duration
+}
+
+// NewTCPProber considers a server up when it accepts a TCP connection on port.
+func NewTCPProber(port int, timeout time.Duration) srv.Prober {
+	return &tcpProber{
+		port:    port,
+		timeout: timeout,
+	}
+}
+
+func (p *tcpProber) Probe(ctx context.Context, server *entity.Server) error {
+	dialer := net.Dialer{Timeout: p.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(server.IPv4, strconv.Itoa(p.port)))
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
@@ -0,0 +1,83 @@
+package queue
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gammazero/workerpool"
+)
+
+// Queue runs tasks in the background on a fixed number of workers. Drain stops
+// it taking new tasks and waits for the queued ones, so work that outlives the
+// request that caused it still finishes on shutdown.
+type Queue struct {
+	pool *workerpool.WorkerPool
+	// mu guards closed so that no task is queued after Drain started waiting.
+	mu      sync.Mutex
+	closed  bool
+	closing chan struct{}
+	pending sync.WaitGroup
+}
+
+// New returns a queue running tasks on the given number of workers. With one
+// worker, tasks run in the order they were submitted.
+func New(workers int) *Queue {
+	return &Queue{
+		pool:    workerpool.New(workers),
+		closing: make(chan struct{}),
+	}
+}
+
+// Submit queues the task and reports whether it was accepted, which it is not
+// once Drain was called.
+func (q *Queue) Submit(task func()) bool {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return false
+	}
+	q.pending.Add(1)
+	q.mu.Unlock()
+
+	q.pool.Submit(func() {
+		defer q.pending.Done()
+		task()
+	})
+	return true
+}
+
+// Wait blocks until every task queued so far has finished, without refusing
+// new ones.
+func (q *Queue) Wait() {
+	q.pending.Wait()
+}
+
+// Closing is closed when Drain is called, so tasks can cut waits short.
+func (q *Queue) Closing() <-chan struct{} {
+	return q.closing
+}
+
+// Drain refuses new tasks and waits until the queued ones finished or the
+// context is done.
+func (q *Queue) Drain(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.closing)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.pending.Wait()
+		q.pool.StopWait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
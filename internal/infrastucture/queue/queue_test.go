@@ -0,0 +1,49 @@
+package queue
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestQueue_DrainWaitsForQueuedTasks(t *testing.T) {
+	q := New(2)
+	var done atomic.Int32
+	for range 5 {
+		if !q.Submit(func() {
+			time.Sleep(10 * time.Millisecond)
+			done.Add(1)
+		}) {
+			t.Fatal("task refused before Drain")
+		}
+	}
+
+	if err := q.Drain(context.Background()); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+	if got := done.Load(); got != 5 {
+		t.Fatalf("got %d tasks finished, want 5", got)
+	}
+	if q.Submit(func() {}) {
+		t.Fatal("task accepted after Drain")
+	}
+	select {
+	case <-q.Closing():
+	default:
+		t.Fatal("Closing not closed after Drain")
+	}
+}
+
+func TestQueue_DrainGivesUpWithContext(t *testing.T) {
+	q := New(1)
+	release := make(chan struct{})
+	defer close(release)
+	q.Submit(func() { <-release })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := q.Drain(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Drain() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
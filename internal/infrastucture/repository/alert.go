@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/authz"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	repo "github.com/th1enq/ViettelSMS_ServerService/internal/domain/repository"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AlertRepository struct {
+	db postgres.DBEngine
+}
+
+func NewAlertRepository(db postgres.DBEngine) repo.AlertRepository {
+	return &AlertRepository{db: db}
+}
+
+func (a *AlertRepository) RecordTransition(ctx context.Context, transition *entity.StatusTransition) error {
+	transition.TenantID = tenant.FromContext(ctx)
+	return conn(ctx, a.db).Create(transition).Error
+}
+
+func (a *AlertRepository) CountTransitions(ctx context.Context, serverID string, since time.Time) (int64, error) {
+	var count int64
+	err := conn(ctx, a.db).Model(&entity.StatusTransition{}).
+		Where("tenant_id = ? AND server_id = ? AND changed_at >= ?", tenant.FromContext(ctx), serverID, since).
+		Count(&count).Error
+	return count, err
+}
+
+// PruneTransitions deletes old transitions of every tenant.
+func (a *AlertRepository) PruneTransitions(ctx context.Context, before time.Time) (int64, error) {
+	result := conn(ctx, a.db).Where("changed_at < ?", before).Delete(&entity.StatusTransition{})
+	return result.RowsAffected, result.Error
+}
+
+func (a *AlertRepository) ListTransitionsSince(ctx context.Context, since time.Time) ([]*entity.StatusTransition, error) {
+	var transitions []*entity.StatusTransition
+	err := conn(ctx, a.db).
+		Where("changed_at >= ?", since).
+		Order("changed_at, id").
+		Find(&transitions).Error
+	return transitions, err
+}
+
+func (a *AlertRepository) Fire(ctx context.Context, alert *entity.Alert) (bool, error) {
+	alert.TenantID = tenant.FromContext(ctx)
+	alert.State = entity.AlertStateFiring
+	result := conn(ctx, a.db).Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "tenant_id"}, {Name: "rule"}, {Name: "subject"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Eq{Column: "state", Value: entity.AlertStateFiring}}},
+		DoNothing:   true,
+	}).Create(alert)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (a *AlertRepository) Resolve(ctx context.Context, rule entity.AlertRule, subject string, at time.Time) (bool, error) {
+	result := conn(ctx, a.db).Model(&entity.Alert{}).
+		Where("tenant_id = ? AND rule = ? AND subject = ? AND state = ?", tenant.FromContext(ctx), rule, subject, entity.AlertStateFiring).
+		Updates(map[string]interface{}{
+			"state":       entity.AlertStateResolved,
+			"resolved_at": at,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (a *AlertRepository) ListFiring(ctx context.Context) ([]*entity.Alert, error) {
+	var alerts []*entity.Alert
+	err := conn(ctx, a.db).Where("state = ?", entity.AlertStateFiring).Order("tenant_id, id").Find(&alerts).Error
+	return alerts, err
+}
+
+func (a *AlertRepository) GetAlerts(ctx context.Context, filter dto.AlertFilterOptions, pagination dto.AlertPaginationOptions) ([]*entity.Alert, int, error) {
+	var alerts []*entity.Alert
+	var total int64
+
+	query := conn(ctx, a.db).Model(&entity.Alert{}).Where("tenant_id = ?", tenant.FromContext(ctx))
+	query = a.visible(ctx, query,
+		"(alerts.rule IN ? AND servers.server_id = alerts.subject) OR (alerts.rule = ? AND servers.group_name = alerts.subject)",
+		[]entity.AlertRule{entity.AlertRuleOffline, entity.AlertRuleFlapping}, entity.AlertRuleGroupOffline)
+	if filter.State != nil {
+		query = query.Where("state = ?", *filter.State)
+	}
+	if filter.Rule != nil {
+		query = query.Where("rule = ?", *filter.Rule)
+	}
+	if filter.Subject != nil {
+		query = query.Where("subject = ?", *filter.Subject)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order("started_at DESC, id DESC").
+		Offset((pagination.Page - 1) * pagination.PageSize).
+		Limit(pagination.PageSize).
+		Find(&alerts).Error; err != nil {
+		return nil, 0, err
+	}
+	return alerts, int(total), nil
+}
+
+func (a *AlertRepository) CreateWindow(ctx context.Context, window *entity.MaintenanceWindow) error {
+	window.TenantID = tenant.FromContext(ctx)
+	return conn(ctx, a.db).Create(window).Error
+}
+
+func (a *AlertRepository) ListWindows(ctx context.Context, includeExpired bool) ([]*entity.MaintenanceWindow, error) {
+	var windows []*entity.MaintenanceWindow
+	query := conn(ctx, a.db).Model(&entity.MaintenanceWindow{}).Where("tenant_id = ?", tenant.FromContext(ctx))
+	query = a.visible(ctx, query, windowServers)
+	if !includeExpired {
+		query = query.Where("ends_at > ?", time.Now())
+	}
+	err := query.Order("starts_at").Find(&windows).Error
+	return windows, err
+}
+
+func (a *AlertRepository) DeleteWindow(ctx context.Context, id uint) error {
+	query := conn(ctx, a.db).Model(&entity.MaintenanceWindow{}).Where("tenant_id = ? AND id = ?", tenant.FromContext(ctx), id)
+	result := a.visible(ctx, query, windowServers).Delete(&entity.MaintenanceWindow{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (a *AlertRepository) InMaintenance(ctx context.Context, serverID, group string, at time.Time) (bool, error) {
+	var count int64
+	err := conn(ctx, a.db).Model(&entity.MaintenanceWindow{}).
+		Where("tenant_id = ? AND starts_at <= ? AND ends_at > ?", tenant.FromContext(ctx), at, at).
+		Where("(server_id <> '' AND server_id = ?) OR (group_name <> '' AND group_name = ?)", serverID, group).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// windowServers matches the servers a maintenance window covers.
+const windowServers = "(maintenance_windows.server_id <> '' AND servers.server_id = maintenance_windows.server_id) OR " +
+	"(maintenance_windows.group_name <> '' AND servers.group_name = maintenance_windows.group_name)"
+
+// visible restricts a constrained principal to rows covering at least one
+// server it may access, where covers relates the row to the servers table.
+func (a *AlertRepository) visible(ctx context.Context, query *gorm.DB, covers string, args ...interface{}) *gorm.DB {
+	principal := authz.FromContext(ctx)
+	if principal == nil || len(principal.Constraints) == 0 {
+		return query
+	}
+	servers := conn(ctx, a.db).Model(&entity.Server{}).
+		Select("1").
+		Where("servers.tenant_id = ?", tenant.FromContext(ctx)).
+		Where(covers, args...)
+	return query.Where("EXISTS (?)", withConstraints(ctx, servers))
+}
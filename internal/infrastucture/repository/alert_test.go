@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/authz"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+)
+
+func TestAlertRepository_GetAlertsAppliesConstraints(t *testing.T) {
+	engine, mock := newMockDB(t)
+	repository := NewAlertRepository(engine)
+
+	ctx := tenant.NewContext(context.Background(), "acme")
+	ctx = authz.NewContext(ctx, &authz.Principal{Constraints: authz.Constraints{
+		authz.ConstraintLocation: {"hanoi"},
+	}})
+
+	where := regexp.QuoteMeta(`WHERE tenant_id = $1 AND EXISTS (SELECT 1 FROM "servers" WHERE servers.tenant_id = $2 AND ` +
+		`((alerts.rule IN ($3,$4) AND servers.server_id = alerts.subject) OR (alerts.rule = $5 AND servers.group_name = alerts.subject)) AND ` +
+		`location IN ($6) AND "servers"."deleted_at" IS NULL)`)
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "alerts" `+where).
+		WithArgs("acme", "acme", "offline", "flapping", "group_offline", "hanoi").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`SELECT \* FROM "alerts" `+where).
+		WithArgs("acme", "acme", "offline", "flapping", "group_offline", "hanoi", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	if _, _, err := repository.GetAlerts(ctx, dto.AlertFilterOptions{}, dto.AlertPaginationOptions{Page: 1, PageSize: 10}); err != nil {
+		t.Fatalf("get alerts: %v", err)
+	}
+}
+
+func TestAlertRepository_ListWindowsAppliesConstraints(t *testing.T) {
+	engine, mock := newMockDB(t)
+	repository := NewAlertRepository(engine)
+
+	ctx := tenant.NewContext(context.Background(), "acme")
+	ctx = authz.NewContext(ctx, &authz.Principal{Constraints: authz.Constraints{
+		authz.ConstraintGroup: {"billing"},
+	}})
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "maintenance_windows" WHERE tenant_id = $1 AND EXISTS (SELECT 1 FROM "servers" WHERE servers.tenant_id = $2 AND `+
+		`((maintenance_windows.server_id <> '' AND servers.server_id = maintenance_windows.server_id) OR `+
+		`(maintenance_windows.group_name <> '' AND servers.group_name = maintenance_windows.group_name)) AND `+
+		`group_name IN ($3) AND "servers"."deleted_at" IS NULL) ORDER BY starts_at`)).
+		WithArgs("acme", "acme", "billing").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	if _, err := repository.ListWindows(ctx, true); err != nil {
+		t.Fatalf("list windows: %v", err)
+	}
+}
+
+func TestAlertRepository_UnconstrainedSkipsServers(t *testing.T) {
+	engine, mock := newMockDB(t)
+	repository := NewAlertRepository(engine)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "maintenance_windows" WHERE tenant_id = $1 ORDER BY starts_at`)).
+		WithArgs("acme").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	if _, err := repository.ListWindows(tenant.NewContext(context.Background(), "acme"), true); err != nil {
+		t.Fatalf("list windows: %v", err)
+	}
+}
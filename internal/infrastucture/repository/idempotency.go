@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	repo "github.com/th1enq/ViettelSMS_ServerService/internal/domain/repository"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyRepository struct {
+	db postgres.DBEngine
+}
+
+func NewIdempotencyRepository(db postgres.DBEngine) repo.IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// Reserve clears an expired record, or a reservation whose request is presumed
+// dead, for the key first, then relies on the primary key so only one of two
+// concurrent requests creates the record.
+func (i *IdempotencyRepository) Reserve(ctx context.Context, key *entity.IdempotencyKey) (*entity.IdempotencyKey, bool, error) {
+	key.TenantID = tenant.FromContext(ctx)
+	now := time.Now()
+	if err := i.scoped(ctx, key.Key).
+		Where("expires_at <= ? OR (status_code = 0 AND locked_until <= ?)", now, now).
+		Delete(&entity.IdempotencyKey{}).Error; err != nil {
+		return nil, false, err
+	}
+
+	result := conn(ctx, i.db).Clauses(clause.OnConflict{DoNothing: true}).Create(key)
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return key, true, nil
+	}
+
+	var stored entity.IdempotencyKey
+	if err := i.scoped(ctx, key.Key).First(&stored).Error; err != nil {
+		return nil, false, err
+	}
+	return &stored, false, nil
+}
+
+func (i *IdempotencyRepository) Complete(ctx context.Context, key *entity.IdempotencyKey) error {
+	return i.scoped(ctx, key.Key).Updates(map[string]interface{}{
+		"status_code":  key.StatusCode,
+		"content_type": key.ContentType,
+		"body":         key.Body,
+	}).Error
+}
+
+func (i *IdempotencyRepository) Release(ctx context.Context, key string) error {
+	return i.scoped(ctx, key).Where("status_code = 0").Delete(&entity.IdempotencyKey{}).Error
+}
+
+func (i *IdempotencyRepository) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := conn(ctx, i.db).Where("expires_at < ?", before).Delete(&entity.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
+
+func (i *IdempotencyRepository) scoped(ctx context.Context, key string) *gorm.DB {
+	return conn(ctx, i.db).Model(&entity.IdempotencyKey{}).
+		Where("tenant_id = ? AND idempotency_key = ?", tenant.FromContext(ctx), key)
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	repo "github.com/th1enq/ViettelSMS_ServerService/internal/domain/repository"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/postgres"
+	"gorm.io/gorm/clause"
+)
+
+type ReportRepository struct {
+	db postgres.DBEngine
+}
+
+func NewReportRepository(db postgres.DBEngine) repo.ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// ClaimRun relies on the primary key so only one of the replicas reaching the
+// same scheduled run creates the claim. The time is stored in UTC so replicas
+// in different zones agree on it.
+func (r *ReportRepository) ClaimRun(ctx context.Context, periodEnd time.Time) (bool, error) {
+	run := &entity.ReportRun{TenantID: tenant.FromContext(ctx), PeriodEnd: periodEnd.UTC()}
+	result := conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(run)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *ReportRepository) ReleaseRun(ctx context.Context, periodEnd time.Time) error {
+	return conn(ctx, r.db).
+		Where("tenant_id = ? AND period_end = ?", tenant.FromContext(ctx), periodEnd.UTC()).
+		Delete(&entity.ReportRun{}).Error
+}
+
+func (r *ReportRepository) Save(ctx context.Context, report *entity.Report) error {
+	report.TenantID = tenant.FromContext(ctx)
+	report.Size = int64(len(report.Content))
+	return conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"size", "content", "created_at"}),
+	}).Create(report).Error
+}
+
+func (r *ReportRepository) List(ctx context.Context) ([]*entity.Report, error) {
+	var reports []*entity.Report
+	err := conn(ctx, r.db).Model(&entity.Report{}).
+		Select("tenant_id", "name", "size", "created_at").
+		Where("tenant_id = ?", tenant.FromContext(ctx)).
+		Order("created_at DESC, name").
+		Find(&reports).Error
+	return reports, err
+}
+
+func (r *ReportRepository) GetByName(ctx context.Context, name string) (*entity.Report, error) {
+	var report entity.Report
+	err := conn(ctx, r.db).Where("tenant_id = ? AND name = ?", tenant.FromContext(ctx), name).First(&report).Error
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+)
+
+func TestReportRepository_ClaimRunOnce(t *testing.T) {
+	engine, mock := newMockDB(t)
+	repository := NewReportRepository(engine)
+
+	ctx := tenant.NewContext(context.Background(), "acme")
+	hanoi := time.FixedZone("ICT", 7*60*60)
+	end := time.Date(2025, 1, 6, 15, 0, 0, 0, hanoi)
+	insert := regexp.QuoteMeta(`INSERT INTO "report_runs" ("tenant_id","period_end","claimed_at") VALUES ($1,$2,$3) ON CONFLICT DO NOTHING`)
+
+	for _, rows := range []int64{1, 0} {
+		mock.ExpectBegin()
+		mock.ExpectExec(insert).
+			WithArgs("acme", end.UTC(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, rows))
+		mock.ExpectCommit()
+	}
+
+	if claimed, err := repository.ClaimRun(ctx, end); err != nil || !claimed {
+		t.Fatalf("first claim = %v, %v; want claimed", claimed, err)
+	}
+	if claimed, err := repository.ClaimRun(ctx, end); err != nil || claimed {
+		t.Fatalf("second claim = %v, %v; want already claimed", claimed, err)
+	}
+}
+
+func TestReportRepository_ReleaseRun(t *testing.T) {
+	engine, mock := newMockDB(t)
+	repository := NewReportRepository(engine)
+
+	ctx := tenant.NewContext(context.Background(), "acme")
+	end := time.Date(2025, 1, 6, 15, 0, 0, 0, time.FixedZone("ICT", 7*60*60))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "report_runs" WHERE tenant_id = $1 AND period_end = $2`)).
+		WithArgs("acme", end.UTC()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := repository.ReleaseRun(ctx, end); err != nil {
+		t.Fatalf("ReleaseRun() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReportRepository_SaveAndList(t *testing.T) {
+	engine, mock := newMockDB(t)
+	repository := NewReportRepository(engine)
+	ctx := tenant.NewContext(context.Background(), "acme")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "reports" ("tenant_id","name","size","content","created_at") VALUES ($1,$2,$3,$4,$5) ON CONFLICT ("tenant_id","name") DO UPDATE SET`)).
+		WithArgs("acme", "report.xlsx", int64(7), []byte("content"), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	if err := repository.Save(ctx, &entity.Report{Name: "report.xlsx", Content: []byte("content")}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// Listing leaves the workbooks in the database.
+	created := time.Date(2025, 1, 6, 8, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT "tenant_id","name","size","created_at" FROM "reports" WHERE tenant_id = $1 ORDER BY created_at DESC, name`)).
+		WithArgs("acme").
+		WillReturnRows(sqlmock.NewRows([]string{"tenant_id", "name", "size", "created_at"}).AddRow("acme", "report.xlsx", 7, created))
+	reports, err := repository.List(ctx)
+	if err != nil || len(reports) != 1 || reports[0].Name != "report.xlsx" || reports[0].Size != 7 || reports[0].Content != nil {
+		t.Fatalf("List() = %+v, %v", reports, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
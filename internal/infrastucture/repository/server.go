@@ -2,43 +2,64 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/wire"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/authz"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
 	repo "github.com/th1enq/ViettelSMS_ServerService/internal/domain/repository"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/postgres"
+	"gorm.io/gorm"
 )
 
 type ServerRepository struct {
 	db postgres.DBEngine
 }
 
-var RepositorySet = wire.NewSet(NewServerRepository)
+type txKey struct{}
+
+// conn returns the transaction carried by the context, if any, so that
+// repositories called inside WithTransaction take part in it.
+func conn(ctx context.Context, db postgres.DBEngine) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.GetDB().WithContext(ctx)
+}
+
+var RepositorySet = wire.NewSet(NewServerRepository, NewWebhookRepository, NewAlertRepository)
 
 func NewServerRepository(db postgres.DBEngine) repo.ServerRepository {
 	return &ServerRepository{db: db}
 }
 
 func (s *ServerRepository) Create(ctx context.Context, server *entity.Server) error {
-	return s.db.GetDB().WithContext(ctx).Create(server).Error
+	server.TenantID = tenant.FromContext(ctx)
+	return conn(ctx, s.db).Create(server).Error
 }
 
 func (s *ServerRepository) Delete(ctx context.Context, serverID string) error {
-	return s.db.GetDB().WithContext(ctx).Where("server_id = ?", serverID).Delete(&entity.Server{}).Error
+	return s.scoped(ctx).Where("server_id = ?", serverID).Delete(&entity.Server{}).Error
 }
 
 func (s *ServerRepository) ExistByNameOrID(ctx context.Context, serverID string, serverName string) (bool, error) {
 	var count int64
-	err := s.db.GetDB().WithContext(ctx).Model(&entity.Server{}).Where("server_id = ? OR server_name = ?", serverID, serverName).Count(&count).Error
+	// server_id stays taken by soft-deleted rows until they are purged, names are freed on delete.
+	err := s.scoped(ctx).Unscoped().
+		Where("server_id = ? OR (server_name = ? AND deleted_at IS NULL)", serverID, serverName).
+		Count(&count).Error
 	return count > 0, err
 }
 
 func (s *ServerRepository) GetByField(ctx context.Context, field string, value interface{}) (*entity.Server, error) {
 	var server entity.Server
-	err := s.db.GetDB().WithContext(ctx).Model(&entity.Server{}).Where(field+" = ?", value).First(&server).Error
+	err := s.scoped(ctx).Where(field+" = ?", value).First(&server).Error
 	if err != nil {
 		return nil, err
 	}
@@ -46,14 +67,18 @@ func (s *ServerRepository) GetByField(ctx context.Context, field string, value i
 }
 
 func (s *ServerRepository) Update(ctx context.Context, server *entity.Server) error {
-	return s.db.GetDB().WithContext(ctx).Save(server).Error
+	server.TenantID = tenant.FromContext(ctx)
+	return conn(ctx, s.db).Save(server).Error
 }
 
 func (s *ServerRepository) GetServers(ctx context.Context, filter dto.ServerFilterOptions, pagination dto.ServerPaginationOptions) ([]*entity.Server, int, error) {
 	var servers []*entity.Server
 	var total int64
 
-	query := s.db.GetDB().WithContext(ctx).Model(&entity.Server{})
+	query := s.scoped(ctx)
+	if filter.IncludeDeleted {
+		query = query.Unscoped()
+	}
 
 	if filter.ServerName != nil {
 		query = query.Where("server_name LIKE ?", "%"+*filter.ServerName+"%")
@@ -61,6 +86,7 @@ func (s *ServerRepository) GetServers(ctx context.Context, filter dto.ServerFilt
 	if filter.Status != nil {
 		query = query.Where("status = ?", *filter.Status)
 	}
+	query = withConstraints(ctx, query)
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -84,35 +110,265 @@ func (s *ServerRepository) BatchCreate(ctx context.Context, servers []*entity.Se
 		return inserted, nil
 	}
 
+	tenantID := tenant.FromContext(ctx)
 	placeholders := make([]string, 0, len(servers))
-	args := make([]interface{}, 0, len(servers)*7)
+	args := make([]interface{}, 0, len(servers)*9)
 
 	for _, server := range servers {
-		placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, NOW())")
+		server.TenantID = tenantID
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?, ?, NOW())")
 		args = append(args,
+			server.TenantID,
 			server.ServerID,
 			server.ServerName,
 			server.IPv4,
 			server.Location,
 			server.OS,
+			server.Group,
 			server.IntervalTime,
 		)
 	}
 
 	query := fmt.Sprintf(`
-        INSERT INTO servers (server_id, server_name, ipv4, location, os, interval_time, created_at)
+        INSERT INTO servers (tenant_id, server_id, server_name, ipv4, location, os, group_name, interval_time, created_at)
         VALUES %s
         ON CONFLICT DO NOTHING
         RETURNING server_id
     `, strings.Join(placeholders, ","))
 
-	if err := s.db.GetDB().WithContext(ctx).Raw(query, args...).Scan(&inserted).Error; err != nil {
+	if err := conn(ctx, s.db).Raw(query, args...).Scan(&inserted).Error; err != nil {
 		return nil, err
 	}
 
 	return inserted, nil
 }
 
-func (s *ServerRepository) UpdateStatus(ctx context.Context, serverID string, status entity.ServerStatus) error {
-	return s.db.GetDB().WithContext(ctx).Model(&entity.Server{}).Where("server_id = ?", serverID).Update("status", status).Error
+// UpdateStatus locks the row before updating it so that concurrent reports for
+// the same server each see the status the other one wrote.
+func (s *ServerRepository) UpdateStatus(ctx context.Context, serverID string, status entity.ServerStatus) (entity.ServerStatus, error) {
+	var previous entity.ServerStatus
+	now := time.Now()
+	err := conn(ctx, s.db).Raw(`
+		UPDATE servers AS s SET
+			status = ?,
+			status_changed_at = CASE WHEN old.status <> ? THEN ? ELSE s.status_changed_at END,
+			updated_at = ?
+		FROM (
+			SELECT tenant_id, server_id, status FROM servers
+			WHERE tenant_id = ? AND server_id = ? AND deleted_at IS NULL
+			FOR UPDATE
+		) AS old
+		WHERE s.tenant_id = old.tenant_id AND s.server_id = old.server_id
+		RETURNING old.status`,
+		status, status, now, now, tenant.FromContext(ctx), serverID,
+	).Row().Scan(&previous)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", gorm.ErrRecordNotFound
+	}
+	return previous, err
+}
+
+func (s *ServerRepository) GetDeletedByID(ctx context.Context, serverID string) (*entity.Server, error) {
+	var server entity.Server
+	err := s.scoped(ctx).Unscoped().Where("server_id = ? AND deleted_at IS NOT NULL", serverID).First(&server).Error
+	if err != nil {
+		return nil, err
+	}
+	return &server, nil
+}
+
+func (s *ServerRepository) Restore(ctx context.Context, serverID string) error {
+	return s.scoped(ctx).Unscoped().
+		Where("server_id = ? AND deleted_at IS NOT NULL", serverID).
+		Update("deleted_at", nil).Error
+}
+
+// PurgeDeleted permanently removes servers soft-deleted before the given time.
+// It is a maintenance operation and deliberately runs across all tenants.
+func (s *ServerRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	result := conn(ctx, s.db).Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Delete(&entity.Server{})
+	return result.RowsAffected, result.Error
+}
+
+// CountByStatus counts live servers per tenant and status. Like PurgeDeleted it
+// serves operational tooling and is not scoped to a tenant.
+func (s *ServerRepository) CountByStatus(ctx context.Context) ([]dto.StatusCount, error) {
+	var counts []dto.StatusCount
+	err := conn(ctx, s.db).Model(&entity.Server{}).
+		Select("tenant_id, status, COUNT(*) AS count").
+		Group("tenant_id, status").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// ListActive returns the live servers of every tenant, for components that
+// monitor the whole inventory.
+func (s *ServerRepository) ListActive(ctx context.Context) ([]*entity.Server, error) {
+	var servers []*entity.Server
+	err := conn(ctx, s.db).Order("tenant_id, server_id").Find(&servers).Error
+	return servers, err
+}
+
+// WithTransaction runs fn in a database transaction; repository calls made with the
+// context passed to fn join that transaction.
+func (s *ServerRepository) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return conn(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+func (s *ServerRepository) GetByIDs(ctx context.Context, serverIDs []string) ([]*entity.Server, error) {
+	var servers []*entity.Server
+	if err := s.scoped(ctx).Where("server_id IN ?", serverIDs).Find(&servers).Error; err != nil {
+		return nil, err
+	}
+	return servers, nil
+}
+
+func (s *ServerRepository) BulkDelete(ctx context.Context, serverIDs []string) (int64, error) {
+	result := s.scoped(ctx).Where("server_id IN ?", serverIDs).Delete(&entity.Server{})
+	return result.RowsAffected, result.Error
+}
+
+func (s *ServerRepository) BulkPatch(ctx context.Context, serverIDs []string, patch dto.BulkPatch) (int64, error) {
+	updates := make(map[string]interface{})
+	if patch.Location != nil {
+		updates["location"] = *patch.Location
+	}
+	if patch.OS != nil {
+		updates["os"] = *patch.OS
+	}
+	if patch.IntervalTime != nil {
+		updates["interval_time"] = *patch.IntervalTime
+	}
+	if len(patch.Labels) > 0 {
+		labels, err := patch.Labels.Value()
+		if err != nil {
+			return 0, err
+		}
+		updates["labels"] = gorm.Expr("labels || ?::jsonb", labels)
+	}
+	if len(updates) == 0 {
+		return 0, nil
+	}
+
+	result := s.scoped(ctx).Where("server_id IN ?", serverIDs).Updates(updates)
+	return result.RowsAffected, result.Error
+}
+
+func (s *ServerRepository) BulkUpdateStatus(ctx context.Context, serverIDs []string, status entity.ServerStatus) (int64, error) {
+	result := s.scoped(ctx).Where("server_id IN ?", serverIDs).Updates(map[string]interface{}{
+		"status":            status,
+		"status_changed_at": gorm.Expr("CASE WHEN status <> ? THEN ? ELSE status_changed_at END", status, time.Now()),
+	})
+	return result.RowsAffected, result.Error
+}
+
+// ListOfflineSince returns the live servers of every tenant that have been
+// OFFLINE since before the given time.
+func (s *ServerRepository) ListOfflineSince(ctx context.Context, before time.Time) ([]*entity.Server, error) {
+	var servers []*entity.Server
+	err := conn(ctx, s.db).
+		Where("status = ? AND status_changed_at < ?", entity.ServerStatusOffline, before).
+		Find(&servers).Error
+	return servers, err
+}
+
+// CountGroupByStatus counts the live servers of a group per status.
+func (s *ServerRepository) CountGroupByStatus(ctx context.Context, group string) ([]dto.StatusCount, error) {
+	var counts []dto.StatusCount
+	err := s.scoped(ctx).
+		Where("group_name = ?", group).
+		Select("tenant_id, status, COUNT(*) AS count").
+		Group("tenant_id, status").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// GetStats runs one aggregate query per breakdown over the live servers of the
+// tenant, restricted by the caller's constraints.
+func (s *ServerRepository) GetStats(ctx context.Context, query dto.ServerStatsQuery) (*dto.ServerStats, error) {
+	visible := func() *gorm.DB {
+		return withConstraints(ctx, s.scoped(ctx))
+	}
+	stats := &dto.ServerStats{GeneratedAt: time.Now()}
+
+	breakdowns := []struct {
+		target *[]dto.ValueCount
+		value  string
+		order  string
+	}{
+		{&stats.ByStatus, "status", "count DESC, value"},
+		{&stats.ByLocation, "COALESCE(location, '')", "count DESC, value"},
+		{&stats.ByOS, "COALESCE(os, '')", "count DESC, value"},
+		{&stats.ByInterval, `CASE
+			WHEN interval_time <= 10 THEN '1-10s'
+			WHEN interval_time <= 30 THEN '11-30s'
+			WHEN interval_time <= 60 THEN '31-60s'
+			ELSE '>60s' END`, "MIN(interval_time)"},
+	}
+	for _, breakdown := range breakdowns {
+		*breakdown.target = make([]dto.ValueCount, 0)
+		if err := visible().
+			Select(breakdown.value + " AS value, COUNT(*) AS count").
+			Group("value").
+			Order(breakdown.order).
+			Scan(breakdown.target).Error; err != nil {
+			return nil, err
+		}
+	}
+	for _, count := range stats.ByStatus {
+		stats.Total += count.Count
+	}
+
+	stats.CreatedPerDay = make([]dto.DailyCount, 0)
+	if err := visible().
+		Select("TO_CHAR(DATE_TRUNC('day', created_at), 'YYYY-MM-DD') AS day, COUNT(*) AS count").
+		Where("created_at >= ?", query.CreatedSince).
+		Group("day").
+		Order("day").
+		Scan(&stats.CreatedPerDay).Error; err != nil {
+		return nil, err
+	}
+
+	if err := visible().
+		Where("status_changed_at >= ?", query.ChangedSince).
+		Count(&stats.ChangedLastHour).Error; err != nil {
+		return nil, err
+	}
+
+	stats.TopFlapping = make([]dto.FlappingServer, 0)
+	flapping := conn(ctx, s.db).Table("server_status_transitions AS t").
+		Select("t.server_id, s.server_name, COUNT(*) AS transitions").
+		Joins("JOIN servers AS s ON s.tenant_id = t.tenant_id AND s.server_id = t.server_id AND s.deleted_at IS NULL").
+		Where("t.tenant_id = ? AND t.changed_at >= ?", tenant.FromContext(ctx), query.FlappingSince)
+	if err := withConstraints(ctx, flapping).
+		Group("t.server_id, s.server_name").
+		Order("transitions DESC, t.server_id").
+		Limit(query.FlappingLimit).
+		Scan(&stats.TopFlapping).Error; err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// conn returns the transaction carried by the context, or the shared connection.
+// scoped starts a query restricted to the tenant carried by the context.
+func (s *ServerRepository) scoped(ctx context.Context) *gorm.DB {
+	return conn(ctx, s.db).Model(&entity.Server{}).Where("tenant_id = ?", tenant.FromContext(ctx))
+}
+
+// withConstraints limits the query to servers the caller's principal is allowed to see.
+func withConstraints(ctx context.Context, query *gorm.DB) *gorm.DB {
+	principal := authz.FromContext(ctx)
+	if principal == nil {
+		return query
+	}
+	for key, allowed := range principal.Constraints {
+		query = query.Where(authz.ConstraintColumns[key]+" IN ?", allowed)
+	}
+	return query
 }
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/authz"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+type mockEngine struct {
+	db *gorm.DB
+}
+
+func (m *mockEngine) GetDB() *gorm.DB                { return m.db }
+func (m *mockEngine) Ping(ctx context.Context) error { return nil }
+func (m *mockEngine) Close() error                   { return nil }
+
+// newMockDB returns an engine speaking the Postgres dialect to sqlmock, so
+// tests can assert the statements a repository generates.
+func newMockDB(t *testing.T) (*mockEngine, sqlmock.Sqlmock) {
+	t.Helper()
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{
+		Logger: gormLogger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("open gorm: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+	return &mockEngine{db: db}, mock
+}
+
+func TestServerRepository_GetServersScopesByTenantAndConstraints(t *testing.T) {
+	engine, mock := newMockDB(t)
+	repository := NewServerRepository(engine)
+
+	ctx := tenant.NewContext(context.Background(), "acme")
+	ctx = authz.NewContext(ctx, &authz.Principal{Constraints: authz.Constraints{
+		authz.ConstraintLocation: {"hanoi"},
+	}})
+
+	where := regexp.QuoteMeta(`WHERE tenant_id = $1 AND location IN ($2) AND "servers"."deleted_at" IS NULL`)
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "servers" `+where).
+		WithArgs("acme", "hanoi").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT \* FROM "servers" `+where).
+		WithArgs("acme", "hanoi", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"server_id", "tenant_id"}).AddRow("srv-1", "acme"))
+
+	servers, total, err := repository.GetServers(ctx, dto.ServerFilterOptions{}, dto.ServerPaginationOptions{
+		Page: 1, PageSize: 10, SortBy: "server_id", SortOrder: "asc",
+	})
+	if err != nil {
+		t.Fatalf("get servers: %v", err)
+	}
+	if total != 1 || len(servers) != 1 || servers[0].TenantID != "acme" {
+		t.Fatalf("got %d servers (total %d), want the acme server", len(servers), total)
+	}
+}
+
+func TestServerRepository_DefaultTenant(t *testing.T) {
+	engine, mock := newMockDB(t)
+	repository := NewServerRepository(engine)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "servers" WHERE tenant_id = $1 AND server_id = $2`)).
+		WithArgs(tenant.Default, "srv-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"server_id", "tenant_id"}).AddRow("srv-1", tenant.Default))
+
+	if _, err := repository.GetByField(context.Background(), "server_id", "srv-1"); err != nil {
+		t.Fatalf("get by field: %v", err)
+	}
+}
+
+func TestServerRepository_WritesUseContextTenant(t *testing.T) {
+	engine, mock := newMockDB(t)
+	repository := NewServerRepository(engine)
+	ctx := tenant.NewContext(context.Background(), "acme")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "servers" SET "deleted_at"=$1 WHERE tenant_id = $2 AND server_id = $3`)).
+		WithArgs(sqlmock.AnyArg(), "acme", "srv-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	if err := repository.Delete(ctx, "srv-1"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	// A tenant set by the caller is overwritten by the one on the context.
+	server := &entity.Server{ServerID: "srv-2", TenantID: "other"}
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "servers"`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	if err := repository.Create(ctx, server); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if server.TenantID != "acme" {
+		t.Fatalf("got tenant %q, want acme", server.TenantID)
+	}
+}
+
+func TestServerRepository_CountByStatusSpansTenants(t *testing.T) {
+	engine, mock := newMockDB(t)
+	repository := NewServerRepository(engine)
+
+	// The tenant on the context is ignored: metrics count every tenant.
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT tenant_id, status, COUNT(*) AS count FROM "servers" WHERE "servers"."deleted_at" IS NULL GROUP BY tenant_id, status`)).
+		WithoutArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"tenant_id", "status", "count"}).
+			AddRow("acme", "ONLINE", 3).
+			AddRow("default", "OFFLINE", 1))
+
+	counts, err := repository.CountByStatus(tenant.NewContext(context.Background(), "acme"))
+	if err != nil {
+		t.Fatalf("count by status: %v", err)
+	}
+	if len(counts) != 2 || counts[1].TenantID != "default" {
+		t.Fatalf("got %+v, want counts for both tenants", counts)
+	}
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	repo "github.com/th1enq/ViettelSMS_ServerService/internal/domain/repository"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/postgres"
+	"gorm.io/gorm"
+)
+
+type WebhookRepository struct {
+	db postgres.DBEngine
+}
+
+func NewWebhookRepository(db postgres.DBEngine) repo.WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (w *WebhookRepository) Create(ctx context.Context, webhook *entity.Webhook) error {
+	webhook.TenantID = tenant.FromContext(ctx)
+	return conn(ctx, w.db).Create(webhook).Error
+}
+
+func (w *WebhookRepository) GetByID(ctx context.Context, id uint) (*entity.Webhook, error) {
+	var webhook entity.Webhook
+	if err := w.scoped(ctx).Where("id = ?", id).First(&webhook).Error; err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (w *WebhookRepository) List(ctx context.Context) ([]*entity.Webhook, error) {
+	var webhooks []*entity.Webhook
+	if err := w.scoped(ctx).Order("id").Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (w *WebhookRepository) ListEnabled(ctx context.Context) ([]*entity.Webhook, error) {
+	var webhooks []*entity.Webhook
+	if err := w.scoped(ctx).Where("enabled").Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (w *WebhookRepository) Update(ctx context.Context, webhook *entity.Webhook) error {
+	webhook.TenantID = tenant.FromContext(ctx)
+	return conn(ctx, w.db).Save(webhook).Error
+}
+
+func (w *WebhookRepository) Delete(ctx context.Context, id uint) error {
+	result := w.scoped(ctx).Where("id = ?", id).Delete(&entity.Webhook{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (w *WebhookRepository) RecordDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	delivery.TenantID = tenant.FromContext(ctx)
+	return conn(ctx, w.db).Create(delivery).Error
+}
+
+func (w *WebhookRepository) ListDeliveries(ctx context.Context, webhookID uint, limit int) ([]*entity.WebhookDelivery, error) {
+	var deliveries []*entity.WebhookDelivery
+	err := conn(ctx, w.db).
+		Where("tenant_id = ? AND webhook_id = ?", tenant.FromContext(ctx), webhookID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (w *WebhookRepository) MarkDelivered(ctx context.Context, id uint) error {
+	return w.scoped(ctx).Where("id = ? AND consecutive_failures <> 0", id).Update("consecutive_failures", 0).Error
+}
+
+// MarkFailed increments the failure count and disables the webhook in a single
+// statement, so concurrent deliveries cannot both miss the threshold.
+func (w *WebhookRepository) MarkFailed(ctx context.Context, id uint, disableAfter int) (bool, error) {
+	var enabled bool
+	err := conn(ctx, w.db).Raw(`
+		UPDATE webhooks SET
+			consecutive_failures = consecutive_failures + 1,
+			enabled = enabled AND consecutive_failures + 1 < ?,
+			disabled_at = CASE WHEN enabled AND consecutive_failures + 1 >= ? THEN ? ELSE disabled_at END,
+			updated_at = ?
+		WHERE tenant_id = ? AND id = ?
+		RETURNING enabled`,
+		disableAfter, disableAfter, time.Now(), time.Now(), tenant.FromContext(ctx), id,
+	).Row().Scan(&enabled)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, gorm.ErrRecordNotFound
+	}
+	return !enabled, err
+}
+
+func (w *WebhookRepository) scoped(ctx context.Context) *gorm.DB {
+	return conn(ctx, w.db).Model(&entity.Webhook{}).Where("tenant_id = ?", tenant.FromContext(ctx))
+}
@@ -1,11 +1,14 @@
 package service
 
 import (
+	"bytes"
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
 
 	"github.com/google/wire"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
 	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
 	srv "github.com/th1enq/ViettelSMS_ServerService/internal/domain/service"
@@ -15,6 +18,141 @@ import (
 
 var ExcelizeServiceSet = wire.NewSet(NewExcelizeService)
 
+const (
+	TEMPLATE_SHEET_SERVERS      = "Servers"
+	TEMPLATE_SHEET_INSTRUCTIONS = "Instructions"
+
+	// TEMPLATE_VALIDATION_ROWS is how far down the template validates input.
+	TEMPLATE_VALIDATION_ROWS = 5000
+
+	MIN_INTERVAL_TIME = 1
+	MAX_INTERVAL_TIME = 60
+)
+
+// importColumn describes one column of the import file. The parser, the
+// header check, the downloadable template and the export are all driven by
+// importColumns.
+type importColumn struct {
+	header      string
+	description string
+	required    bool
+	// optional columns may be left out of the header altogether; they must
+	// come after every mandatory column.
+	optional bool
+	examples []string
+	// width is the column width in exports.
+	width float64
+	// parse checks a non-empty value and sets it on the server.
+	parse func(server *entity.Server, value string) error
+	// export returns the value written for the server in exports.
+	export func(server *dto.ServerResponse) interface{}
+	// validation restricts what the template accepts in the column; cell is
+	// the first data cell, which relative formulas refer to.
+	validation func(sqref, cell string) (*excelize.DataValidation, error)
+}
+
+var importColumns = []importColumn{
+	{
+		header:      "server_id",
+		description: "Unique identifier of the server.",
+		required:    true,
+		examples:    []string{"srv-001", "srv-002"},
+		width:       16,
+		parse:       func(server *entity.Server, value string) error { server.ServerID = value; return nil },
+		export:      func(server *dto.ServerResponse) interface{} { return server.ServerID },
+	},
+	{
+		header:      "server_name",
+		description: "Unique display name of the server.",
+		required:    true,
+		examples:    []string{"web-01", "db-01"},
+		width:       24,
+		parse:       func(server *entity.Server, value string) error { server.ServerName = value; return nil },
+		export:      func(server *dto.ServerResponse) interface{} { return server.ServerName },
+	},
+	{
+		header:      "ipv4",
+		description: "IPv4 address, for example 10.0.0.1. Must be unique.",
+		required:    true,
+		examples:    []string{"10.0.0.1", "10.0.0.2"},
+		width:       16,
+		parse: func(server *entity.Server, value string) error {
+			if ip := net.ParseIP(value); ip == nil || ip.To4() == nil || strings.Contains(value, ":") {
+				return fmt.Errorf("must be a valid IPv4 address")
+			}
+			server.IPv4 = value
+			return nil
+		},
+		export: func(server *dto.ServerResponse) interface{} { return server.IPv4 },
+		validation: func(sqref, cell string) (*excelize.DataValidation, error) {
+			// Excel has no regular expressions: check the dotted shape and
+			// leave the octet ranges to the parser.
+			dv := excelize.NewDataValidation(true)
+			dv.SetSqref(sqref)
+			// Formula1 is written as raw XML, so the formula is escaped here.
+			dv.Type = "custom"
+			dv.Formula1 = strings.NewReplacer("C2", cell, "&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(
+				`AND(LEN(C2)-LEN(SUBSTITUTE(C2,".",""))=3,ISNUMBER(--SUBSTITUTE(C2,".","")),ISERROR(FIND("..",C2)),ISERROR(FIND("-",C2)),LEFT(C2)<>".",RIGHT(C2)<>".",LEN(C2)<=15)`)
+			dv.SetError(excelize.DataValidationErrorStyleStop, "Invalid IPv4", "Enter an IPv4 address such as 10.0.0.1.")
+			return dv, nil
+		},
+	},
+	{
+		header:      "location",
+		description: "Where the server is hosted, for example a data centre.",
+		examples:    []string{"HN", "HCM"},
+		width:       16,
+		parse:       func(server *entity.Server, value string) error { server.Location = value; return nil },
+		export:      func(server *dto.ServerResponse) interface{} { return server.Location },
+	},
+	{
+		header:      "os",
+		description: "Operating system.",
+		examples:    []string{"ubuntu", "centos"},
+		width:       16,
+		parse:       func(server *entity.Server, value string) error { server.OS = value; return nil },
+		export:      func(server *dto.ServerResponse) interface{} { return server.OS },
+	},
+	{
+		header:      "interval_time",
+		description: fmt.Sprintf("Seconds between health checks, a whole number from %d to %d.", MIN_INTERVAL_TIME, MAX_INTERVAL_TIME),
+		required:    true,
+		examples:    []string{"10", "30"},
+		width:       14,
+		parse: func(server *entity.Server, value string) error {
+			interval, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("must be a valid number")
+			}
+			if interval < MIN_INTERVAL_TIME || interval > MAX_INTERVAL_TIME {
+				return fmt.Errorf("must be between %d and %d", MIN_INTERVAL_TIME, MAX_INTERVAL_TIME)
+			}
+			server.IntervalTime = interval
+			return nil
+		},
+		export: func(server *dto.ServerResponse) interface{} { return server.IntervalTime },
+		validation: func(sqref, cell string) (*excelize.DataValidation, error) {
+			dv := excelize.NewDataValidation(true)
+			dv.SetSqref(sqref)
+			if err := dv.SetRange(MIN_INTERVAL_TIME, MAX_INTERVAL_TIME, excelize.DataValidationTypeWhole, excelize.DataValidationOperatorBetween); err != nil {
+				return nil, err
+			}
+			dv.SetError(excelize.DataValidationErrorStyleStop, "Invalid interval",
+				fmt.Sprintf("Enter a whole number of seconds from %d to %d.", MIN_INTERVAL_TIME, MAX_INTERVAL_TIME))
+			return dv, nil
+		},
+	},
+	{
+		header:      "group",
+		description: "Optional group used by alerts and bulk operations. The column may be left out.",
+		optional:    true,
+		examples:    []string{"web", "database"},
+		width:       16,
+		parse:       func(server *entity.Server, value string) error { server.Group = value; return nil },
+		export:      func(server *dto.ServerResponse) interface{} { return server.Group },
+	},
+}
+
 type excelizeService struct {
 	logger *zap.Logger
 }
@@ -46,59 +184,162 @@ func (e *excelizeService) GetRows(filePath string) ([][]string, error) {
 	return rows, nil
 }
 
+// Validate checks the header row. The error is a domain.ErrInvalidFile whose
+// field names the first column that does not match.
 func (e *excelizeService) Validate(row []string) error {
-	expectedHeaders := []string{
-		"server_id",
-		"server_name",
-		"ipv4",
-		"location",
-		"os",
-		"interval_time",
+	for i, column := range importColumns {
+		if i >= len(row) || strings.TrimSpace(row[i]) == "" {
+			if column.optional {
+				return nil
+			}
+			return domain.ErrInvalidFile.WithFields(domain.FieldError{
+				Field:   fmt.Sprintf("column %d", i+1),
+				Message: fmt.Sprintf("is missing, expected %q", column.header),
+			})
+		}
+		if got := strings.TrimSpace(strings.ToLower(row[i])); got != column.header {
+			return domain.ErrInvalidFile.WithFields(domain.FieldError{
+				Field:   fmt.Sprintf("column %d", i+1),
+				Message: fmt.Sprintf("is %q, expected %q", row[i], column.header),
+			})
+		}
 	}
+	return nil
+}
 
-	if len(row) < len(expectedHeaders) {
-		return fmt.Errorf("invalid header: expected at least %d columns, got %d", len(expectedHeaders), len(row))
+func (e *excelizeService) Parse(row []string) (*entity.Server, error) {
+	server := &entity.Server{}
+	for i, column := range importColumns {
+		value := ""
+		if i < len(row) {
+			value = strings.TrimSpace(row[i])
+		}
+		if value == "" {
+			if column.required {
+				return nil, fmt.Errorf("invalid row: %s is required", column.header)
+			}
+			continue
+		}
+		if err := column.parse(server, value); err != nil {
+			return nil, fmt.Errorf("invalid row: %s %w", column.header, err)
+		}
 	}
+	return server, nil
+}
 
-	for i, expected := range expectedHeaders {
-		if i >= len(row) || strings.TrimSpace(strings.ToLower(row[i])) != expected {
-			return domain.ErrInvalidFile
-		}
+// Template builds the import workbook: the header row with a comment on each
+// column, example rows and input validation, followed by an instructions sheet.
+func (e *excelizeService) Template() ([]byte, error) {
+	file := excelize.NewFile()
+	defer file.Close()
+
+	if err := file.SetSheetName("Sheet1", TEMPLATE_SHEET_SERVERS); err != nil {
+		return nil, err
+	}
+	if _, err := file.NewSheet(TEMPLATE_SHEET_INSTRUCTIONS); err != nil {
+		return nil, err
+	}
+	if err := writeTemplateServers(file); err != nil {
+		return nil, err
+	}
+	if err := writeTemplateInstructions(file); err != nil {
+		return nil, err
 	}
 
-	return nil
+	var buf bytes.Buffer
+	if _, err := file.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
 
-func (e *excelizeService) Parse(row []string) (*entity.Server, error) {
-	if len(row) < 6 {
-		return nil, fmt.Errorf("invalid row: expected at least 7 columns, got %d", len(row))
+func writeTemplateServers(file *excelize.File) error {
+	header, err := file.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true, Color: "FFFFFF"},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"1F4E78"}},
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, column := range importColumns {
+		name, _ := excelize.ColumnNumberToName(i + 1)
+		headerCell := name + "1"
+		if err := file.SetCellStr(TEMPLATE_SHEET_SERVERS, headerCell, column.header); err != nil {
+			return err
+		}
+		if err := file.SetColWidth(TEMPLATE_SHEET_SERVERS, name, name, 18); err != nil {
+			return err
+		}
+		if err := file.AddComment(TEMPLATE_SHEET_SERVERS, excelize.Comment{
+			Cell:   headerCell,
+			Author: "Server Service",
+			Text:   columnNote(column),
+		}); err != nil {
+			return err
+		}
+		for row, example := range column.examples {
+			cell := fmt.Sprintf("%s%d", name, row+2)
+			if err := file.SetCellStr(TEMPLATE_SHEET_SERVERS, cell, example); err != nil {
+				return err
+			}
+		}
+		if column.validation != nil {
+			dv, err := column.validation(fmt.Sprintf("%s2:%s%d", name, name, TEMPLATE_VALIDATION_ROWS), name+"2")
+			if err != nil {
+				return err
+			}
+			if err := file.AddDataValidation(TEMPLATE_SHEET_SERVERS, dv); err != nil {
+				return err
+			}
+		}
 	}
 
-	server := &entity.Server{
-		ServerID:     strings.TrimSpace(row[0]),
-		ServerName:   strings.TrimSpace(row[1]),
-		IPv4:         strings.TrimSpace(row[2]),
-		Location:     strings.TrimSpace(row[3]),
-		OS:           strings.TrimSpace(row[4]),
-		IntervalTime: 0,
+	lastColumn, _ := excelize.ColumnNumberToName(len(importColumns))
+	if err := file.SetCellStyle(TEMPLATE_SHEET_SERVERS, "A1", lastColumn+"1", header); err != nil {
+		return err
 	}
-	if server.ServerID == "" {
-		return nil, fmt.Errorf("invalid row: server_id is required")
+	return file.SetPanes(TEMPLATE_SHEET_SERVERS, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	})
+}
+
+func writeTemplateInstructions(file *excelize.File) error {
+	lines := [][]interface{}{
+		{"How to import servers"},
+		{},
+		{fmt.Sprintf("Fill in the %q sheet, one server per row, keeping the header row as it is.", TEMPLATE_SHEET_SERVERS)},
+		{"Replace the example rows with your own servers before uploading."},
+		{"Only the first sheet is imported; this sheet is ignored."},
+		{},
+		{"column", "required", "description"},
 	}
-	if server.ServerName == "" {
-		return nil, fmt.Errorf("invalid row: server_name is required")
+	for _, column := range importColumns {
+		required := "no"
+		if column.required {
+			required = "yes"
+		}
+		lines = append(lines, []interface{}{column.header, required, column.description})
 	}
-	if server.IPv4 == "" {
-		return nil, fmt.Errorf("invalid row: ipv4 is required")
+
+	for i, line := range lines {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := file.SetSheetRow(TEMPLATE_SHEET_INSTRUCTIONS, cell, &line); err != nil {
+			return err
+		}
 	}
-	intervalTime := strings.TrimSpace(row[5])
-	if intervalTime == "" {
-		return nil, fmt.Errorf("invalid row: interval_time is required")
+	if err := file.SetColWidth(TEMPLATE_SHEET_INSTRUCTIONS, "A", "B", 16); err != nil {
+		return err
 	}
-	parsedIntervalTime, err := strconv.ParseInt(intervalTime, 10, 64)
-	if err != nil {
-		return nil, fmt.Errorf("invalid row: interval_time must be a valid number")
+	return file.SetColWidth(TEMPLATE_SHEET_INSTRUCTIONS, "C", "C", 80)
+}
+
+func columnNote(column importColumn) string {
+	if column.required {
+		return column.description + " Required."
 	}
-	server.IntervalTime = int(parsedIntervalTime)
-	return server, nil
+	return column.description + " Optional."
 }
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
+)
+
+func TestExcelizeService_TemplateIsImportable(t *testing.T) {
+	xlsx := NewExcelizeService(zap.NewNop())
+	content, err := xlsx.Template()
+	if err != nil {
+		t.Fatalf("Template() error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "template.xlsx")
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := xlsx.GetRows(path)
+	if err != nil {
+		t.Fatalf("GetRows() error = %v", err)
+	}
+	if err := xlsx.Validate(rows[0]); err != nil {
+		t.Fatalf("template header rejected: %v", err)
+	}
+	for _, row := range rows[1:] {
+		if _, err := xlsx.Parse(row); err != nil {
+			t.Fatalf("example row %v rejected: %v", row, err)
+		}
+	}
+
+	file, err := excelize.OpenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if sheets := file.GetSheetList(); len(sheets) != 2 || sheets[1] != TEMPLATE_SHEET_INSTRUCTIONS {
+		t.Fatalf("unexpected sheets %v", sheets)
+	}
+	comments, err := file.GetComments(TEMPLATE_SHEET_SERVERS)
+	if err != nil || len(comments) != len(importColumns) {
+		t.Fatalf("expected a comment per column, got %d", len(comments))
+	}
+	validations, err := file.GetDataValidations(TEMPLATE_SHEET_SERVERS)
+	if err != nil || len(validations) != 2 {
+		t.Fatalf("expected ipv4 and interval validation, got %d: %v", len(validations), err)
+	}
+}
+
+func TestExcelizeService_Validate(t *testing.T) {
+	xlsx := NewExcelizeService(zap.NewNop())
+
+	if err := xlsx.Validate([]string{"server_id", "server_name", "ipv4", "location", "os", "interval_time"}); err != nil {
+		t.Fatalf("header without the optional group rejected: %v", err)
+	}
+	err := xlsx.Validate([]string{"server_id", "name", "ipv4"})
+	if !errors.Is(err, domain.ErrInvalidFile) || !strings.Contains(err.Error(), `expected "server_name"`) {
+		t.Fatalf("expected the wrong column named, got %v", err)
+	}
+}
+
+func TestExcelizeService_Parse(t *testing.T) {
+	xlsx := NewExcelizeService(zap.NewNop())
+
+	server, err := xlsx.Parse([]string{" s1 ", "web-01", "10.0.0.1", "HN", "ubuntu", "30", "web"})
+	if err != nil || server.ServerID != "s1" || server.IntervalTime != 30 || server.Group != "web" {
+		t.Fatalf("Parse() = %+v, %v", server, err)
+	}
+	// Trailing empty cells are dropped by GetRows.
+	if _, err := xlsx.Parse([]string{"s1", "web-01", "10.0.0.1", "", "", "30"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	for _, tc := range []struct {
+		row  []string
+		want string
+	}{
+		{[]string{"", "web-01", "10.0.0.1", "", "", "30"}, "server_id is required"},
+		{[]string{"s1", "web-01", "10.0.0.256", "", "", "30"}, "ipv4 must be a valid IPv4 address"},
+		{[]string{"s1", "web-01", "::1", "", "", "30"}, "ipv4 must be a valid IPv4 address"},
+		{[]string{"s1", "web-01", "10.0.0.1", "", "", "61"}, "interval_time must be between 1 and 60"},
+		{[]string{"s1", "web-01", "10.0.0.1"}, "interval_time is required"},
+	} {
+		if _, err := xlsx.Parse(tc.row); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Parse(%v) error = %v, want %q", tc.row, err, tc.want)
+		}
+	}
+}
+
+func TestExcelizeService_ExportStyledAndImportable(t *testing.T) {
+	servers := []*dto.ServerResponse{
+		{ServerID: "s1", ServerName: "web-01", IPv4: "10.0.0.1", Status: entity.ServerStatusOnline, Location: "HN", OS: "ubuntu", IntervalTime: 10},
+		{ServerID: "s2", ServerName: "web-02", IPv4: "10.0.0.2", Status: entity.ServerStatusOffline, Location: "HN", OS: "centos", IntervalTime: 20, Group: "web"},
+		{ServerID: "s3", ServerName: "db-01", IPv4: "10.0.0.3", Status: entity.ServerStatusUnknown, Location: "HCM", OS: "ubuntu", IntervalTime: 30},
+	}
+	xlsx := NewExcelizeService(zap.NewNop())
+	content, err := xlsx.Export(servers)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "export.xlsx")
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := excelize.OpenFile(path)
+	if err != nil {
+		t.Fatalf("open error: %v", err)
+	}
+	defer file.Close()
+
+	if sheets := file.GetSheetList(); len(sheets) != 3 || sheets[0] != EXPORT_SHEET_SERVERS || sheets[1] != EXPORT_SHEET_SUMMARY {
+		t.Fatalf("unexpected sheets %v", sheets)
+	}
+	if visible, _ := file.GetSheetVisible(EXPORT_SHEET_LISTS); visible {
+		t.Fatalf("expected the lists sheet hidden")
+	}
+
+	// The header follows importColumns, with status last.
+	header, _ := file.GetRows(EXPORT_SHEET_SERVERS)
+	if len(header[0]) != len(importColumns)+1 || header[0][len(importColumns)] != EXPORT_STATUS_HEADER {
+		t.Fatalf("unexpected header %v", header[0])
+	}
+	for i, column := range importColumns {
+		if header[0][i] != column.header {
+			t.Fatalf("column %d is %q, want %q", i+1, header[0][i], column.header)
+		}
+	}
+
+	panes, _ := file.GetPanes(EXPORT_SHEET_SERVERS)
+	if !panes.Freeze || panes.YSplit != 1 {
+		t.Fatalf("expected the header row frozen, got %+v", panes)
+	}
+	var autofilter bool
+	for _, name := range file.GetDefinedName() {
+		autofilter = autofilter || (name.Name == "_xlnm._FilterDatabase" && name.Scope == EXPORT_SHEET_SERVERS)
+	}
+	if !autofilter {
+		t.Fatalf("expected an autofilter on the server sheet")
+	}
+	validations, _ := file.GetDataValidations(EXPORT_SHEET_SERVERS)
+	if len(validations) != 3 {
+		t.Fatalf("expected ipv4, os and interval validations, got %d", len(validations))
+	}
+	for _, validation := range validations {
+		if strings.HasPrefix(validation.Sqref, "H") {
+			t.Fatalf("expected no validation on the read-only status column, got %s", validation.Sqref)
+		}
+	}
+
+	// Status cells are locked on a protected sheet; importable cells are not.
+	online, _ := file.GetCellStyle(EXPORT_SHEET_SERVERS, "H2")
+	offline, _ := file.GetCellStyle(EXPORT_SHEET_SERVERS, "H3")
+	if online == 0 || online == offline {
+		t.Fatalf("expected distinct status styles, got %d and %d", online, offline)
+	}
+	statusStyle, _ := file.GetStyle(online)
+	if statusStyle.Protection != nil && !statusStyle.Protection.Locked {
+		t.Fatalf("expected status cells locked")
+	}
+	nameStyleID, _ := file.GetCellStyle(EXPORT_SHEET_SERVERS, "B2")
+	nameStyle, _ := file.GetStyle(nameStyleID)
+	if nameStyle.Protection == nil || nameStyle.Protection.Locked {
+		t.Fatalf("expected importable cells unlocked, got %+v", nameStyle.Protection)
+	}
+	if protection := sheetXML(t, content, "xl/worksheets/sheet1.xml"); !strings.Contains(protection, "<sheetProtection") {
+		t.Fatalf("expected the server sheet protected")
+	}
+
+	if count, _ := file.GetCellValue(EXPORT_SHEET_SUMMARY, "B5"); count != "1" {
+		t.Fatalf("expected 1 offline server in the summary, got %q", count)
+	}
+	if location, _ := file.GetCellValue(EXPORT_SHEET_SUMMARY, "D4"); location != "HN" {
+		t.Fatalf("expected the largest location first, got %q", location)
+	}
+
+	// The server sheet goes back through the importer unchanged.
+	rows, err := xlsx.GetRows(path)
+	if err != nil || xlsx.Validate(rows[0]) != nil {
+		t.Fatalf("export not importable: %v", err)
+	}
+	parsed, err := xlsx.Parse(rows[2])
+	if err != nil || parsed.ServerID != "s2" || parsed.IntervalTime != 20 || parsed.Group != "web" {
+		t.Fatalf("unexpected parsed row %+v, %v", parsed, err)
+	}
+}
+
+// sheetXML returns a part of the workbook package as text.
+func sheetXML(t *testing.T, content []byte, name string) string {
+	t.Helper()
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		t.Fatalf("workbook is not a zip: %v", err)
+	}
+	part, err := archive.Open(name)
+	if err != nil {
+		t.Fatalf("open %s: %v", name, err)
+	}
+	defer part.Close()
+	data, err := io.ReadAll(part)
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+	return string(data)
+}
@@ -0,0 +1,361 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	EXPORT_SHEET_SERVERS = "Servers"
+	EXPORT_SHEET_SUMMARY = "Summary"
+	// EXPORT_SHEET_LISTS is hidden and holds the values offered by dropdowns.
+	EXPORT_SHEET_LISTS = "Lists"
+
+	// EXPORT_VALIDATION_ROWS is how far down the dropdowns reach, leaving room
+	// for rows added by hand before the file is imported again.
+	EXPORT_VALIDATION_ROWS = 5000
+)
+
+// The server sheet has the importColumns, so an edited export can be imported
+// again, followed by the status column. Status is reported by the servers
+// themselves: its cells are locked and the importer ignores the column.
+const (
+	EXPORT_STATUS_HEADER = "status"
+	EXPORT_STATUS_WIDTH  = 12
+)
+
+// statusColours fills status cells; unknown statuses keep the plain style.
+var statusColours = map[entity.ServerStatus]string{
+	entity.ServerStatusOnline:  "C6EFCE",
+	entity.ServerStatusOffline: "FFC7CE",
+	entity.ServerStatusUnknown: "E7E6E6",
+}
+
+var exportStatuses = []entity.ServerStatus{
+	entity.ServerStatusOnline,
+	entity.ServerStatusOffline,
+	entity.ServerStatusUnknown,
+}
+
+type exportStyles struct {
+	header int
+	status map[entity.ServerStatus]int
+	title  int
+	// editable unlocks the importable columns of the protected server sheet.
+	editable int
+}
+
+// Export lays out the export workbook and returns its content.
+func (e *excelizeService) Export(servers []*dto.ServerResponse) ([]byte, error) {
+	file, err := buildExportWorkbook(servers)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var buf bytes.Buffer
+	if _, err := file.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// buildExportWorkbook lays out the server sheet, which stays first so the
+// importer reads it, a summary sheet with a pie chart by status, and the
+// hidden lists backing the dropdowns. The summary is the sheet shown on open.
+func buildExportWorkbook(servers []*dto.ServerResponse) (*excelize.File, error) {
+	file := excelize.NewFile()
+	if err := file.SetSheetName("Sheet1", EXPORT_SHEET_SERVERS); err != nil {
+		file.Close()
+		return nil, err
+	}
+	summaryIndex, err := file.NewSheet(EXPORT_SHEET_SUMMARY)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err := file.NewSheet(EXPORT_SHEET_LISTS); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	styles, err := newExportStyles(file)
+	if err == nil {
+		err = writeLists(file, servers)
+	}
+	if err == nil {
+		err = writeServers(file, styles, servers)
+	}
+	if err == nil {
+		err = writeSummary(file, styles, servers)
+	}
+	if err == nil {
+		err = file.SetSheetVisible(EXPORT_SHEET_LISTS, false)
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	file.SetActiveSheet(summaryIndex)
+	return file, nil
+}
+
+func newExportStyles(file *excelize.File) (*exportStyles, error) {
+	border := []excelize.Border{
+		{Type: "left", Color: "BFBFBF", Style: 1},
+		{Type: "right", Color: "BFBFBF", Style: 1},
+		{Type: "top", Color: "BFBFBF", Style: 1},
+		{Type: "bottom", Color: "BFBFBF", Style: 1},
+	}
+
+	header, err := file.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true, Color: "FFFFFF"},
+		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"1F4E78"}},
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center"},
+		Border:    border,
+	})
+	if err != nil {
+		return nil, err
+	}
+	title, err := file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Size: 14, Color: "1F4E78"}})
+	if err != nil {
+		return nil, err
+	}
+	editable, err := file.NewStyle(&excelize.Style{Protection: &excelize.Protection{Locked: false}})
+	if err != nil {
+		return nil, err
+	}
+
+	styles := &exportStyles{header: header, title: title, editable: editable, status: make(map[entity.ServerStatus]int)}
+	for status, colour := range statusColours {
+		id, err := file.NewStyle(&excelize.Style{
+			Font:      &excelize.Font{Bold: true},
+			Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{colour}},
+			Alignment: &excelize.Alignment{Horizontal: "center"},
+			Border:    border,
+		})
+		if err != nil {
+			return nil, err
+		}
+		styles.status[status] = id
+	}
+	return styles, nil
+}
+
+// writeLists fills the hidden sheet with the operating systems found in the
+// export.
+func writeLists(file *excelize.File, servers []*dto.ServerResponse) error {
+	for i, value := range distinctOS(servers) {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := file.SetCellStr(EXPORT_SHEET_LISTS, cell, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeServers streams the server rows. The autofilter, validations and
+// protection are set up first because the stream writer takes the worksheet
+// settings as they are when it is created.
+func writeServers(file *excelize.File, styles *exportStyles, servers []*dto.ServerResponse) error {
+	statusIndex := len(importColumns) + 1
+	lastColumn, _ := excelize.ColumnNumberToName(statusIndex)
+	if err := file.AutoFilter(EXPORT_SHEET_SERVERS, fmt.Sprintf("A1:%s%d", lastColumn, len(servers)+1), nil); err != nil {
+		return err
+	}
+
+	// The template's validations apply to edited and added rows alike.
+	for i, column := range importColumns {
+		name, _ := excelize.ColumnNumberToName(i + 1)
+		sqref := fmt.Sprintf("%s2:%s%d", name, name, EXPORT_VALIDATION_ROWS)
+		var dv *excelize.DataValidation
+		switch {
+		case column.validation != nil:
+			var err error
+			if dv, err = column.validation(sqref, name+"2"); err != nil {
+				return err
+			}
+		case column.header == "os" && len(distinctOS(servers)) > 0:
+			// The OS list only suggests the values already in use; new ones
+			// are allowed.
+			dv = excelize.NewDataValidation(true)
+			dv.SetSqref(sqref)
+			dv.SetSqrefDropList(fmt.Sprintf("%s!$A$1:$A$%d", EXPORT_SHEET_LISTS, len(distinctOS(servers))))
+			dv.ShowErrorMessage = false
+		default:
+			continue
+		}
+		if err := file.AddDataValidation(EXPORT_SHEET_SERVERS, dv); err != nil {
+			return err
+		}
+	}
+
+	// Only the status column and the header stay locked; filtering, sorting
+	// and adding rows remain allowed.
+	if err := file.ProtectSheet(EXPORT_SHEET_SERVERS, &excelize.SheetProtectionOptions{
+		AutoFilter:          true,
+		Sort:                true,
+		InsertRows:          true,
+		DeleteRows:          true,
+		FormatColumns:       true,
+		SelectLockedCells:   true,
+		SelectUnlockedCells: true,
+	}); err != nil {
+		return err
+	}
+
+	writer, err := file.NewStreamWriter(EXPORT_SHEET_SERVERS)
+	if err != nil {
+		return err
+	}
+	if err := writer.SetPanes(&excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+		Selection:   []excelize.Selection{{SQRef: "A2", ActiveCell: "A2", Pane: "bottomLeft"}},
+	}); err != nil {
+		return err
+	}
+
+	header := make([]interface{}, 0, statusIndex)
+	for i, column := range importColumns {
+		if err := writer.SetColWidth(i+1, i+1, column.width); err != nil {
+			return err
+		}
+		header = append(header, excelize.Cell{StyleID: styles.header, Value: column.header})
+	}
+	if err := writer.SetColStyle(1, len(importColumns), styles.editable); err != nil {
+		return err
+	}
+	if err := writer.SetColWidth(statusIndex, statusIndex, EXPORT_STATUS_WIDTH); err != nil {
+		return err
+	}
+	header = append(header, excelize.Cell{StyleID: styles.header, Value: EXPORT_STATUS_HEADER})
+	if err := writer.SetRow("A1", header, excelize.RowOpts{Height: 20}); err != nil {
+		return err
+	}
+
+	for rowIndex, server := range servers {
+		cell, _ := excelize.CoordinatesToCellName(1, rowIndex+2)
+		row := make([]interface{}, 0, statusIndex)
+		for _, column := range importColumns {
+			row = append(row, excelize.Cell{StyleID: styles.editable, Value: column.export(server)})
+		}
+		row = append(row, excelize.Cell{StyleID: styles.status[server.Status], Value: string(server.Status)})
+		if err := writer.SetRow(cell, row); err != nil {
+			return fmt.Errorf("failed to write server %s: %w", server.ServerID, err)
+		}
+	}
+	return writer.Flush()
+}
+
+// writeSummary counts the exported servers by status and by location and
+// charts the status split.
+func writeSummary(file *excelize.File, styles *exportStyles, servers []*dto.ServerResponse) error {
+	byStatus := make(map[entity.ServerStatus]int)
+	byLocation := make(map[string]int)
+	for _, server := range servers {
+		byStatus[server.Status]++
+		byLocation[server.Location]++
+	}
+
+	set := func(cell string, value interface{}, style int) error {
+		if err := file.SetCellValue(EXPORT_SHEET_SUMMARY, cell, value); err != nil {
+			return err
+		}
+		if style == 0 {
+			return nil
+		}
+		return file.SetCellStyle(EXPORT_SHEET_SUMMARY, cell, cell, style)
+	}
+
+	if err := set("A1", fmt.Sprintf("Servers exported: %d", len(servers)), styles.title); err != nil {
+		return err
+	}
+	if err := file.SetColWidth(EXPORT_SHEET_SUMMARY, "A", "A", 20); err != nil {
+		return err
+	}
+	if err := file.SetColWidth(EXPORT_SHEET_SUMMARY, "D", "D", 20); err != nil {
+		return err
+	}
+
+	// Counts by status in A3:B6.
+	if err := set("A3", "status", styles.header); err != nil {
+		return err
+	}
+	if err := set("B3", "count", styles.header); err != nil {
+		return err
+	}
+	for i, status := range exportStatuses {
+		row := i + 4
+		if err := set(fmt.Sprintf("A%d", row), string(status), styles.status[status]); err != nil {
+			return err
+		}
+		if err := set(fmt.Sprintf("B%d", row), byStatus[status], 0); err != nil {
+			return err
+		}
+	}
+
+	// Counts by location in D3:E*, largest first.
+	if err := set("D3", "location", styles.header); err != nil {
+		return err
+	}
+	if err := set("E3", "count", styles.header); err != nil {
+		return err
+	}
+	locations := make([]string, 0, len(byLocation))
+	for location := range byLocation {
+		locations = append(locations, location)
+	}
+	sort.Slice(locations, func(i, j int) bool {
+		if byLocation[locations[i]] != byLocation[locations[j]] {
+			return byLocation[locations[i]] > byLocation[locations[j]]
+		}
+		return locations[i] < locations[j]
+	})
+	for i, location := range locations {
+		row := i + 4
+		name := location
+		if name == "" {
+			name = "(none)"
+		}
+		if err := set(fmt.Sprintf("D%d", row), name, 0); err != nil {
+			return err
+		}
+		if err := set(fmt.Sprintf("E%d", row), byLocation[location], 0); err != nil {
+			return err
+		}
+	}
+
+	lastStatusRow := len(exportStatuses) + 3
+	return file.AddChart(EXPORT_SHEET_SUMMARY, "G3", &excelize.Chart{
+		Type: excelize.Pie,
+		Series: []excelize.ChartSeries{{
+			Name:       fmt.Sprintf("%s!$B$3", EXPORT_SHEET_SUMMARY),
+			Categories: fmt.Sprintf("%s!$A$4:$A$%d", EXPORT_SHEET_SUMMARY, lastStatusRow),
+			Values:     fmt.Sprintf("%s!$B$4:$B$%d", EXPORT_SHEET_SUMMARY, lastStatusRow),
+		}},
+		Title:    []excelize.RichTextRun{{Text: "Servers by status"}},
+		Legend:   excelize.ChartLegend{Position: "right"},
+		PlotArea: excelize.ChartPlotArea{ShowPercent: true},
+	})
+}
+
+func distinctOS(servers []*dto.ServerResponse) []string {
+	seen := make(map[string]bool)
+	var values []string
+	for _, server := range servers {
+		if server.OS != "" && !seen[server.OS] {
+			seen[server.OS] = true
+			values = append(values, server.OS)
+		}
+	}
+	sort.Strings(values)
+	return values
+}
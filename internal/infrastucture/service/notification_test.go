@@ -0,0 +1,183 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	srv "github.com/th1enq/ViettelSMS_ServerService/internal/domain/service"
+)
+
+type fakeMail struct {
+	from       string
+	recipients []string
+	data       string
+}
+
+// fakeSMTPServer accepts a single session without STARTTLS or AUTH and hands
+// back what it received.
+func fakeSMTPServer(t *testing.T) (string, int, <-chan fakeMail) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	mails := make(chan fakeMail, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		reply("220 fake ESMTP")
+
+		var mail fakeMail
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			switch command := strings.ToUpper(line); {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 fake")
+			case strings.HasPrefix(command, "MAIL FROM:"):
+				mail.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+				reply("250 OK")
+			case strings.HasPrefix(command, "RCPT TO:"):
+				mail.recipients = append(mail.recipients, strings.Trim(line[len("RCPT TO:"):], "<>"))
+				reply("250 OK")
+			case command == "DATA":
+				reply("354 go ahead")
+				var data strings.Builder
+				for {
+					dataLine, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if dataLine == ".\r\n" {
+						break
+					}
+					data.WriteString(dataLine)
+				}
+				mail.data = data.String()
+				reply("250 queued")
+			case command == "QUIT":
+				reply("221 bye")
+				mails <- mail
+				return
+			default:
+				reply("502 not implemented")
+			}
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	return host, portNumber, mails
+}
+
+func TestSMTPChannel_Send(t *testing.T) {
+	host, port, mails := fakeSMTPServer(t)
+	channel := NewSMTPChannel(config.SMTP{Host: host, Port: port, From: "monitor@example.com"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := channel.Send(ctx, []string{"oncall@example.com", "sms-team@example.com"}, "[OFFLINE] web-01", "web-01 is down\nsince 10:00")
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	mail := <-mails
+	if mail.from != "monitor@example.com" {
+		t.Errorf("MAIL FROM = %q", mail.from)
+	}
+	if len(mail.recipients) != 2 || mail.recipients[1] != "sms-team@example.com" {
+		t.Errorf("RCPT TO = %v", mail.recipients)
+	}
+	for _, want := range []string{
+		"To: oncall@example.com, sms-team@example.com\r\n",
+		"Subject: [OFFLINE] web-01\r\n",
+		"\r\n\r\nweb-01 is down\r\nsince 10:00\r\n",
+	} {
+		if !strings.Contains(mail.data, want) {
+			t.Errorf("message missing %q:\n%s", want, mail.data)
+		}
+	}
+}
+
+func TestSMTPChannel_SendWithAttachment(t *testing.T) {
+	host, port, mails := fakeSMTPServer(t)
+	channel := NewSMTPChannel(config.SMTP{Host: host, Port: port, From: "monitor@example.com"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	content := []byte(strings.Repeat("report", 30))
+	err := channel.SendWithAttachment(ctx, []string{"managers@example.com"}, "Weekly report", "See attached.", srv.Attachment{
+		Name:        "report.xlsx",
+		ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		Content:     content,
+	})
+	if err != nil {
+		t.Fatalf("SendWithAttachment() error = %v", err)
+	}
+
+	mail := <-mails
+	for _, want := range []string{
+		"Content-Type: multipart/mixed; boundary=",
+		"\r\n\r\nSee attached.\r\n",
+		"Content-Disposition: attachment; filename=report.xlsx\r\n",
+	} {
+		if !strings.Contains(mail.data, want) {
+			t.Errorf("message missing %q:\n%s", want, mail.data)
+		}
+	}
+
+	_, encoded, _ := strings.Cut(mail.data, "Content-Transfer-Encoding: base64\r\n")
+	_, encoded, _ = strings.Cut(encoded, "\r\n\r\n")
+	encoded, _, _ = strings.Cut(encoded, "\r\n--")
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(encoded, "\r\n", ""))
+	if err != nil || string(decoded) != string(content) {
+		t.Errorf("attachment not decoded back: %v", err)
+	}
+}
+
+func TestSMSGatewayChannel_Send(t *testing.T) {
+	var got smsRequest
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer gateway.Close()
+
+	channel := NewSMSGatewayChannel(config.SMS{URL: gateway.URL, Token: "secret", Timeout: time.Second})
+	if err := channel.Send(context.Background(), []string{"+84900000000"}, "ignored", "web-01 down"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if len(got.To) != 1 || got.To[0] != "+84900000000" || got.Message != "web-01 down" {
+		t.Errorf("gateway received %+v", got)
+	}
+
+	channel = NewSMSGatewayChannel(config.SMS{URL: gateway.URL, Token: "wrong", Timeout: time.Second})
+	if err := channel.Send(context.Background(), []string{"+84900000000"}, "", "web-01 down"); err == nil {
+		t.Error("Send() error = nil, want error for rejected request")
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	srv "github.com/th1enq/ViettelSMS_ServerService/internal/domain/service"
+)
+
+// SWEEP_INTERVAL is how often buckets that refilled completely are dropped.
+const SWEEP_INTERVAL = time.Minute
+
+type memoryRateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type bucket struct {
+	tokens   float64
+	capacity float64
+	rate     float64 // tokens per second
+	updated  time.Time
+}
+
+// NewMemoryRateLimiter keeps the buckets in this process, so each replica
+// enforces its own limits.
+func NewMemoryRateLimiter() srv.RateLimiter {
+	return &memoryRateLimiter{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+func (m *memoryRateLimiter) Allow(ctx context.Context, key string, limit int, period time.Duration) (bool, time.Duration, error) {
+	if limit <= 0 || period <= 0 {
+		return true, 0, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit), updated: now}
+		m.buckets[key] = b
+	}
+	// Limits may change between calls, so the bucket follows the latest one.
+	b.capacity = float64(limit)
+	b.rate = float64(limit) / period.Seconds()
+	b.refill(now)
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0, nil
+	}
+	wait := time.Duration(math.Ceil((1 - b.tokens) / b.rate * float64(time.Second)))
+	return false, wait, nil
+}
+
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.capacity, b.tokens+elapsed*b.rate)
+	}
+	b.updated = now
+}
+
+// sweep drops full buckets, which behave exactly like missing ones.
+func (m *memoryRateLimiter) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < SWEEP_INTERVAL {
+		return
+	}
+	m.lastSweep = now
+	for key, b := range m.buckets {
+		b.refill(now)
+		if b.tokens >= b.capacity {
+			delete(m.buckets, key)
+		}
+	}
+}
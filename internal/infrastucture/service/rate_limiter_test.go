@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryRateLimiter_RefillsOverPeriod(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewMemoryRateLimiter().(*memoryRateLimiter)
+	limiter.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if ok, _, _ := limiter.Allow(ctx, "export|alice", 3, time.Minute); !ok {
+			t.Fatalf("request %d within the burst was rejected", i+1)
+		}
+	}
+	ok, wait, _ := limiter.Allow(ctx, "export|alice", 3, time.Minute)
+	if ok || wait != 20*time.Second {
+		t.Fatalf("want rejection with a 20s wait, got %v %v", ok, wait)
+	}
+	if ok, _, _ := limiter.Allow(ctx, "export|bob", 3, time.Minute); !ok {
+		t.Fatalf("buckets must be per key")
+	}
+
+	now = now.Add(20 * time.Second)
+	if ok, _, _ := limiter.Allow(ctx, "export|alice", 3, time.Minute); !ok {
+		t.Fatalf("a token should be back after 20s")
+	}
+	if ok, _, _ := limiter.Allow(ctx, "export|alice", 3, time.Minute); ok {
+		t.Fatalf("only one token should have been refilled")
+	}
+}
+
+func TestMemoryRateLimiter_SweepsFullBuckets(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewMemoryRateLimiter().(*memoryRateLimiter)
+	limiter.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	limiter.Allow(ctx, "a", 10, time.Second)
+	now = now.Add(2 * SWEEP_INTERVAL)
+	limiter.Allow(ctx, "b", 10, time.Second)
+	if _, ok := limiter.buckets["a"]; ok || len(limiter.buckets) != 1 {
+		t.Fatalf("idle bucket was not swept: %v", limiter.buckets)
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	srv "github.com/th1enq/ViettelSMS_ServerService/internal/domain/service"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+type smsGatewayChannel struct {
+	config config.SMS
+	client *http.Client
+}
+
+type smsRequest struct {
+	To      []string `json:"to"`
+	Message string   `json:"message"`
+}
+
+// NewSMSGatewayChannel posts {"to": [...], "message": "..."} to an HTTP SMS
+// gateway, authenticated with a bearer token when one is configured. The
+// subject is not part of an SMS and is dropped.
+func NewSMSGatewayChannel(config config.SMS) srv.NotificationChannel {
+	return &smsGatewayChannel{
+		config: config,
+		client: &http.Client{
+			Timeout:   config.Timeout,
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+		},
+	}
+}
+
+func (s *smsGatewayChannel) Send(ctx context.Context, recipients []string, _ string, body string) error {
+	payload, err := json.Marshal(smsRequest{To: recipients, Message: body})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.config.Token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sms gateway responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
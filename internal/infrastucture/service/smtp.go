@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	srv "github.com/th1enq/ViettelSMS_ServerService/internal/domain/service"
+)
+
+// SMTP_TIMEOUT bounds a whole SMTP session when the context has no deadline.
+const SMTP_TIMEOUT = 30 * time.Second
+
+type smtpChannel struct {
+	config config.SMTP
+}
+
+// NewSMTPChannel sends plain-text email, optionally with an attachment.
+// STARTTLS is used when the server offers it, and authentication when a
+// username is configured.
+func NewSMTPChannel(config config.SMTP) srv.AttachmentChannel {
+	return &smtpChannel{config: config}
+}
+
+func (s *smtpChannel) Send(ctx context.Context, recipients []string, subject, body string) error {
+	return s.deliver(ctx, recipients, s.message(recipients, subject, body))
+}
+
+func (s *smtpChannel) SendWithAttachment(ctx context.Context, recipients []string, subject, body string, attachment srv.Attachment) error {
+	return s.deliver(ctx, recipients, s.multipartMessage(recipients, subject, body, attachment))
+}
+
+func (s *smtpChannel) deliver(ctx context.Context, recipients []string, message []byte) error {
+	addr := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(SMTP_TIMEOUT)
+	}
+	_ = conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start smtp session: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.config.Host}); err != nil {
+			return fmt.Errorf("failed to start tls: %w", err)
+		}
+	}
+	if s.config.Username != "" {
+		auth := smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("smtp authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(s.config.From); err != nil {
+		return fmt.Errorf("smtp MAIL FROM rejected: %w", err)
+	}
+	for _, recipient := range recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("smtp RCPT TO %s rejected: %w", recipient, err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA rejected: %w", err)
+	}
+	if _, err := writer.Write(message); err != nil {
+		writer.Close()
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("smtp message rejected: %w", err)
+	}
+	return client.Quit()
+}
+
+func (s *smtpChannel) message(recipients []string, subject, body string) []byte {
+	var msg strings.Builder
+	s.writeHeaders(&msg, recipients, subject)
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(crlf(body))
+	msg.WriteString("\r\n")
+	return []byte(msg.String())
+}
+
+// multipartMessage puts the body and a base64 encoded attachment in a
+// multipart/mixed message.
+func (s *smtpChannel) multipartMessage(recipients []string, subject, body string, attachment srv.Attachment) []byte {
+	var random [12]byte
+	_, _ = rand.Read(random[:])
+	boundary := "boundary-" + hex.EncodeToString(random[:])
+
+	contentType := attachment.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	var msg strings.Builder
+	s.writeHeaders(&msg, recipients, subject)
+	msg.WriteString("Content-Type: multipart/mixed; boundary=\"" + boundary + "\"\r\n")
+	msg.WriteString("\r\n")
+
+	msg.WriteString("--" + boundary + "\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(crlf(body))
+	msg.WriteString("\r\n")
+
+	msg.WriteString("--" + boundary + "\r\n")
+	msg.WriteString("Content-Type: " + mime.FormatMediaType(contentType, map[string]string{"name": attachment.Name}) + "\r\n")
+	msg.WriteString("Content-Transfer-Encoding: base64\r\n")
+	msg.WriteString("Content-Disposition: " + mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name}) + "\r\n")
+	msg.WriteString("\r\n")
+	encoded := base64.StdEncoding.EncodeToString(attachment.Content)
+	for len(encoded) > 76 {
+		msg.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	msg.WriteString(encoded + "\r\n")
+	msg.WriteString("--" + boundary + "--\r\n")
+	return []byte(msg.String())
+}
+
+func (s *smtpChannel) writeHeaders(msg *strings.Builder, recipients []string, subject string) {
+	msg.WriteString("From: " + s.config.From + "\r\n")
+	msg.WriteString("To: " + strings.Join(recipients, ", ") + "\r\n")
+	msg.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n")
+	msg.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
+}
+
+func crlf(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\n", "\r\n")
+}
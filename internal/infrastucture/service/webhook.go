@@ -0,0 +1,167 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/requestid"
+	srv "github.com/th1enq/ViettelSMS_ServerService/internal/domain/service"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// Headers set on every webhook request. The signature is the hex HMAC-SHA256
+// of "<timestamp>.<body>" keyed with the webhook secret, prefixed by "sha256=".
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookIDHeader        = "X-Webhook-ID"
+)
+
+type webhookSender struct {
+	client *http.Client
+	guard  addressGuard
+}
+
+// NewWebhookSender builds a sender that refuses to connect to loopback,
+// private, link-local and unspecified addresses outside cfg.AllowedNetworks.
+func NewWebhookSender(cfg config.Webhook) (srv.WebhookSender, error) {
+	guard := addressGuard{}
+	for _, network := range cfg.AllowedNetworks {
+		prefix, err := netip.ParsePrefix(network)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook allowed network %q: %w", network, err)
+		}
+		guard.allowed = append(guard.allowed, prefix.Masked())
+	}
+
+	// The guard checks the address actually dialled, after DNS resolution, so
+	// a name re-pointed since registration is caught too. A proxy would hide
+	// that address, hence none is used.
+	dialer := &net.Dialer{Timeout: cfg.Timeout, Control: guard.control}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &webhookSender{
+		client: &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: otelhttp.NewTransport(transport),
+			// A redirect would resend the signed body to a URL nobody registered.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		guard: guard,
+	}, nil
+}
+
+func (w *webhookSender) CheckURL(ctx context.Context, rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	host := parsed.Hostname()
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return w.guard.check(addr)
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("resolve webhook host %q: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if err := w.guard.check(addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *webhookSender) Send(ctx context.Context, url, secret string, event entity.WebhookEvent, eventID string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ViettelSMS-ServerService-Webhook")
+	req.Header.Set(WebhookEventHeader, string(event))
+	req.Header.Set(WebhookIDHeader, eventID)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(secret, timestamp, body))
+	if id := requestid.FromContext(ctx); id != "" {
+		req.Header.Set(requestid.Header, id)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain a little so the connection can be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// SignWebhook returns the signature header value for a payload.
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// addressGuard keeps webhooks from reaching the service's own network, such as
+// internal endpoints or the cloud metadata address 169.254.169.254.
+type addressGuard struct {
+	allowed []netip.Prefix
+}
+
+func (g addressGuard) check(addr netip.Addr) error {
+	addr = addr.Unmap()
+	for _, prefix := range g.allowed {
+		if prefix.Contains(addr) {
+			return nil
+		}
+	}
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() {
+		return fmt.Errorf("webhook address %s is not allowed", addr)
+	}
+	return nil
+}
+
+// control runs between resolving and connecting, with the address about to be
+// dialled.
+func (g addressGuard) control(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	return g.check(addr)
+}
@@ -0,0 +1,109 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+func TestNewTracerProvider_ExportsSampledSpans(t *testing.T) {
+	cases := []struct {
+		name  string
+		ratio float64
+		want  int
+	}{
+		{"always", 1, 1},
+		{"never", 0, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			exporter := tracetest.NewInMemoryExporter()
+			cfg := &config.Config{Tracing: config.Tracing{ServiceName: "server-service-test", SampleRatio: tc.ratio}}
+			provider, err := newTracerProvider(cfg, exporter)
+			if err != nil {
+				t.Fatalf("new provider: %v", err)
+			}
+
+			_, span := provider.Tracer("test").Start(context.Background(), "work")
+			span.End()
+			if err := provider.ForceFlush(context.Background()); err != nil {
+				t.Fatalf("flush: %v", err)
+			}
+
+			spans := exporter.GetSpans()
+			if len(spans) != tc.want {
+				t.Fatalf("got %d spans, want %d", len(spans), tc.want)
+			}
+			if tc.want == 0 {
+				return
+			}
+			if spans[0].Name != "work" {
+				t.Fatalf("got span %q, want work", spans[0].Name)
+			}
+			if name, ok := spans[0].Resource.Set().Value(semconv.ServiceNameKey); !ok || name.AsString() != "server-service-test" {
+				t.Fatalf("got service name %v, want server-service-test", name)
+			}
+		})
+	}
+}
+
+func TestNewTracerProvider_FollowsSampledParent(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider, err := newTracerProvider(&config.Config{Tracing: config.Tracing{SampleRatio: 0}}, exporter)
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+
+	// An upstream service decided to sample: the ratio does not drop the span.
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	_, span := provider.Tracer("test").Start(trace.ContextWithRemoteSpanContext(context.Background(), parent), "child")
+	span.End()
+	if err := provider.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Parent.SpanID() != parent.SpanID() {
+		t.Fatalf("got %+v, want one child of the remote parent", spans)
+	}
+}
+
+func TestNewProvider_Exporters(t *testing.T) {
+	provider, err := NewProvider(&config.Config{Tracing: config.Tracing{Exporter: ExporterNone}}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("none exporter: %v", err)
+	}
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	if _, err := NewProvider(&config.Config{Tracing: config.Tracing{Exporter: "jaeger"}}, zap.NewNop()); err == nil {
+		t.Fatal("want an error for an unsupported exporter")
+	}
+}
+
+func TestNewProvider_PropagatesWithoutExporter(t *testing.T) {
+	if _, err := NewProvider(&config.Config{Tracing: config.Tracing{Exporter: ExporterNone}}, zap.NewNop()); err != nil {
+		t.Fatalf("none exporter: %v", err)
+	}
+
+	carrier := propagation.MapCarrier{"traceparent": "00-0102030405060708090a0b0c0d0e0f10-0102030405060708-01"}
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), carrier)
+	out := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, out)
+	if out["traceparent"] != carrier["traceparent"] {
+		t.Fatalf("got traceparent %q, want %q", out["traceparent"], carrier["traceparent"])
+	}
+}
@@ -0,0 +1,25 @@
+package alert
+
+import (
+	"context"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+)
+
+type UseCase interface {
+	ViewAlerts(ctx context.Context, filter dto.AlertFilterOptions, pagination dto.AlertPaginationOptions) ([]*dto.AlertResponse, int, error)
+
+	CreateMaintenanceWindow(ctx context.Context, params dto.CreateMaintenanceWindowParams) (*dto.MaintenanceWindowResponse, error)
+	ListMaintenanceWindows(ctx context.Context, filter dto.MaintenanceWindowFilterOptions) ([]*dto.MaintenanceWindowResponse, error)
+	DeleteMaintenanceWindow(ctx context.Context, id uint) error
+
+	// StatusChanged queues the transition to be recorded and the rules it
+	// affects evaluated, and returns immediately.
+	StatusChanged(ctx context.Context, event dto.StatusChangedEvent)
+	// Drain stops accepting events and waits for queued evaluations until ctx
+	// expires.
+	Drain(ctx context.Context) error
+	// Evaluate fires time-based alerts and resolves firing alerts whose
+	// condition no longer holds, across all tenants.
+	Evaluate(ctx context.Context) error
+}
@@ -0,0 +1,322 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/authz"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	repo "github.com/th1enq/ViettelSMS_ServerService/internal/domain/repository"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/queue"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// TRANSITION_RETENTION is how long status transitions are kept for flap
+// detection and troubleshooting.
+const TRANSITION_RETENTION = 7 * 24 * time.Hour
+
+type alertUseCase struct {
+	repo    repo.AlertRepository
+	servers repo.ServerRepository
+	config  config.Alert
+	logger  *zap.Logger
+
+	evaluations *queue.Queue
+}
+
+func NewAlertUseCase(
+	repo repo.AlertRepository,
+	servers repo.ServerRepository,
+	config *config.Config,
+	logger *zap.Logger,
+) UseCase {
+	return &alertUseCase{
+		repo:        repo,
+		servers:     servers,
+		config:      config.Alert,
+		logger:      logger,
+		evaluations: queue.New(config.Alert.Workers),
+	}
+}
+
+func (a *alertUseCase) ViewAlerts(ctx context.Context, filter dto.AlertFilterOptions, pagination dto.AlertPaginationOptions) ([]*dto.AlertResponse, int, error) {
+	alerts, total, err := a.repo.GetAlerts(ctx, filter, pagination)
+	if err != nil {
+		log.LoggerWithContext(ctx, a.logger).Error("failed to get alerts", zap.Error(err))
+		return nil, 0, domain.ErrDatabase
+	}
+	return dto.ToAlertsResponse(alerts), total, nil
+}
+
+func (a *alertUseCase) CreateMaintenanceWindow(ctx context.Context, params dto.CreateMaintenanceWindowParams) (*dto.MaintenanceWindowResponse, error) {
+	logger := log.LoggerWithContext(ctx, a.logger)
+	logger.Info("CreateMaintenanceWindow called", zap.Any("request", params))
+
+	window := &entity.MaintenanceWindow{
+		StartsAt: params.StartsAt,
+		EndsAt:   params.EndsAt,
+		Reason:   params.Reason,
+	}
+	// A window silences alerts and notifications, so it may only cover
+	// servers the caller can see.
+	principal := authz.FromContext(ctx)
+	if params.ServerID != nil {
+		server, err := a.servers.GetByField(ctx, "server_id", *params.ServerID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				logger.Warn("Server not found", zap.String("server_id", *params.ServerID))
+				return nil, domain.ErrServerNotFound
+			}
+			logger.Error("failed to get server by ID", zap.String("server_id", *params.ServerID), zap.Error(err))
+			return nil, domain.ErrDatabase
+		}
+		if !principal.CanAccess(server) {
+			logger.Warn("Server is outside caller constraints", zap.String("server_id", *params.ServerID))
+			return nil, domain.ErrForbidden
+		}
+		window.ServerID = *params.ServerID
+	}
+	if params.Group != nil {
+		if !principal.CanAccessGroup(*params.Group) {
+			logger.Warn("Group is outside caller constraints", zap.String("group", *params.Group))
+			return nil, domain.ErrForbidden
+		}
+		window.Group = *params.Group
+	}
+
+	if err := a.repo.CreateWindow(ctx, window); err != nil {
+		logger.Error("failed to create maintenance window", zap.Error(err))
+		return nil, domain.ErrDatabase
+	}
+
+	logger.Info("Maintenance window created successfully", zap.Uint("window_id", window.ID))
+	return dto.ToMaintenanceWindowResponse(window), nil
+}
+
+func (a *alertUseCase) ListMaintenanceWindows(ctx context.Context, filter dto.MaintenanceWindowFilterOptions) ([]*dto.MaintenanceWindowResponse, error) {
+	windows, err := a.repo.ListWindows(ctx, filter.IncludeExpired)
+	if err != nil {
+		log.LoggerWithContext(ctx, a.logger).Error("failed to list maintenance windows", zap.Error(err))
+		return nil, domain.ErrDatabase
+	}
+	return dto.ToMaintenanceWindowsResponse(windows), nil
+}
+
+func (a *alertUseCase) DeleteMaintenanceWindow(ctx context.Context, id uint) error {
+	logger := log.LoggerWithContext(ctx, a.logger)
+	logger.Info("DeleteMaintenanceWindow called", zap.Uint("window_id", id))
+
+	if err := a.repo.DeleteWindow(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Warn("Maintenance window not found", zap.Uint("window_id", id))
+			return domain.ErrMaintenanceWindowNotFound
+		}
+		logger.Error("failed to delete maintenance window", zap.Uint("window_id", id), zap.Error(err))
+		return domain.ErrDatabase
+	}
+
+	logger.Info("Maintenance window deleted successfully", zap.Uint("window_id", id))
+	return nil
+}
+
+func (a *alertUseCase) StatusChanged(ctx context.Context, event dto.StatusChangedEvent) {
+	ctx = tenant.NewContext(context.WithoutCancel(ctx), event.TenantID)
+
+	if !a.evaluations.Submit(func() { a.evaluateTransition(ctx, event) }) {
+		log.LoggerWithContext(ctx, a.logger).Warn("Alerts draining, status change not evaluated", zap.String("server_id", event.ServerID))
+	}
+}
+
+func (a *alertUseCase) Drain(ctx context.Context) error {
+	return a.evaluations.Drain(ctx)
+}
+
+func (a *alertUseCase) evaluateTransition(ctx context.Context, event dto.StatusChangedEvent) {
+	logger := log.LoggerWithContext(ctx, a.logger)
+
+	err := a.repo.RecordTransition(ctx, &entity.StatusTransition{
+		ServerID:       event.ServerID,
+		PreviousStatus: event.Previous,
+		Status:         event.Current,
+		ChangedAt:      event.ChangedAt,
+	})
+	if err != nil {
+		logger.Error("failed to record status transition", zap.String("server_id", event.ServerID), zap.Error(err))
+	}
+
+	if event.Current != entity.ServerStatusOffline {
+		a.resolve(ctx, entity.AlertRuleOffline, event.ServerID, event.ChangedAt)
+	}
+	a.evaluateFlapping(ctx, event.ServerID, event.Group, event.ChangedAt)
+	if event.Group != "" {
+		a.evaluateGroup(ctx, event.Group, event.ChangedAt)
+	}
+}
+
+func (a *alertUseCase) Evaluate(ctx context.Context) error {
+	logger := log.LoggerWithContext(ctx, a.logger)
+	now := time.Now()
+
+	if a.config.OfflineAfter > 0 {
+		servers, err := a.servers.ListOfflineSince(ctx, now.Add(-a.config.OfflineAfter))
+		if err != nil {
+			logger.Error("failed to list offline servers", zap.Error(err))
+			return err
+		}
+		for _, server := range servers {
+			offline := now.Sub(*server.StatusChangedAt)
+			a.fire(tenant.NewContext(ctx, server.TenantID), &entity.Alert{
+				Rule:      entity.AlertRuleOffline,
+				Subject:   server.ServerID,
+				Message:   fmt.Sprintf("server %s has been offline for %s", server.ServerID, offline.Truncate(time.Second)),
+				Value:     offline.Minutes(),
+				StartedAt: now,
+			}, server.ServerID, server.Group)
+		}
+	}
+
+	firing, err := a.repo.ListFiring(ctx)
+	if err != nil {
+		logger.Error("failed to list firing alerts", zap.Error(err))
+		return err
+	}
+	for _, alert := range firing {
+		a.recheck(tenant.NewContext(ctx, alert.TenantID), alert, now)
+	}
+
+	if _, err := a.repo.PruneTransitions(ctx, now.Add(-TRANSITION_RETENTION)); err != nil {
+		logger.Error("failed to prune status transitions", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// recheck resolves a firing alert whose condition no longer holds, including
+// alerts of rules that have since been disabled.
+func (a *alertUseCase) recheck(ctx context.Context, alert *entity.Alert, now time.Time) {
+	switch alert.Rule {
+	case entity.AlertRuleOffline:
+		server, err := a.servers.GetByField(ctx, "server_id", alert.Subject)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.LoggerWithContext(ctx, a.logger).Error("failed to get server", zap.String("server_id", alert.Subject), zap.Error(err))
+			return
+		}
+		if a.config.OfflineAfter <= 0 || server == nil || server.Status != entity.ServerStatusOffline {
+			a.resolve(ctx, alert.Rule, alert.Subject, now)
+		}
+	case entity.AlertRuleFlapping:
+		a.evaluateFlapping(ctx, alert.Subject, "", now)
+	case entity.AlertRuleGroupOffline:
+		a.evaluateGroup(ctx, alert.Subject, now)
+	}
+}
+
+func (a *alertUseCase) evaluateFlapping(ctx context.Context, serverID, group string, now time.Time) {
+	if a.config.FlapThreshold <= 0 {
+		a.resolve(ctx, entity.AlertRuleFlapping, serverID, now)
+		return
+	}
+
+	count, err := a.repo.CountTransitions(ctx, serverID, now.Add(-a.config.FlapWindow))
+	if err != nil {
+		log.LoggerWithContext(ctx, a.logger).Error("failed to count status transitions", zap.String("server_id", serverID), zap.Error(err))
+		return
+	}
+
+	if count > int64(a.config.FlapThreshold) {
+		a.fire(ctx, &entity.Alert{
+			Rule:      entity.AlertRuleFlapping,
+			Subject:   serverID,
+			Message:   fmt.Sprintf("server %s changed status %d times in %s", serverID, count, a.config.FlapWindow),
+			Value:     float64(count),
+			StartedAt: now,
+		}, serverID, group)
+		return
+	}
+	a.resolve(ctx, entity.AlertRuleFlapping, serverID, now)
+}
+
+func (a *alertUseCase) evaluateGroup(ctx context.Context, group string, now time.Time) {
+	if a.config.GroupOfflinePercent <= 0 {
+		a.resolve(ctx, entity.AlertRuleGroupOffline, group, now)
+		return
+	}
+
+	counts, err := a.servers.CountGroupByStatus(ctx, group)
+	if err != nil {
+		log.LoggerWithContext(ctx, a.logger).Error("failed to count group servers", zap.String("group", group), zap.Error(err))
+		return
+	}
+
+	var total, offline int64
+	for _, count := range counts {
+		total += count.Count
+		if count.Status == entity.ServerStatusOffline {
+			offline += count.Count
+		}
+	}
+
+	if total > 0 {
+		percent := float64(offline) * 100 / float64(total)
+		if percent > a.config.GroupOfflinePercent {
+			a.fire(ctx, &entity.Alert{
+				Rule:      entity.AlertRuleGroupOffline,
+				Subject:   group,
+				Message:   fmt.Sprintf("%d of %d servers in group %s are offline", offline, total, group),
+				Value:     percent,
+				StartedAt: now,
+			}, "", group)
+			return
+		}
+	}
+	a.resolve(ctx, entity.AlertRuleGroupOffline, group, now)
+}
+
+// fire stores the alert unless a maintenance window covers the server or group.
+func (a *alertUseCase) fire(ctx context.Context, alert *entity.Alert, serverID, group string) {
+	logger := log.LoggerWithContext(ctx, a.logger).With(
+		zap.String("rule", string(alert.Rule)),
+		zap.String("subject", alert.Subject))
+
+	inMaintenance, err := a.repo.InMaintenance(ctx, serverID, group, alert.StartedAt)
+	if err != nil {
+		logger.Error("failed to check maintenance windows", zap.Error(err))
+		return
+	}
+	if inMaintenance {
+		logger.Debug("Alert suppressed by maintenance window")
+		return
+	}
+
+	fired, err := a.repo.Fire(ctx, alert)
+	if err != nil {
+		logger.Error("failed to fire alert", zap.Error(err))
+		return
+	}
+	if fired {
+		logger.Warn("Alert firing", zap.String("message", alert.Message))
+	}
+}
+
+func (a *alertUseCase) resolve(ctx context.Context, rule entity.AlertRule, subject string, at time.Time) {
+	logger := log.LoggerWithContext(ctx, a.logger).With(
+		zap.String("rule", string(rule)),
+		zap.String("subject", subject))
+
+	resolved, err := a.repo.Resolve(ctx, rule, subject, at)
+	if err != nil {
+		logger.Error("failed to resolve alert", zap.Error(err))
+		return
+	}
+	if resolved {
+		logger.Info("Alert resolved")
+	}
+}
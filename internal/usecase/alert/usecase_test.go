@@ -0,0 +1,304 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/authz"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	repoiface "github.com/th1enq/ViettelSMS_ServerService/internal/domain/repository"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// mockAlertRepo keeps firing alerts by rule and subject; every tenant shares it.
+type mockAlertRepo struct {
+	repoiface.AlertRepository
+	transitions   []*entity.StatusTransition
+	firing        map[entity.AlertRule]map[string]*entity.Alert
+	windows       []*entity.MaintenanceWindow
+	inMaintenance bool
+}
+
+func newMockAlertRepo() *mockAlertRepo {
+	return &mockAlertRepo{firing: make(map[entity.AlertRule]map[string]*entity.Alert)}
+}
+
+func (m *mockAlertRepo) RecordTransition(ctx context.Context, transition *entity.StatusTransition) error {
+	m.transitions = append(m.transitions, transition)
+	return nil
+}
+func (m *mockAlertRepo) CountTransitions(ctx context.Context, serverID string, since time.Time) (int64, error) {
+	var count int64
+	for _, transition := range m.transitions {
+		if transition.ServerID == serverID && !transition.ChangedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+func (m *mockAlertRepo) PruneTransitions(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+func (m *mockAlertRepo) Fire(ctx context.Context, alert *entity.Alert) (bool, error) {
+	if m.firing[alert.Rule] == nil {
+		m.firing[alert.Rule] = make(map[string]*entity.Alert)
+	}
+	if _, ok := m.firing[alert.Rule][alert.Subject]; ok {
+		return false, nil
+	}
+	alert.State = entity.AlertStateFiring
+	m.firing[alert.Rule][alert.Subject] = alert
+	return true, nil
+}
+func (m *mockAlertRepo) Resolve(ctx context.Context, rule entity.AlertRule, subject string, at time.Time) (bool, error) {
+	if _, ok := m.firing[rule][subject]; !ok {
+		return false, nil
+	}
+	delete(m.firing[rule], subject)
+	return true, nil
+}
+func (m *mockAlertRepo) ListFiring(ctx context.Context) ([]*entity.Alert, error) {
+	var alerts []*entity.Alert
+	for _, subjects := range m.firing {
+		for _, alert := range subjects {
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts, nil
+}
+func (m *mockAlertRepo) CreateWindow(ctx context.Context, window *entity.MaintenanceWindow) error {
+	m.windows = append(m.windows, window)
+	return nil
+}
+func (m *mockAlertRepo) InMaintenance(ctx context.Context, serverID, group string, at time.Time) (bool, error) {
+	return m.inMaintenance, nil
+}
+
+func (m *mockAlertRepo) isFiring(rule entity.AlertRule, subject string) bool {
+	_, ok := m.firing[rule][subject]
+	return ok
+}
+
+type mockServers struct {
+	repoiface.ServerRepository
+	servers map[string]*entity.Server
+}
+
+func (m *mockServers) GetByField(ctx context.Context, field string, value interface{}) (*entity.Server, error) {
+	if server, ok := m.servers[value.(string)]; ok {
+		return server, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+func (m *mockServers) ListOfflineSince(ctx context.Context, before time.Time) ([]*entity.Server, error) {
+	var servers []*entity.Server
+	for _, server := range m.servers {
+		if server.Status == entity.ServerStatusOffline && server.StatusChangedAt.Before(before) {
+			servers = append(servers, server)
+		}
+	}
+	return servers, nil
+}
+func (m *mockServers) CountGroupByStatus(ctx context.Context, group string) ([]dto.StatusCount, error) {
+	counts := make(map[entity.ServerStatus]int64)
+	for _, server := range m.servers {
+		if server.Group == group {
+			counts[server.Status]++
+		}
+	}
+	var result []dto.StatusCount
+	for status, count := range counts {
+		result = append(result, dto.StatusCount{Status: status, Count: count})
+	}
+	return result, nil
+}
+
+func newUseCase(r repoiface.AlertRepository, servers repoiface.ServerRepository) UseCase {
+	return NewAlertUseCase(r, servers, &config.Config{
+		Alert: config.Alert{
+			OfflineAfter:        5 * time.Minute,
+			GroupOfflinePercent: 50,
+			FlapThreshold:       3,
+			FlapWindow:          time.Hour,
+		},
+	}, zap.NewNop())
+}
+
+func transition(serverID, group string, previous, current entity.ServerStatus, at time.Time) dto.StatusChangedEvent {
+	return dto.StatusChangedEvent{
+		TenantID:  "acme",
+		ServerID:  serverID,
+		Group:     group,
+		Previous:  previous,
+		Current:   current,
+		ChangedAt: at,
+	}
+}
+
+// evaluate runs the evaluation StatusChanged queues, synchronously.
+func evaluate(uc UseCase, event dto.StatusChangedEvent) {
+	uc.(*alertUseCase).evaluateTransition(tenant.NewContext(context.Background(), event.TenantID), event)
+}
+
+func TestStatusChanged_EvaluatesInBackground(t *testing.T) {
+	r := newMockAlertRepo()
+	uc := newUseCase(r, &mockServers{})
+
+	uc.StatusChanged(context.Background(), transition("s1", "", entity.ServerStatusOnline, entity.ServerStatusOffline, time.Now()))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := uc.Drain(ctx); err != nil {
+		t.Fatalf("drain: %v", err)
+	}
+	if len(r.transitions) != 1 || r.transitions[0].ServerID != "s1" {
+		t.Fatalf("expected the queued transition recorded, got %+v", r.transitions)
+	}
+
+	// Events arriving after Drain are dropped.
+	uc.StatusChanged(context.Background(), transition("s1", "", entity.ServerStatusOffline, entity.ServerStatusOnline, time.Now()))
+	if len(r.transitions) != 1 {
+		t.Fatalf("expected no transition after drain, got %d", len(r.transitions))
+	}
+}
+
+func TestStatusChanged_Flapping(t *testing.T) {
+	r := newMockAlertRepo()
+	uc := newUseCase(r, &mockServers{})
+	now := time.Now()
+
+	statuses := []entity.ServerStatus{entity.ServerStatusOffline, entity.ServerStatusOnline}
+	for i := 0; i < 4; i++ {
+		evaluate(uc, transition("s1", "", statuses[(i+1)%2], statuses[i%2], now.Add(time.Duration(i)*time.Minute)))
+		if firing := r.isFiring(entity.AlertRuleFlapping, "s1"); firing != (i == 3) {
+			t.Fatalf("after %d transitions: firing=%v", i+1, firing)
+		}
+	}
+
+	// An hour later the transitions have aged out of the window.
+	evaluate(uc, transition("s1", "", entity.ServerStatusOnline, entity.ServerStatusOffline, now.Add(2*time.Hour)))
+	if r.isFiring(entity.AlertRuleFlapping, "s1") {
+		t.Fatalf("expected flapping alert resolved")
+	}
+}
+
+func TestStatusChanged_GroupOffline(t *testing.T) {
+	r := newMockAlertRepo()
+	servers := &mockServers{servers: map[string]*entity.Server{
+		"s1": {ServerID: "s1", Group: "web", Status: entity.ServerStatusOffline},
+		"s2": {ServerID: "s2", Group: "web", Status: entity.ServerStatusOnline},
+		"s3": {ServerID: "s3", Group: "web", Status: entity.ServerStatusOnline},
+	}}
+	uc := newUseCase(r, servers)
+
+	evaluate(uc, transition("s1", "web", entity.ServerStatusOnline, entity.ServerStatusOffline, time.Now()))
+	if r.isFiring(entity.AlertRuleGroupOffline, "web") {
+		t.Fatalf("1 of 3 offline must not fire")
+	}
+
+	servers.servers["s2"].Status = entity.ServerStatusOffline
+	evaluate(uc, transition("s2", "web", entity.ServerStatusOnline, entity.ServerStatusOffline, time.Now()))
+	if !r.isFiring(entity.AlertRuleGroupOffline, "web") {
+		t.Fatalf("2 of 3 offline must fire")
+	}
+
+	servers.servers["s1"].Status = entity.ServerStatusOnline
+	evaluate(uc, transition("s1", "web", entity.ServerStatusOffline, entity.ServerStatusOnline, time.Now()))
+	if r.isFiring(entity.AlertRuleGroupOffline, "web") {
+		t.Fatalf("expected group alert resolved")
+	}
+}
+
+func TestEvaluate_OfflineForTooLong(t *testing.T) {
+	r := newMockAlertRepo()
+	offlineSince := time.Now().Add(-10 * time.Minute)
+	recently := time.Now().Add(-time.Minute)
+	servers := &mockServers{servers: map[string]*entity.Server{
+		"s1": {TenantID: "acme", ServerID: "s1", Status: entity.ServerStatusOffline, StatusChangedAt: &offlineSince},
+		"s2": {TenantID: "acme", ServerID: "s2", Status: entity.ServerStatusOffline, StatusChangedAt: &recently},
+	}}
+	uc := newUseCase(r, servers)
+
+	if err := uc.Evaluate(context.Background()); err != nil {
+		t.Fatalf("unexpected: %v", err)
+	}
+	if !r.isFiring(entity.AlertRuleOffline, "s1") || r.isFiring(entity.AlertRuleOffline, "s2") {
+		t.Fatalf("unexpected firing alerts %+v", r.firing)
+	}
+
+	// Back online without a transition reaching us: the next evaluation resolves it.
+	servers.servers["s1"].Status = entity.ServerStatusOnline
+	if err := uc.Evaluate(context.Background()); err != nil {
+		t.Fatalf("unexpected: %v", err)
+	}
+	if r.isFiring(entity.AlertRuleOffline, "s1") {
+		t.Fatalf("expected offline alert resolved")
+	}
+}
+
+func TestMaintenanceWindow_SuppressesAlerts(t *testing.T) {
+	r := newMockAlertRepo()
+	r.inMaintenance = true
+	offlineSince := time.Now().Add(-time.Hour)
+	servers := &mockServers{servers: map[string]*entity.Server{
+		"s1": {TenantID: "acme", ServerID: "s1", Group: "web", Status: entity.ServerStatusOffline, StatusChangedAt: &offlineSince},
+	}}
+	uc := newUseCase(r, servers)
+
+	evaluate(uc, transition("s1", "web", entity.ServerStatusOnline, entity.ServerStatusOffline, time.Now()))
+	if err := uc.Evaluate(context.Background()); err != nil {
+		t.Fatalf("unexpected: %v", err)
+	}
+	if alerts, _ := r.ListFiring(context.Background()); len(alerts) != 0 {
+		t.Fatalf("expected no alerts during maintenance, got %d", len(alerts))
+	}
+}
+
+func TestCreateMaintenanceWindow_RespectsConstraints(t *testing.T) {
+	r := newMockAlertRepo()
+	servers := &mockServers{servers: map[string]*entity.Server{
+		"hn-1":  {TenantID: "acme", ServerID: "hn-1", Location: "HN", Group: "web"},
+		"hcm-1": {TenantID: "acme", ServerID: "hcm-1", Location: "HCM", Group: "web"},
+	}}
+	uc := newUseCase(r, servers)
+	starts := time.Now()
+	window := func(serverID, group string) dto.CreateMaintenanceWindowParams {
+		params := dto.CreateMaintenanceWindowParams{StartsAt: starts, EndsAt: starts.Add(time.Hour)}
+		if serverID != "" {
+			params.ServerID = &serverID
+		}
+		if group != "" {
+			params.Group = &group
+		}
+		return params
+	}
+
+	hanoi := authz.NewContext(context.Background(), &authz.Principal{Constraints: authz.Constraints{authz.ConstraintLocation: {"HN"}}})
+	if _, err := uc.CreateMaintenanceWindow(hanoi, window("hn-1", "")); err != nil {
+		t.Fatalf("window for a visible server: %v", err)
+	}
+	if _, err := uc.CreateMaintenanceWindow(hanoi, window("hcm-1", "")); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("window for a hidden server: got %v, want %v", err, domain.ErrForbidden)
+	}
+	if _, err := uc.CreateMaintenanceWindow(hanoi, window("missing", "")); !errors.Is(err, domain.ErrServerNotFound) {
+		t.Fatalf("window for a missing server: got %v, want %v", err, domain.ErrServerNotFound)
+	}
+	// The group also holds servers outside Hanoi.
+	if _, err := uc.CreateMaintenanceWindow(hanoi, window("", "web")); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("window for a group: got %v, want %v", err, domain.ErrForbidden)
+	}
+
+	web := authz.NewContext(context.Background(), &authz.Principal{Constraints: authz.Constraints{authz.ConstraintGroup: {"web"}}})
+	if _, err := uc.CreateMaintenanceWindow(web, window("", "web")); err != nil {
+		t.Fatalf("window for a permitted group: %v", err)
+	}
+	if len(r.windows) != 2 {
+		t.Fatalf("got %d windows stored, want 2", len(r.windows))
+	}
+}
@@ -0,0 +1,22 @@
+package idempotency
+
+import (
+	"context"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+)
+
+type UseCase interface {
+	// Begin claims the key for a request with the given hash. It returns nil
+	// when the request should run, the stored response when the same request
+	// already completed, ErrIdempotencyKeyReused when the key was used with a
+	// different request and ErrIdempotencyKeyInProgress while the first
+	// request is still running.
+	Begin(ctx context.Context, key, requestHash string) (*entity.IdempotencyKey, error)
+	// Complete stores the response replayed for later requests with the key.
+	Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error
+	// Release gives the key up after a failed request, so it can be retried.
+	Release(ctx context.Context, key string) error
+	// PurgeExpired removes the keys whose TTL has passed.
+	PurgeExpired(ctx context.Context) (int64, error)
+}
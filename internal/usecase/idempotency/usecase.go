@@ -0,0 +1,102 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	repo "github.com/th1enq/ViettelSMS_ServerService/internal/domain/repository"
+	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	"go.uber.org/zap"
+)
+
+type idempotencyUseCase struct {
+	repo        repo.IdempotencyRepository
+	ttl         time.Duration
+	lockTimeout time.Duration
+	logger      *zap.Logger
+}
+
+func NewIdempotencyUseCase(
+	repo repo.IdempotencyRepository,
+	config *config.Config,
+	logger *zap.Logger,
+) UseCase {
+	return &idempotencyUseCase{
+		repo:        repo,
+		ttl:         config.Idempotency.TTL,
+		lockTimeout: config.Idempotency.LockTimeout,
+		logger:      logger,
+	}
+}
+
+func (u *idempotencyUseCase) Begin(ctx context.Context, key, requestHash string) (*entity.IdempotencyKey, error) {
+	logger := log.LoggerWithContext(ctx, u.logger).With(zap.String("idempotency_key", key))
+
+	now := time.Now()
+	stored, created, err := u.repo.Reserve(ctx, &entity.IdempotencyKey{
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+		LockedUntil: now.Add(u.lockTimeout),
+		ExpiresAt:   now.Add(u.ttl),
+	})
+	if err != nil {
+		logger.Error("failed to reserve idempotency key", zap.Error(err))
+		return nil, domain.ErrDatabase
+	}
+
+	switch {
+	case created:
+		return nil, nil
+	case stored.RequestHash != requestHash:
+		logger.Warn("Idempotency key reused with a different request")
+		return nil, domain.ErrIdempotencyKeyReused
+	case !stored.Completed():
+		logger.Warn("Idempotency key is still in progress")
+		return nil, domain.ErrIdempotencyKeyInProgress.WithRetryAfter(time.Until(stored.LockedUntil))
+	}
+
+	logger.Info("Replaying stored response", zap.Int("status_code", stored.StatusCode))
+	return stored, nil
+}
+
+func (u *idempotencyUseCase) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	err := u.repo.Complete(ctx, &entity.IdempotencyKey{
+		Key:         key,
+		StatusCode:  statusCode,
+		ContentType: contentType,
+		Body:        body,
+	})
+	if err != nil {
+		log.LoggerWithContext(ctx, u.logger).Error("failed to store idempotent response",
+			zap.String("idempotency_key", key), zap.Error(err))
+		return domain.ErrDatabase
+	}
+	return nil
+}
+
+func (u *idempotencyUseCase) Release(ctx context.Context, key string) error {
+	if err := u.repo.Release(ctx, key); err != nil {
+		log.LoggerWithContext(ctx, u.logger).Error("failed to release idempotency key",
+			zap.String("idempotency_key", key), zap.Error(err))
+		return domain.ErrDatabase
+	}
+	return nil
+}
+
+func (u *idempotencyUseCase) PurgeExpired(ctx context.Context) (int64, error) {
+	logger := log.LoggerWithContext(ctx, u.logger)
+
+	purged, err := u.repo.PurgeExpired(ctx, time.Now())
+	if err != nil {
+		logger.Error("failed to purge expired idempotency keys", zap.Error(err))
+		return 0, domain.ErrDatabase
+	}
+	if purged > 0 {
+		logger.Info("Expired idempotency keys purged", zap.Int64("purged", purged))
+	}
+	return purged, nil
+}
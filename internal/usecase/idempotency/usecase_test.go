@@ -0,0 +1,154 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	"go.uber.org/zap"
+)
+
+// memoryRepo keeps keys per tenant the way the table's primary key does.
+type memoryRepo struct {
+	keys map[string]*entity.IdempotencyKey
+	err  error
+}
+
+func (m *memoryRepo) id(ctx context.Context, key string) string {
+	return tenant.FromContext(ctx) + "/" + key
+}
+
+func (m *memoryRepo) Reserve(ctx context.Context, key *entity.IdempotencyKey) (*entity.IdempotencyKey, bool, error) {
+	if m.err != nil {
+		return nil, false, m.err
+	}
+	now := time.Now()
+	if stored, ok := m.keys[m.id(ctx, key.Key)]; ok && stored.ExpiresAt.After(now) && (stored.Completed() || stored.LockedUntil.After(now)) {
+		copied := *stored
+		return &copied, false, nil
+	}
+	copied := *key
+	m.keys[m.id(ctx, key.Key)] = &copied
+	return key, true, nil
+}
+
+func (m *memoryRepo) Complete(ctx context.Context, key *entity.IdempotencyKey) error {
+	stored := m.keys[m.id(ctx, key.Key)]
+	stored.StatusCode, stored.ContentType, stored.Body = key.StatusCode, key.ContentType, key.Body
+	return nil
+}
+
+func (m *memoryRepo) Release(ctx context.Context, key string) error {
+	if stored, ok := m.keys[m.id(ctx, key)]; ok && !stored.Completed() {
+		delete(m.keys, m.id(ctx, key))
+	}
+	return nil
+}
+
+func (m *memoryRepo) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	for id, stored := range m.keys {
+		if stored.ExpiresAt.Before(before) {
+			delete(m.keys, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func newUseCase(r *memoryRepo, ttl time.Duration) UseCase {
+	return newLockingUseCase(r, ttl, time.Minute)
+}
+
+func newLockingUseCase(r *memoryRepo, ttl, lockTimeout time.Duration) UseCase {
+	return NewIdempotencyUseCase(r, &config.Config{Idempotency: config.Idempotency{TTL: ttl, LockTimeout: lockTimeout}}, zap.NewNop())
+}
+
+func TestBegin_ReplaysCompletedRequest(t *testing.T) {
+	u := newUseCase(&memoryRepo{keys: map[string]*entity.IdempotencyKey{}}, time.Hour)
+	ctx := tenant.NewContext(context.Background(), "acme")
+
+	if stored, err := u.Begin(ctx, "k1", "hash"); err != nil || stored != nil {
+		t.Fatalf("first request should run, got %v %v", stored, err)
+	}
+	if _, err := u.Begin(ctx, "k1", "hash"); !errors.Is(err, domain.ErrIdempotencyKeyInProgress) {
+		t.Fatalf("want in progress, got %v", err)
+	}
+	if err := u.Complete(ctx, "k1", http.StatusCreated, "application/json", []byte(`{"ok":true}`)); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+
+	stored, err := u.Begin(ctx, "k1", "hash")
+	if err != nil || stored == nil || stored.StatusCode != http.StatusCreated || string(stored.Body) != `{"ok":true}` {
+		t.Fatalf("want stored response, got %+v %v", stored, err)
+	}
+	if _, err := u.Begin(ctx, "k1", "other"); !errors.Is(err, domain.ErrIdempotencyKeyReused) {
+		t.Fatalf("want reused, got %v", err)
+	}
+	if stored, err := u.Begin(tenant.NewContext(context.Background(), "globex"), "k1", "other"); err != nil || stored != nil {
+		t.Fatalf("keys must not cross tenants, got %v %v", stored, err)
+	}
+}
+
+func TestRelease_AllowsRetry(t *testing.T) {
+	u := newUseCase(&memoryRepo{keys: map[string]*entity.IdempotencyKey{}}, time.Hour)
+	ctx := context.Background()
+
+	if _, err := u.Begin(ctx, "k1", "hash"); err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if err := u.Release(ctx, "k1"); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if stored, err := u.Begin(ctx, "k1", "other"); err != nil || stored != nil {
+		t.Fatalf("released key should be free, got %v %v", stored, err)
+	}
+}
+
+func TestBegin_ReclaimsExpiredLock(t *testing.T) {
+	r := &memoryRepo{keys: map[string]*entity.IdempotencyKey{}}
+	ctx := context.Background()
+
+	// The first request died without completing or releasing its key.
+	if _, err := newLockingUseCase(r, time.Hour, -time.Second).Begin(ctx, "k1", "hash"); err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	u := newUseCase(r, time.Hour)
+	if stored, err := u.Begin(ctx, "k1", "hash"); err != nil || stored != nil {
+		t.Fatalf("retry should take over the key, got %v %v", stored, err)
+	}
+
+	_, err := u.Begin(ctx, "k1", "hash")
+	var inProgress *domain.Error
+	if !errors.As(err, &inProgress) || !errors.Is(err, domain.ErrIdempotencyKeyInProgress) || inProgress.RetryAfter <= 0 {
+		t.Fatalf("want in progress with a retry delay, got %v", err)
+	}
+}
+
+func TestPurgeExpired(t *testing.T) {
+	r := &memoryRepo{keys: map[string]*entity.IdempotencyKey{}}
+	ctx := context.Background()
+
+	if _, err := newUseCase(r, -time.Minute).Begin(ctx, "old", "hash"); err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if _, err := newUseCase(r, time.Hour).Begin(ctx, "new", "hash"); err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if purged, err := newUseCase(r, time.Hour).PurgeExpired(ctx); err != nil || purged != 1 || len(r.keys) != 1 {
+		t.Fatalf("want the expired key purged, got %d %v", purged, err)
+	}
+}
+
+func TestBegin_RepoError(t *testing.T) {
+	u := newUseCase(&memoryRepo{err: errors.New("boom")}, time.Hour)
+	if _, err := u.Begin(context.Background(), "k1", "hash"); !errors.Is(err, domain.ErrDatabase) {
+		t.Fatalf("want database error, got %v", err)
+	}
+}
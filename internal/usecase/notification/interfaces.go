@@ -0,0 +1,16 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+)
+
+type UseCase interface {
+	// StatusChanged queues a message for every route matching the event and
+	// returns immediately.
+	StatusChanged(ctx context.Context, event dto.StatusChangedEvent)
+	// Drain stops accepting events and waits for queued messages until ctx
+	// expires.
+	Drain(ctx context.Context) error
+}
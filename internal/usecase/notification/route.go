@@ -0,0 +1,77 @@
+package notification
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+)
+
+const (
+	CHANNEL_EMAIL = "email"
+	CHANNEL_SMS   = "sms"
+)
+
+// Route sends the transitions of matching servers to recipients over one
+// channel. Empty filters match every server, except Tenants: a route without
+// tenants only sees the default tenant, so recipients never receive another
+// tenant's servers by omission.
+type Route struct {
+	Name       string                `json:"name"`
+	Channel    string                `json:"channel"`
+	Recipients []string              `json:"recipients"`
+	Tenants    []string              `json:"tenants"`
+	Labels     entity.Labels         `json:"labels"`
+	Locations  []string              `json:"locations"`
+	Statuses   []entity.ServerStatus `json:"statuses"`
+}
+
+// Matches reports whether the server belongs to one of the route's tenants,
+// carries all its labels, is in one of its locations and moved to one of its
+// statuses.
+func (r Route) Matches(server *entity.Server) bool {
+	tenants := r.Tenants
+	if len(tenants) == 0 {
+		tenants = []string{tenant.Default}
+	}
+	serverTenant := server.TenantID
+	if serverTenant == "" {
+		serverTenant = tenant.Default
+	}
+	if !slices.Contains(tenants, serverTenant) {
+		return false
+	}
+	for key, value := range r.Labels {
+		if server.Labels[key] != value {
+			return false
+		}
+	}
+	if len(r.Locations) > 0 && !slices.Contains(r.Locations, server.Location) {
+		return false
+	}
+	if len(r.Statuses) > 0 && !slices.Contains(r.Statuses, server.Status) {
+		return false
+	}
+	return true
+}
+
+func parseRoutes(raw string) ([]Route, error) {
+	var routes []Route
+	if raw == "" {
+		return routes, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &routes); err != nil {
+		return nil, fmt.Errorf("invalid notification routes: %w", err)
+	}
+	for i, route := range routes {
+		if route.Name == "" {
+			return nil, fmt.Errorf("notification route %d has no name", i)
+		}
+		if len(route.Recipients) == 0 {
+			return nil, fmt.Errorf("notification route %s has no recipients", route.Name)
+		}
+	}
+	return routes, nil
+}
@@ -0,0 +1,204 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	repo "github.com/th1enq/ViettelSMS_ServerService/internal/domain/repository"
+	srv "github.com/th1enq/ViettelSMS_ServerService/internal/domain/service"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/queue"
+	"go.uber.org/zap"
+)
+
+type notificationUseCase struct {
+	routes   []Route
+	channels map[string]srv.NotificationChannel
+	// windows suppresses notifications for servers under maintenance.
+	windows repo.AlertRepository
+	// templates holds the subject and body template of each channel; a nil
+	// subject template renders an empty subject.
+	templates map[string]messageTemplate
+	config    config.Notification
+	logger    *zap.Logger
+
+	// sent remembers when each route last notified a server reaching a status.
+	sentMu sync.Mutex
+	sent   map[string]time.Time
+
+	messages *queue.Queue
+}
+
+type messageTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+// NewNotificationUseCase validates the configured routes against the
+// available channels, keyed by CHANNEL_EMAIL and CHANNEL_SMS.
+func NewNotificationUseCase(
+	config *config.Config,
+	channels map[string]srv.NotificationChannel,
+	windows repo.AlertRepository,
+	logger *zap.Logger,
+) (UseCase, error) {
+	routes, err := parseRoutes(config.Notification.Routes)
+	if err != nil {
+		return nil, err
+	}
+	for _, route := range routes {
+		if _, ok := channels[route.Channel]; !ok {
+			return nil, fmt.Errorf("notification route %s uses unconfigured channel %q", route.Name, route.Channel)
+		}
+	}
+
+	templates := make(map[string]messageTemplate)
+	email := messageTemplate{}
+	if email.subject, err = template.New("email_subject").Parse(config.Notification.EmailSubjectTemplate); err != nil {
+		return nil, fmt.Errorf("invalid email subject template: %w", err)
+	}
+	if email.body, err = template.New("email_body").Parse(config.Notification.EmailBodyTemplate); err != nil {
+		return nil, fmt.Errorf("invalid email body template: %w", err)
+	}
+	templates[CHANNEL_EMAIL] = email
+	sms := messageTemplate{}
+	if sms.body, err = template.New("sms").Parse(config.Notification.SMSTemplate); err != nil {
+		return nil, fmt.Errorf("invalid sms template: %w", err)
+	}
+	templates[CHANNEL_SMS] = sms
+
+	return &notificationUseCase{
+		routes:    routes,
+		channels:  channels,
+		windows:   windows,
+		templates: templates,
+		config:    config.Notification,
+		logger:    logger,
+		sent:      make(map[string]time.Time),
+		messages:  queue.New(config.Notification.Workers),
+	}, nil
+}
+
+func (u *notificationUseCase) StatusChanged(ctx context.Context, event dto.StatusChangedEvent) {
+	ctx = tenant.NewContext(context.WithoutCancel(ctx), event.TenantID)
+	logger := log.LoggerWithContext(ctx, u.logger)
+
+	server := event.Server()
+	for _, route := range u.routes {
+		if !route.Matches(server) {
+			continue
+		}
+		key, ok := u.claim(route, event)
+		if !ok {
+			logger.Debug("Duplicate notification suppressed",
+				zap.String("route", route.Name), zap.String("server_id", event.ServerID))
+			continue
+		}
+
+		queued := u.messages.Submit(func() {
+			if u.inMaintenance(ctx, event) {
+				// Nothing was sent, so nothing to deduplicate later.
+				u.forget(key)
+				return
+			}
+			if !u.send(ctx, route, event) {
+				// Let the next transition try again.
+				u.forget(key)
+			}
+		})
+		if !queued {
+			u.forget(key)
+			logger.Warn("Notifications draining, status change not sent", zap.String("server_id", event.ServerID))
+			return
+		}
+	}
+}
+
+func (u *notificationUseCase) Drain(ctx context.Context) error {
+	return u.messages.Drain(ctx)
+}
+
+// claim records that the route is about to notify the event, unless it
+// already did so for the same server and status within DedupeWindow.
+func (u *notificationUseCase) claim(route Route, event dto.StatusChangedEvent) (string, bool) {
+	key := strings.Join([]string{route.Name, event.TenantID, event.ServerID, string(event.Current)}, "/")
+	now := time.Now()
+
+	u.sentMu.Lock()
+	defer u.sentMu.Unlock()
+
+	for k, at := range u.sent {
+		if now.Sub(at) >= u.config.DedupeWindow {
+			delete(u.sent, k)
+		}
+	}
+	if _, ok := u.sent[key]; ok {
+		return key, false
+	}
+	u.sent[key] = now
+	return key, true
+}
+
+func (u *notificationUseCase) forget(key string) {
+	u.sentMu.Lock()
+	defer u.sentMu.Unlock()
+	delete(u.sent, key)
+}
+
+// inMaintenance reports whether a maintenance window covers the server or its
+// group. A failed check is logged and the notification sent anyway.
+func (u *notificationUseCase) inMaintenance(ctx context.Context, event dto.StatusChangedEvent) bool {
+	inMaintenance, err := u.windows.InMaintenance(ctx, event.ServerID, event.Group, event.ChangedAt)
+	if err != nil {
+		log.LoggerWithContext(ctx, u.logger).Error("failed to check maintenance windows",
+			zap.String("server_id", event.ServerID), zap.Error(err))
+		return false
+	}
+	if inMaintenance {
+		log.LoggerWithContext(ctx, u.logger).Debug("Notification suppressed by maintenance window",
+			zap.String("server_id", event.ServerID))
+	}
+	return inMaintenance
+}
+
+func (u *notificationUseCase) send(ctx context.Context, route Route, event dto.StatusChangedEvent) bool {
+	logger := log.LoggerWithContext(ctx, u.logger).With(
+		zap.String("route", route.Name),
+		zap.String("channel", route.Channel),
+		zap.String("server_id", event.ServerID))
+
+	subject, body, err := u.render(route.Channel, event)
+	if err != nil {
+		logger.Error("failed to render notification", zap.Error(err))
+		return false
+	}
+
+	if err := u.channels[route.Channel].Send(ctx, route.Recipients, subject, body); err != nil {
+		logger.Error("failed to send notification", zap.Error(err))
+		return false
+	}
+	logger.Info("Notification sent", zap.Int("recipients", len(route.Recipients)))
+	return true
+}
+
+func (u *notificationUseCase) render(channel string, event dto.StatusChangedEvent) (string, string, error) {
+	tmpl := u.templates[channel]
+
+	var subject, body strings.Builder
+	if tmpl.subject != nil {
+		if err := tmpl.subject.Execute(&subject, event); err != nil {
+			return "", "", err
+		}
+	}
+	if err := tmpl.body.Execute(&body, event); err != nil {
+		return "", "", err
+	}
+	return subject.String(), body.String(), nil
+}
@@ -0,0 +1,220 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	repoiface "github.com/th1enq/ViettelSMS_ServerService/internal/domain/repository"
+	srv "github.com/th1enq/ViettelSMS_ServerService/internal/domain/service"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	"go.uber.org/zap"
+)
+
+type sentMessage struct {
+	recipients []string
+	subject    string
+	body       string
+}
+
+type recordingChannel struct {
+	mu   sync.Mutex
+	sent []sentMessage
+	err  error
+}
+
+func (c *recordingChannel) Send(ctx context.Context, recipients []string, subject, body string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent = append(c.sent, sentMessage{recipients: recipients, subject: subject, body: body})
+	return c.err
+}
+
+func (c *recordingChannel) messages() []sentMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]sentMessage(nil), c.sent...)
+}
+
+// windowRepo reports the servers listed in maintenance as covered by a window.
+type windowRepo struct {
+	repoiface.AlertRepository
+	maintenance map[string]bool
+	err         error
+}
+
+func (w *windowRepo) InMaintenance(ctx context.Context, serverID, group string, at time.Time) (bool, error) {
+	return w.maintenance[tenant.FromContext(ctx)+"/"+serverID], w.err
+}
+
+const testRoutes = `[
+	{"name": "sms-team", "channel": "sms", "recipients": ["+84900000000"], "labels": {"team": "sms"}, "statuses": ["OFFLINE"]},
+	{"name": "hanoi", "channel": "email", "recipients": ["hanoi@example.com"], "locations": ["Hanoi"]},
+	{"name": "acme", "channel": "email", "recipients": ["ops@acme.example"], "tenants": ["acme"], "statuses": ["OFFLINE"]}
+]`
+
+func newUseCase(t *testing.T, email, sms srv.NotificationChannel) UseCase {
+	t.Helper()
+	return newUseCaseWithWindows(t, email, sms, &windowRepo{})
+}
+
+func newUseCaseWithWindows(t *testing.T, email, sms srv.NotificationChannel, windows repoiface.AlertRepository) UseCase {
+	t.Helper()
+	u, err := NewNotificationUseCase(&config.Config{
+		Notification: config.Notification{
+			Routes:               testRoutes,
+			DedupeWindow:         time.Hour,
+			Workers:              2,
+			EmailSubjectTemplate: "[{{.Current}}] {{.ServerName}}",
+			EmailBodyTemplate:    "{{.ServerName}} ({{.IPv4}}) {{.Previous}} -> {{.Current}}",
+			SMSTemplate:          "{{.ServerName}} {{.Current}}",
+		},
+	}, map[string]srv.NotificationChannel{CHANNEL_EMAIL: email, CHANNEL_SMS: sms}, windows, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewNotificationUseCase() error = %v", err)
+	}
+	return u
+}
+
+func event(serverID, location string, labels entity.Labels, previous, current entity.ServerStatus) dto.StatusChangedEvent {
+	return dto.StatusChangedEvent{
+		TenantID:   "default",
+		ServerID:   serverID,
+		ServerName: serverID + "-name",
+		IPv4:       "10.0.0.1",
+		Location:   location,
+		Labels:     labels,
+		Previous:   previous,
+		Current:    current,
+		ChangedAt:  time.Now(),
+	}
+}
+
+func drain(t *testing.T, u UseCase) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := u.Drain(ctx); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+}
+
+func TestStatusChanged_RoutesByLabelsAndLocation(t *testing.T) {
+	email, sms := &recordingChannel{}, &recordingChannel{}
+	u := newUseCase(t, email, sms)
+
+	u.StatusChanged(context.Background(), event("srv-1", "Hanoi", entity.Labels{"team": "sms"}, entity.ServerStatusOnline, entity.ServerStatusOffline))
+	u.StatusChanged(context.Background(), event("srv-2", "HCM", entity.Labels{"team": "sms"}, entity.ServerStatusOffline, entity.ServerStatusOnline))
+	u.StatusChanged(context.Background(), event("srv-3", "HCM", entity.Labels{"team": "web"}, entity.ServerStatusOnline, entity.ServerStatusOffline))
+	drain(t, u)
+
+	smsSent := sms.messages()
+	if len(smsSent) != 1 || smsSent[0].body != "srv-1-name OFFLINE" || smsSent[0].recipients[0] != "+84900000000" {
+		t.Errorf("sms messages = %+v, want only srv-1 going offline", smsSent)
+	}
+	emailSent := email.messages()
+	if len(emailSent) != 1 {
+		t.Fatalf("email messages = %+v, want only the Hanoi server", emailSent)
+	}
+	if emailSent[0].subject != "[OFFLINE] srv-1-name" || emailSent[0].body != "srv-1-name (10.0.0.1) ONLINE -> OFFLINE" {
+		t.Errorf("email message = %+v", emailSent[0])
+	}
+}
+
+func TestStatusChanged_Deduplicates(t *testing.T) {
+	email, sms := &recordingChannel{}, &recordingChannel{}
+	u := newUseCase(t, email, sms)
+
+	labels := entity.Labels{"team": "sms"}
+	u.StatusChanged(context.Background(), event("srv-1", "HCM", labels, entity.ServerStatusOnline, entity.ServerStatusOffline))
+	u.StatusChanged(context.Background(), event("srv-1", "HCM", labels, entity.ServerStatusOnline, entity.ServerStatusOffline))
+	u.StatusChanged(context.Background(), event("srv-2", "HCM", labels, entity.ServerStatusOnline, entity.ServerStatusOffline))
+	drain(t, u)
+
+	if got := len(sms.messages()); got != 2 {
+		t.Errorf("sms messages = %d, want one per server", got)
+	}
+}
+
+func TestStatusChanged_RetriesAfterFailedSend(t *testing.T) {
+	email, sms := &recordingChannel{}, &recordingChannel{err: errors.New("gateway down")}
+	u := newUseCase(t, email, sms).(*notificationUseCase)
+
+	labels := entity.Labels{"team": "sms"}
+	u.StatusChanged(context.Background(), event("srv-1", "HCM", labels, entity.ServerStatusOnline, entity.ServerStatusOffline))
+	u.messages.Wait()
+	u.StatusChanged(context.Background(), event("srv-1", "HCM", labels, entity.ServerStatusOnline, entity.ServerStatusOffline))
+	drain(t, u)
+
+	if got := len(sms.messages()); got != 2 {
+		t.Errorf("sms attempts = %d, want the failed message to be retried", got)
+	}
+}
+
+func TestStatusChanged_RoutesByTenant(t *testing.T) {
+	email, sms := &recordingChannel{}, &recordingChannel{}
+	u := newUseCase(t, email, sms)
+
+	// Without tenants the Hanoi route only sees the default tenant.
+	acme := event("srv-1", "Hanoi", nil, entity.ServerStatusOnline, entity.ServerStatusOffline)
+	acme.TenantID = "acme"
+	u.StatusChanged(context.Background(), acme)
+	other := event("srv-2", "Hanoi", nil, entity.ServerStatusOnline, entity.ServerStatusOffline)
+	other.TenantID = "globex"
+	u.StatusChanged(context.Background(), other)
+	drain(t, u)
+
+	emailSent := email.messages()
+	if len(emailSent) != 1 || emailSent[0].recipients[0] != "ops@acme.example" || emailSent[0].subject != "[OFFLINE] srv-1-name" {
+		t.Errorf("email messages = %+v, want only the acme route for the acme server", emailSent)
+	}
+}
+
+func TestStatusChanged_SuppressedDuringMaintenance(t *testing.T) {
+	email, sms := &recordingChannel{}, &recordingChannel{}
+	windows := &windowRepo{maintenance: map[string]bool{"default/srv-1": true}}
+	u := newUseCaseWithWindows(t, email, sms, windows).(*notificationUseCase)
+
+	labels := entity.Labels{"team": "sms"}
+	u.StatusChanged(context.Background(), event("srv-1", "HCM", labels, entity.ServerStatusOnline, entity.ServerStatusOffline))
+	u.StatusChanged(context.Background(), event("srv-2", "HCM", labels, entity.ServerStatusOnline, entity.ServerStatusOffline))
+	u.messages.Wait()
+
+	if sent := sms.messages(); len(sent) != 1 || sent[0].body != "srv-2-name OFFLINE" {
+		t.Fatalf("sms messages = %+v, want only the server outside maintenance", sent)
+	}
+
+	// Once the window is over the same transition is notified.
+	windows.maintenance = nil
+	u.StatusChanged(context.Background(), event("srv-1", "HCM", labels, entity.ServerStatusOnline, entity.ServerStatusOffline))
+	drain(t, u)
+	if got := len(sms.messages()); got != 2 {
+		t.Errorf("sms messages = %d, want srv-1 notified after maintenance", got)
+	}
+}
+
+func TestStatusChanged_SendsWhenMaintenanceCheckFails(t *testing.T) {
+	email, sms := &recordingChannel{}, &recordingChannel{}
+	u := newUseCaseWithWindows(t, email, sms, &windowRepo{err: errors.New("db down")})
+
+	u.StatusChanged(context.Background(), event("srv-1", "HCM", entity.Labels{"team": "sms"}, entity.ServerStatusOnline, entity.ServerStatusOffline))
+	drain(t, u)
+
+	if got := len(sms.messages()); got != 1 {
+		t.Errorf("sms messages = %d, want the notification sent", got)
+	}
+}
+
+func TestNewNotificationUseCase_RejectsUnconfiguredChannel(t *testing.T) {
+	_, err := NewNotificationUseCase(&config.Config{
+		Notification: config.Notification{Routes: testRoutes, Workers: 1},
+	}, map[string]srv.NotificationChannel{CHANNEL_EMAIL: &recordingChannel{}}, &windowRepo{}, zap.NewNop())
+	if err == nil {
+		t.Error("error = nil, want error for route using the unconfigured sms channel")
+	}
+}
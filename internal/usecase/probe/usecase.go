@@ -0,0 +1,128 @@
+package probe
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/mq"
+	repo "github.com/th1enq/ViettelSMS_ServerService/internal/domain/repository"
+	srv "github.com/th1enq/ViettelSMS_ServerService/internal/domain/service"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/metrics"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/server"
+	"go.uber.org/zap"
+)
+
+type UseCase interface {
+	// Targets lists the live servers of every tenant.
+	Targets(ctx context.Context) ([]*entity.Server, error)
+	// Check probes the server and records the result through the same
+	// UpdateStatus path as the status reports of agents.
+	Check(ctx context.Context, server *entity.Server) (entity.ServerStatus, error)
+	// RequestCheck publishes a health_check_request so that a probe agent
+	// checks the server instead of this process.
+	RequestCheck(ctx context.Context, server *entity.Server) error
+}
+
+type probeUseCase struct {
+	repo    repo.ServerRepository
+	prober  srv.Prober
+	servers server.UseCase
+	broker  mq.Publisher
+	topic   string
+	metrics *metrics.Metrics
+	logger  *zap.Logger
+}
+
+// NewProbeUseCase builds the probe usecase. prober is nil unless the built-in
+// prober is enabled, in which case Check must not be called.
+func NewProbeUseCase(
+	repo repo.ServerRepository,
+	prober srv.Prober,
+	servers server.UseCase,
+	broker mq.Publisher,
+	topic string,
+	metrics *metrics.Metrics,
+	logger *zap.Logger,
+) UseCase {
+	return &probeUseCase{
+		repo:    repo,
+		prober:  prober,
+		servers: servers,
+		broker:  broker,
+		topic:   topic,
+		metrics: metrics,
+		logger:  logger,
+	}
+}
+
+func (p *probeUseCase) Targets(ctx context.Context) ([]*entity.Server, error) {
+	servers, err := p.repo.ListActive(ctx)
+	if err != nil {
+		log.LoggerWithContext(ctx, p.logger).Error("failed to list servers to probe", zap.Error(err))
+		return nil, err
+	}
+	return servers, nil
+}
+
+func (p *probeUseCase) Check(ctx context.Context, server *entity.Server) (entity.ServerStatus, error) {
+	ctx = tenant.NewContext(ctx, server.TenantID)
+	logger := log.LoggerWithContext(ctx, p.logger).With(zap.String("server_id", server.ServerID), zap.String("tenant_id", server.TenantID))
+
+	start := time.Now()
+	status := entity.ServerStatusOnline
+	if err := p.prober.Probe(ctx, server); err != nil {
+		if ctx.Err() != nil {
+			return entity.ServerStatusUnknown, ctx.Err()
+		}
+		logger.Debug("Probe failed", zap.String("ipv4", server.IPv4), zap.Error(err))
+		status = entity.ServerStatusOffline
+	}
+	p.metrics.ProbeDuration.WithLabelValues(string(status)).Observe(time.Since(start).Seconds())
+
+	err := p.servers.UpdateStatus(ctx, dto.UpdateStatusMessage{
+		ServerID:  server.ServerID,
+		Status:    status,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		logger.Warn("Failed to record probe result", zap.String("status", string(status)), zap.Error(err))
+		return status, err
+	}
+	return status, nil
+}
+
+func (p *probeUseCase) RequestCheck(ctx context.Context, server *entity.Server) error {
+	ctx = tenant.NewContext(ctx, server.TenantID)
+	logger := log.LoggerWithContext(ctx, p.logger).With(zap.String("server_id", server.ServerID), zap.String("tenant_id", server.TenantID))
+
+	now := time.Now()
+	body, err := json.Marshal(dto.HealthCheckRequestMessage{
+		TenantID:     server.TenantID,
+		ServerID:     server.ServerID,
+		IPv4:         server.IPv4,
+		IntervalTime: server.IntervalTime,
+		RequestedAt:  now,
+		Deadline:     now.Add(server.CheckInterval()),
+	})
+	if err != nil {
+		logger.Error("Failed to marshal health check request", zap.Error(err))
+		return err
+	}
+
+	// Keyed by server so the requests for a server stay ordered on one partition.
+	err = p.broker.Send(ctx, mq.Message{
+		Key:   server.TenantID + "/" + server.ServerID,
+		Body:  body,
+		Topic: p.topic,
+	})
+	if err != nil {
+		logger.Error("Failed to publish health check request", zap.Error(err))
+		return err
+	}
+	return nil
+}
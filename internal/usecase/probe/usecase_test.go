@@ -0,0 +1,125 @@
+package probe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/mq"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/metrics"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/prober"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/usecase/server"
+	"go.uber.org/zap"
+)
+
+// mockServers records status updates; other UseCase methods are not used.
+type mockServers struct {
+	server.UseCase
+	updates []dto.UpdateStatusMessage
+	tenants []string
+}
+
+func (m *mockServers) UpdateStatus(ctx context.Context, msg dto.UpdateStatusMessage) error {
+	m.updates = append(m.updates, msg)
+	m.tenants = append(m.tenants, tenant.FromContext(ctx))
+	return nil
+}
+
+func TestCheck_LoopbackListener(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, portStr, _ := net.SplitHostPort(l.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+
+	servers := &mockServers{}
+	uc := NewProbeUseCase(nil, prober.NewTCPProber(port, time.Second), servers, nil, "", metrics.NewMetrics(), zap.NewNop())
+	target := &entity.Server{TenantID: "acme", ServerID: "s1", IPv4: "127.0.0.1", IntervalTime: 5}
+
+	status, err := uc.Check(context.Background(), target)
+	if err != nil || status != entity.ServerStatusOnline {
+		t.Fatalf("expected ONLINE, got %s, %v", status, err)
+	}
+
+	l.Close()
+	status, err = uc.Check(context.Background(), target)
+	if err != nil || status != entity.ServerStatusOffline {
+		t.Fatalf("expected OFFLINE after listener closed, got %s, %v", status, err)
+	}
+
+	if len(servers.updates) != 2 {
+		t.Fatalf("expected 2 status updates, got %d", len(servers.updates))
+	}
+	if servers.updates[0].Status != entity.ServerStatusOnline || servers.updates[1].Status != entity.ServerStatusOffline {
+		t.Fatalf("unexpected updates: %+v", servers.updates)
+	}
+	for _, tenantID := range servers.tenants {
+		if tenantID != "acme" {
+			t.Fatalf("status update must run in the server's tenant, got %q", tenantID)
+		}
+	}
+}
+
+// recordingPublisher keeps published messages with the tenant they were sent in.
+type recordingPublisher struct {
+	messages []mq.Message
+	tenants  []string
+	err      error
+}
+
+func (p *recordingPublisher) Send(ctx context.Context, message mq.Message) error {
+	p.messages = append(p.messages, message)
+	p.tenants = append(p.tenants, tenant.FromContext(ctx))
+	return p.err
+}
+
+func TestRequestCheck_PublishesRequest(t *testing.T) {
+	publisher := &recordingPublisher{}
+	servers := &mockServers{}
+	uc := NewProbeUseCase(nil, nil, servers, publisher, "health_check_request", metrics.NewMetrics(), zap.NewNop())
+	target := &entity.Server{TenantID: "acme", ServerID: "s1", IPv4: "10.0.0.1", IntervalTime: 30}
+
+	before := time.Now()
+	if err := uc.RequestCheck(context.Background(), target); err != nil {
+		t.Fatalf("request check: %v", err)
+	}
+
+	if len(publisher.messages) != 1 {
+		t.Fatalf("expected one message, got %d", len(publisher.messages))
+	}
+	message := publisher.messages[0]
+	if message.Topic != "health_check_request" || message.Key != "acme/s1" || publisher.tenants[0] != "acme" {
+		t.Fatalf("unexpected message %+v in tenant %q", message, publisher.tenants[0])
+	}
+
+	var request dto.HealthCheckRequestMessage
+	if err := json.Unmarshal(message.Body, &request); err != nil {
+		t.Fatalf("decode request: %v", err)
+	}
+	if request.TenantID != "acme" || request.ServerID != "s1" || request.IPv4 != "10.0.0.1" || request.IntervalTime != 30 {
+		t.Fatalf("unexpected request %+v", request)
+	}
+	if request.RequestedAt.Before(before) || request.Deadline.Sub(request.RequestedAt) != target.CheckInterval() {
+		t.Fatalf("deadline must be one interval after the request, got %+v", request)
+	}
+	if len(servers.updates) != 0 {
+		t.Fatalf("requesting a check must not update the status, got %+v", servers.updates)
+	}
+}
+
+func TestRequestCheck_PublishError(t *testing.T) {
+	publisher := &recordingPublisher{err: errors.New("broker down")}
+	uc := NewProbeUseCase(nil, nil, &mockServers{}, publisher, "health_check_request", metrics.NewMetrics(), zap.NewNop())
+
+	if err := uc.RequestCheck(context.Background(), &entity.Server{ServerID: "s1"}); !errors.Is(err, publisher.err) {
+		t.Fatalf("want the publish error, got %v", err)
+	}
+}
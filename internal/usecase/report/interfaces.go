@@ -0,0 +1,20 @@
+package report
+
+import (
+	"context"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+)
+
+type UseCase interface {
+	// Generate builds the inventory, status and uptime report of every tenant
+	// for the period ending at end, stores it and emails it to the tenant's
+	// recipients. A tenant whose run for end was already claimed, by another
+	// replica reaching the same schedule, is skipped.
+	Generate(ctx context.Context, end time.Time) error
+	// ListReports returns the stored reports of the caller's tenant.
+	ListReports(ctx context.Context) ([]dto.ReportResponse, error)
+	// ReportContent returns the workbook of a stored report.
+	ReportContent(ctx context.Context, name string) ([]byte, error)
+}
@@ -0,0 +1,229 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/authz"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	repo "github.com/th1enq/ViettelSMS_ServerService/internal/domain/repository"
+	srv "github.com/th1enq/ViettelSMS_ServerService/internal/domain/service"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// REPORT_NAME_FORMAT names reports after the period they cover.
+	REPORT_NAME_FORMAT = "20060102-1504"
+
+	XLSX_CONTENT_TYPE = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+type reportUseCase struct {
+	servers repo.ServerRepository
+	alerts  repo.AlertRepository
+	reports repo.ReportRepository
+	mailer  srv.AttachmentChannel
+	config  config.Report
+	logger  *zap.Logger
+}
+
+// NewReportUseCase builds the report usecase. mailer may be nil when SMTP is
+// not configured, in which case reports are only stored. Reports are stored in
+// the database, so any replica can serve the reports another one generated.
+func NewReportUseCase(
+	servers repo.ServerRepository,
+	alerts repo.AlertRepository,
+	reports repo.ReportRepository,
+	mailer srv.AttachmentChannel,
+	config *config.Config,
+	logger *zap.Logger,
+) UseCase {
+	return &reportUseCase{
+		servers: servers,
+		alerts:  alerts,
+		reports: reports,
+		mailer:  mailer,
+		config:  config.Report,
+		logger:  logger,
+	}
+}
+
+func (r *reportUseCase) Generate(ctx context.Context, end time.Time) error {
+	logger := log.LoggerWithContext(ctx, r.logger)
+	start := end.Add(-r.config.Period)
+	logger.Info("Generate report called", zap.Time("start", start), zap.Time("end", end))
+
+	servers, err := r.servers.ListActive(ctx)
+	if err != nil {
+		logger.Error("failed to list servers for report", zap.Error(err))
+		return domain.ErrDatabase
+	}
+	transitions, err := r.alerts.ListTransitionsSince(ctx, start)
+	if err != nil {
+		logger.Error("failed to list status transitions for report", zap.Error(err))
+		return domain.ErrDatabase
+	}
+
+	byTenant := make(map[string][]*entity.Server)
+	for _, server := range servers {
+		byTenant[server.TenantID] = append(byTenant[server.TenantID], server)
+	}
+	byServer := make(map[string][]*entity.StatusTransition)
+	for _, transition := range transitions {
+		key := transition.TenantID + "/" + transition.ServerID
+		byServer[key] = append(byServer[key], transition)
+	}
+
+	tenants := make([]string, 0, len(byTenant))
+	for tenantID := range byTenant {
+		tenants = append(tenants, tenantID)
+	}
+	sort.Strings(tenants)
+
+	var failed bool
+	for _, tenantID := range tenants {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		tenantCtx := tenant.NewContext(ctx, tenantID)
+		claimed, err := r.reports.ClaimRun(tenantCtx, end)
+		if err != nil {
+			logger.Error("failed to claim report run", zap.String("tenant_id", tenantID), zap.Error(err))
+			failed = true
+			continue
+		}
+		if !claimed {
+			logger.Info("Report already generated by another replica", zap.String("tenant_id", tenantID))
+			continue
+		}
+
+		tenantServers := byTenant[tenantID]
+		uptimes := make(map[string]uptime, len(tenantServers))
+		for _, server := range tenantServers {
+			uptimes[server.ServerID] = computeUptime(server, byServer[tenantID+"/"+server.ServerID], start, end)
+		}
+		if err := r.generateTenant(tenantCtx, tenantServers, uptimes, start, end); err != nil {
+			logger.Error("failed to generate report", zap.String("tenant_id", tenantID), zap.Error(err))
+			failed = true
+			// Without the claim the next replica or run retries the report.
+			if err := r.reports.ReleaseRun(context.WithoutCancel(tenantCtx), end); err != nil {
+				logger.Error("failed to release report run", zap.String("tenant_id", tenantID), zap.Error(err))
+			}
+		}
+	}
+	if failed {
+		return domain.ErrInternalServer
+	}
+
+	logger.Info("Reports generated successfully", zap.Int("tenants", len(tenants)))
+	return nil
+}
+
+func (r *reportUseCase) generateTenant(ctx context.Context, servers []*entity.Server, uptimes map[string]uptime, start, end time.Time) error {
+	logger := log.LoggerWithContext(ctx, r.logger)
+	tenantID := tenant.FromContext(ctx)
+
+	content, err := buildWorkbook(servers, uptimes)
+	if err != nil {
+		return fmt.Errorf("failed to build workbook: %w", err)
+	}
+
+	name := fmt.Sprintf("report_%s_%s.xlsx", start.Format(REPORT_NAME_FORMAT), end.Format(REPORT_NAME_FORMAT))
+	if err := r.reports.Save(ctx, &entity.Report{Name: name, Content: content}); err != nil {
+		return fmt.Errorf("failed to store report: %w", err)
+	}
+	logger.Info("Report stored", zap.String("tenant_id", tenantID), zap.String("name", name), zap.Int("servers", len(servers)))
+
+	recipients := r.config.Recipients[tenantID]
+	if r.mailer == nil || len(recipients) == 0 {
+		return nil
+	}
+	subject := fmt.Sprintf("Server report %s to %s", start.Format(time.DateOnly), end.Format(time.DateOnly))
+	attachment := srv.Attachment{Name: name, ContentType: XLSX_CONTENT_TYPE, Content: content}
+	if err := r.mailer.SendWithAttachment(ctx, recipients, subject, summary(servers, uptimes, start, end), attachment); err != nil {
+		// The report is stored and listed; only the email is lost.
+		logger.Warn("failed to email report", zap.String("tenant_id", tenantID), zap.String("name", name), zap.Error(err))
+	}
+	return nil
+}
+
+func (r *reportUseCase) ListReports(ctx context.Context) ([]dto.ReportResponse, error) {
+	logger := log.LoggerWithContext(ctx, r.logger)
+	logger.Info("ListReports called")
+
+	if err := checkUnrestricted(ctx); err != nil {
+		logger.Warn("restricted caller cannot list reports")
+		return nil, err
+	}
+
+	reports, err := r.reports.List(ctx)
+	if err != nil {
+		logger.Error("failed to list reports", zap.Error(err))
+		return nil, domain.ErrDatabase
+	}
+	return dto.ToReportsResponse(reports), nil
+}
+
+func (r *reportUseCase) ReportContent(ctx context.Context, name string) ([]byte, error) {
+	logger := log.LoggerWithContext(ctx, r.logger)
+	logger.Info("ReportContent called", zap.String("name", name))
+
+	if err := checkUnrestricted(ctx); err != nil {
+		logger.Warn("restricted caller cannot download reports")
+		return nil, err
+	}
+
+	report, err := r.reports.GetByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Warn("Report not found", zap.String("name", name))
+			return nil, domain.ErrReportNotFound
+		}
+		logger.Error("failed to get report", zap.String("name", name), zap.Error(err))
+		return nil, domain.ErrDatabase
+	}
+	return report.Content, nil
+}
+
+// checkUnrestricted keeps reports, which cover the whole tenant, away from
+// principals limited to part of the inventory.
+func checkUnrestricted(ctx context.Context) error {
+	if principal := authz.FromContext(ctx); principal != nil && len(principal.Constraints) > 0 {
+		return domain.ErrReportRestricted
+	}
+	return nil
+}
+
+// summary is the email body: the period and a status overview.
+func summary(servers []*entity.Server, uptimes map[string]uptime, start, end time.Time) string {
+	counts := make(map[entity.ServerStatus]int)
+	var total float64
+	var measured int
+	for _, server := range servers {
+		counts[server.Status]++
+		if percent, ok := uptimes[server.ServerID].percent(); ok {
+			total += percent
+			measured++
+		}
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Server report for %s to %s.\n\n", start.Format(CELL_TIME_FORMAT), end.Format(CELL_TIME_FORMAT))
+	fmt.Fprintf(&body, "Servers: %d (online %d, offline %d, unknown %d)\n", len(servers),
+		counts[entity.ServerStatusOnline], counts[entity.ServerStatusOffline], counts[entity.ServerStatusUnknown])
+	if measured > 0 {
+		fmt.Fprintf(&body, "Average uptime: %.2f%%\n", total/float64(measured))
+	}
+	body.WriteString("\nThe attached workbook has the inventory, current status and uptime sheets.\n")
+	return body.String()
+}
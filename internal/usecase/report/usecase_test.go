@@ -0,0 +1,253 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/authz"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	repoiface "github.com/th1enq/ViettelSMS_ServerService/internal/domain/repository"
+	srv "github.com/th1enq/ViettelSMS_ServerService/internal/domain/service"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type mockServers struct {
+	repoiface.ServerRepository
+	servers []*entity.Server
+}
+
+func (m *mockServers) ListActive(ctx context.Context) ([]*entity.Server, error) {
+	return m.servers, nil
+}
+
+type mockAlerts struct {
+	repoiface.AlertRepository
+	transitions []*entity.StatusTransition
+}
+
+func (m *mockAlerts) ListTransitionsSince(ctx context.Context, since time.Time) ([]*entity.StatusTransition, error) {
+	return m.transitions, nil
+}
+
+// mockReports claims each tenant and period once, claimed runs being taken by
+// another replica, and keeps the stored reports by tenant and name.
+type mockReports struct {
+	claimed map[string]bool
+	saved   map[string][]byte
+	err     error
+}
+
+func newMockReports() *mockReports {
+	return &mockReports{claimed: make(map[string]bool), saved: make(map[string][]byte)}
+}
+
+func (m *mockReports) ClaimRun(ctx context.Context, periodEnd time.Time) (bool, error) {
+	key := tenant.FromContext(ctx) + "/" + periodEnd.String()
+	if m.claimed[key] {
+		return false, nil
+	}
+	m.claimed[key] = true
+	return true, nil
+}
+func (m *mockReports) ReleaseRun(ctx context.Context, periodEnd time.Time) error {
+	delete(m.claimed, tenant.FromContext(ctx)+"/"+periodEnd.String())
+	return nil
+}
+func (m *mockReports) Save(ctx context.Context, report *entity.Report) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.saved[tenant.FromContext(ctx)+"/"+report.Name] = report.Content
+	return nil
+}
+func (m *mockReports) List(ctx context.Context) ([]*entity.Report, error) {
+	return []*entity.Report{}, nil
+}
+func (m *mockReports) GetByName(ctx context.Context, name string) (*entity.Report, error) {
+	if content, ok := m.saved[tenant.FromContext(ctx)+"/"+name]; ok {
+		return &entity.Report{Name: name, Content: content}, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type sentMail struct {
+	recipients []string
+	body       string
+	attachment srv.Attachment
+}
+
+type mockMailer struct {
+	srv.AttachmentChannel
+	sent []sentMail
+}
+
+func (m *mockMailer) SendWithAttachment(ctx context.Context, recipients []string, subject, body string, attachment srv.Attachment) error {
+	m.sent = append(m.sent, sentMail{recipients: recipients, body: body, attachment: attachment})
+	return nil
+}
+
+func TestComputeUptime(t *testing.T) {
+	start := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	end := start.Add(10 * time.Hour)
+	server := &entity.Server{ServerID: "s1", Status: entity.ServerStatusOnline, CreatedAt: start.Add(-24 * time.Hour)}
+
+	// OFFLINE until 02:00, ONLINE until 06:00, OFFLINE until 07:00, then ONLINE.
+	transitions := []*entity.StatusTransition{
+		{ServerID: "s1", PreviousStatus: entity.ServerStatusOffline, Status: entity.ServerStatusOnline, ChangedAt: start.Add(2 * time.Hour)},
+		{ServerID: "s1", PreviousStatus: entity.ServerStatusOnline, Status: entity.ServerStatusOffline, ChangedAt: start.Add(6 * time.Hour)},
+		{ServerID: "s1", PreviousStatus: entity.ServerStatusOffline, Status: entity.ServerStatusOnline, ChangedAt: start.Add(7 * time.Hour)},
+	}
+	u := computeUptime(server, transitions, start, end)
+	if u.online != 7*time.Hour || u.offline != 3*time.Hour || u.transitions != 3 {
+		t.Fatalf("unexpected uptime %+v", u)
+	}
+	if percent, ok := u.percent(); !ok || percent != 70 {
+		t.Fatalf("expected 70%%, got %v", percent)
+	}
+
+	// Created half way through without any transition: only its lifetime counts.
+	created := &entity.Server{ServerID: "s2", Status: entity.ServerStatusUnknown, CreatedAt: start.Add(5 * time.Hour)}
+	u = computeUptime(created, nil, start, end)
+	if u.unknown != 5*time.Hour || u.online != 0 {
+		t.Fatalf("unexpected uptime %+v", u)
+	}
+	if _, ok := u.percent(); ok {
+		t.Fatalf("expected no uptime for a server never reported")
+	}
+}
+
+func TestGenerate_StoresAndEmailsPerTenant(t *testing.T) {
+	created := time.Now().Add(-30 * 24 * time.Hour)
+	servers := &mockServers{servers: []*entity.Server{
+		{TenantID: "acme", ServerID: "s1", ServerName: "web-01", Status: entity.ServerStatusOnline, CreatedAt: created},
+		{TenantID: "acme", ServerID: "s2", ServerName: "web-02", Status: entity.ServerStatusOffline, CreatedAt: created},
+		{TenantID: "globex", ServerID: "s1", ServerName: "db-01", Status: entity.ServerStatusOnline, CreatedAt: created},
+	}}
+	store := newMockReports()
+	mailer := &mockMailer{}
+	u := NewReportUseCase(servers, &mockAlerts{}, store, mailer, &config.Config{Report: config.Report{
+		Period:     7 * 24 * time.Hour,
+		Recipients: map[string][]string{"acme": {"managers@acme.example"}},
+	}}, zap.NewNop())
+
+	if err := u.Generate(context.Background(), time.Now()); err != nil {
+		t.Fatalf("unexpected: %v", err)
+	}
+	if len(store.saved) != 2 {
+		t.Fatalf("expected one report per tenant, got %d", len(store.saved))
+	}
+	if len(mailer.sent) != 1 || mailer.sent[0].recipients[0] != "managers@acme.example" {
+		t.Fatalf("expected only acme emailed, got %+v", mailer.sent)
+	}
+	if !strings.Contains(mailer.sent[0].body, "Servers: 2 (online 1, offline 1, unknown 0)") {
+		t.Fatalf("unexpected summary %q", mailer.sent[0].body)
+	}
+
+	file, err := excelize.OpenReader(strings.NewReader(string(mailer.sent[0].attachment.Content)))
+	if err != nil {
+		t.Fatalf("attachment is not a workbook: %v", err)
+	}
+	defer file.Close()
+	if sheets := file.GetSheetList(); len(sheets) != 3 || sheets[0] != SHEET_INVENTORY || sheets[2] != SHEET_UPTIME {
+		t.Fatalf("unexpected sheets %v", sheets)
+	}
+	rows, _ := file.GetRows(SHEET_UPTIME)
+	if len(rows) != 3 || rows[1][0] != "s1" || rows[1][2] != "100" || rows[2][2] != "0" {
+		t.Fatalf("unexpected uptime rows %v", rows)
+	}
+}
+
+func TestListReports_RejectsRestrictedCallers(t *testing.T) {
+	u := NewReportUseCase(&mockServers{}, &mockAlerts{}, newMockReports(), nil, &config.Config{}, zap.NewNop())
+
+	ctx := tenant.NewContext(context.Background(), "acme")
+	if _, err := u.ListReports(ctx); err != nil {
+		t.Fatalf("unexpected: %v", err)
+	}
+
+	restricted := authz.NewContext(ctx, &authz.Principal{Constraints: authz.Constraints{authz.ConstraintLocation: {"HN"}}})
+	if _, err := u.ListReports(restricted); !errors.Is(err, domain.ErrReportRestricted) {
+		t.Fatalf("want restricted, got %v", err)
+	}
+	if _, err := u.ReportContent(ctx, "report.xlsx"); !errors.Is(err, domain.ErrReportNotFound) {
+		t.Fatalf("want not found, got %v", err)
+	}
+	if _, err := u.ReportContent(restricted, "report.xlsx"); !errors.Is(err, domain.ErrReportRestricted) {
+		t.Fatalf("want restricted, got %v", err)
+	}
+}
+
+func TestGenerate_SkipsRunsClaimedByAnotherReplica(t *testing.T) {
+	created := time.Now().Add(-30 * 24 * time.Hour)
+	servers := &mockServers{servers: []*entity.Server{
+		{TenantID: "acme", ServerID: "s1", Status: entity.ServerStatusOnline, CreatedAt: created},
+		{TenantID: "globex", ServerID: "s1", Status: entity.ServerStatusOnline, CreatedAt: created},
+	}}
+	end := time.Date(2025, 1, 6, 8, 0, 0, 0, time.UTC)
+	store := newMockReports()
+	store.claimed["acme/"+end.String()] = true
+	mailer := &mockMailer{}
+	u := NewReportUseCase(servers, &mockAlerts{}, store, mailer, &config.Config{Report: config.Report{
+		Period:     24 * time.Hour,
+		Recipients: map[string][]string{"acme": {"managers@acme.example"}, "globex": {"ops@globex.example"}},
+	}}, zap.NewNop())
+
+	if err := u.Generate(context.Background(), end); err != nil {
+		t.Fatalf("unexpected: %v", err)
+	}
+	if _, ok := store.saved["globex/report_20250105-0800_20250106-0800.xlsx"]; !ok || len(store.saved) != 1 {
+		t.Fatalf("expected only the globex report stored, got %v", keys(store.saved))
+	}
+	if len(mailer.sent) != 1 || mailer.sent[0].recipients[0] != "ops@globex.example" {
+		t.Fatalf("expected only globex emailed, got %+v", mailer.sent)
+	}
+
+	// A second replica reaching the same run generates nothing.
+	if err := u.Generate(context.Background(), end); err != nil {
+		t.Fatalf("unexpected: %v", err)
+	}
+	if len(store.saved) != 1 || len(mailer.sent) != 1 {
+		t.Fatalf("expected the run generated once, got %d reports and %d emails", len(store.saved), len(mailer.sent))
+	}
+}
+
+func TestGenerate_ReleasesFailedRuns(t *testing.T) {
+	servers := &mockServers{servers: []*entity.Server{
+		{TenantID: "acme", ServerID: "s1", Status: entity.ServerStatusOnline, CreatedAt: time.Now().Add(-30 * 24 * time.Hour)},
+	}}
+	end := time.Date(2025, 1, 6, 8, 0, 0, 0, time.UTC)
+	store := newMockReports()
+	store.err = errors.New("connection reset")
+	u := NewReportUseCase(servers, &mockAlerts{}, store, nil, &config.Config{Report: config.Report{Period: 24 * time.Hour}}, zap.NewNop())
+
+	if err := u.Generate(context.Background(), end); !errors.Is(err, domain.ErrInternalServer) {
+		t.Fatalf("want internal error, got %v", err)
+	}
+	if store.claimed["acme/"+end.String()] {
+		t.Fatalf("expected the failed run released")
+	}
+
+	store.err = nil
+	if err := u.Generate(context.Background(), end); err != nil {
+		t.Fatalf("unexpected: %v", err)
+	}
+	if len(store.saved) != 1 {
+		t.Fatalf("expected the retried run stored, got %v", keys(store.saved))
+	}
+}
+
+func keys(m map[string][]byte) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	return result
+}
@@ -0,0 +1,180 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	SHEET_INVENTORY = "Inventory"
+	SHEET_STATUS    = "Status"
+	SHEET_UPTIME    = "Uptime"
+
+	CELL_TIME_FORMAT = "2006-01-02 15:04:05"
+)
+
+// uptime is the time a server spent in each status over the report period.
+type uptime struct {
+	online      time.Duration
+	offline     time.Duration
+	unknown     time.Duration
+	transitions int
+}
+
+// percent is the share of the period with a known status that the server was
+// online; ok is false when its status was never known.
+func (u uptime) percent() (float64, bool) {
+	known := u.online + u.offline
+	if known <= 0 {
+		return 0, false
+	}
+	return float64(u.online) / float64(known) * 100, true
+}
+
+// computeUptime replays the transitions of a server, oldest first, over the
+// period. Before its first transition the server was in the status that
+// transition left, or in its current status if it did not change at all.
+// Time before the server was created is not counted.
+func computeUptime(server *entity.Server, transitions []*entity.StatusTransition, start, end time.Time) uptime {
+	var result uptime
+	cursor := start
+	if server.CreatedAt.After(cursor) {
+		cursor = server.CreatedAt
+	}
+
+	status := server.Status
+	for _, transition := range transitions {
+		if transition.ChangedAt.After(cursor) {
+			status = transition.PreviousStatus
+			break
+		}
+	}
+
+	add := func(status entity.ServerStatus, d time.Duration) {
+		if d <= 0 {
+			return
+		}
+		switch status {
+		case entity.ServerStatusOnline:
+			result.online += d
+		case entity.ServerStatusOffline:
+			result.offline += d
+		default:
+			result.unknown += d
+		}
+	}
+
+	for _, transition := range transitions {
+		if !transition.ChangedAt.After(cursor) || transition.ChangedAt.After(end) {
+			continue
+		}
+		add(status, transition.ChangedAt.Sub(cursor))
+		status = transition.Status
+		cursor = transition.ChangedAt
+		result.transitions++
+	}
+	add(status, end.Sub(cursor))
+	return result
+}
+
+// buildWorkbook writes the three report sheets with excelize stream writers.
+func buildWorkbook(servers []*entity.Server, uptimes map[string]uptime) ([]byte, error) {
+	file := excelize.NewFile()
+	defer file.Close()
+
+	if err := file.SetSheetName("Sheet1", SHEET_INVENTORY); err != nil {
+		return nil, err
+	}
+	for _, sheet := range []string{SHEET_STATUS, SHEET_UPTIME} {
+		if _, err := file.NewSheet(sheet); err != nil {
+			return nil, err
+		}
+	}
+
+	sheets := []struct {
+		name   string
+		header []interface{}
+		row    func(server *entity.Server) []interface{}
+	}{
+		{
+			name:   SHEET_INVENTORY,
+			header: []interface{}{"server_id", "server_name", "ipv4", "location", "os", "interval_time", "group", "created_at"},
+			row: func(server *entity.Server) []interface{} {
+				return []interface{}{
+					server.ServerID,
+					server.ServerName,
+					server.IPv4,
+					server.Location,
+					server.OS,
+					server.IntervalTime,
+					server.Group,
+					server.CreatedAt.Format(CELL_TIME_FORMAT),
+				}
+			},
+		},
+		{
+			name:   SHEET_STATUS,
+			header: []interface{}{"server_id", "server_name", "status", "status_changed_at"},
+			row: func(server *entity.Server) []interface{} {
+				changedAt := ""
+				if server.StatusChangedAt != nil {
+					changedAt = server.StatusChangedAt.Format(CELL_TIME_FORMAT)
+				}
+				return []interface{}{server.ServerID, server.ServerName, string(server.Status), changedAt}
+			},
+		},
+		{
+			name:   SHEET_UPTIME,
+			header: []interface{}{"server_id", "server_name", "uptime_percent", "online_hours", "offline_hours", "unknown_hours", "transitions"},
+			row: func(server *entity.Server) []interface{} {
+				u := uptimes[server.ServerID]
+				var percent interface{} = ""
+				if value, ok := u.percent(); ok {
+					percent = round(value)
+				}
+				return []interface{}{
+					server.ServerID,
+					server.ServerName,
+					percent,
+					round(u.online.Hours()),
+					round(u.offline.Hours()),
+					round(u.unknown.Hours()),
+					u.transitions,
+				}
+			},
+		},
+	}
+
+	for _, sheet := range sheets {
+		writer, err := file.NewStreamWriter(sheet.name)
+		if err != nil {
+			return nil, err
+		}
+		if err := writer.SetRow("A1", sheet.header); err != nil {
+			return nil, err
+		}
+		for i, server := range servers {
+			cell, _ := excelize.CoordinatesToCellName(1, i+2)
+			if err := writer.SetRow(cell, sheet.row(server)); err != nil {
+				return nil, fmt.Errorf("failed to write %s row of server %s: %w", sheet.name, server.ServerID, err)
+			}
+		}
+		if err := writer.Flush(); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	if _, err := file.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func round(value float64) float64 {
+	return float64(int64(value*100+0.5)) / 100
+}
@@ -0,0 +1,229 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/authz"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	"go.uber.org/zap"
+)
+
+func (s *serverUseCase) BulkOperation(ctx context.Context, params dto.BulkOperationParams) (*dto.BulkOperationResponse, error) {
+	logger := log.LoggerWithContext(ctx, s.logger)
+	logger.Info("BulkOperation called",
+		zap.String("operation", string(params.Operation)),
+		zap.Int("server_ids", len(params.ServerIDs)),
+		zap.Any("filter", params.Filter),
+		zap.Bool("dry_run", params.DryRun))
+
+	if len(params.ServerIDs) > BULK_MAX_SIZE {
+		logger.Warn("Bulk operation too large", zap.Int("server_ids", len(params.ServerIDs)))
+		return nil, domain.ErrBulkLimitExceeded
+	}
+	if params.Operation == dto.BulkOperationPatch && isEmptyPatch(params.Patch) {
+		logger.Warn("Bulk patch without fields")
+		return nil, domain.ErrEmptyPatch
+	}
+
+	response := &dto.BulkOperationResponse{
+		Operation: params.Operation,
+		DryRun:    params.DryRun,
+		Results:   make([]dto.BulkServerResult, 0),
+	}
+
+	var changed []*entity.Server
+	err := s.repo.WithTransaction(ctx, func(ctx context.Context) error {
+		servers, notFound, err := s.resolveBulkTargets(ctx, params)
+		if err != nil {
+			return err
+		}
+		for _, serverID := range notFound {
+			response.Results = append(response.Results, dto.BulkServerResult{
+				ServerID: serverID,
+				Result:   dto.BulkResultNotFound,
+				Error:    domain.ErrServerNotFound.Error(),
+			})
+		}
+
+		principal := authz.FromContext(ctx)
+		eligible := make([]string, 0, len(servers))
+		targets := make([]*entity.Server, 0, len(servers))
+		for _, server := range servers {
+			if !principal.CanAccess(server) || !principal.CanAccess(applyBulkPatch(*server, params)) {
+				response.Results = append(response.Results, dto.BulkServerResult{
+					ServerID: server.ServerID,
+					Result:   dto.BulkResultForbidden,
+					Error:    domain.ErrForbidden.Error(),
+				})
+				continue
+			}
+			eligible = append(eligible, server.ServerID)
+			targets = append(targets, server)
+		}
+		response.Matched = len(servers)
+
+		result := dto.BulkResultWouldApply
+		if !params.DryRun && len(eligible) > 0 {
+			applied, err := s.applyBulkOperation(ctx, eligible, params)
+			if err != nil {
+				return err
+			}
+			response.Applied = int(applied)
+			result = dto.BulkResultApplied
+			changed = targets
+		}
+		for _, serverID := range eligible {
+			response.Results = append(response.Results, dto.BulkServerResult{ServerID: serverID, Result: result})
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrBulkLimitExceeded) {
+			return nil, err
+		}
+		logger.Error("failed to run bulk operation", zap.Error(err))
+		return nil, domain.ErrDatabase
+	}
+
+	s.publishBulk(ctx, params, changed)
+
+	logger.Info("BulkOperation completed",
+		zap.String("operation", string(params.Operation)),
+		zap.Int("matched", response.Matched),
+		zap.Int("applied", response.Applied))
+	return response, nil
+}
+
+// resolveBulkTargets loads the live servers addressed by the request and reports
+// requested IDs that do not exist.
+func (s *serverUseCase) resolveBulkTargets(ctx context.Context, params dto.BulkOperationParams) ([]*entity.Server, []string, error) {
+	logger := log.LoggerWithContext(ctx, s.logger)
+	if params.Filter != nil {
+		filter := *params.Filter
+		filter.IncludeDeleted = false
+		servers, total, err := s.repo.GetServers(ctx, filter, dto.ServerPaginationOptions{
+			Page:      1,
+			PageSize:  BULK_MAX_SIZE,
+			SortBy:    "server_name",
+			SortOrder: "asc",
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if total > BULK_MAX_SIZE {
+			logger.Warn("Bulk filter matches too many servers", zap.Int("total", total))
+			return nil, nil, domain.ErrBulkLimitExceeded
+		}
+		return servers, nil, nil
+	}
+
+	serverIDs := make([]string, 0, len(params.ServerIDs))
+	seen := make(map[string]bool, len(params.ServerIDs))
+	for _, serverID := range params.ServerIDs {
+		if !seen[serverID] {
+			seen[serverID] = true
+			serverIDs = append(serverIDs, serverID)
+		}
+	}
+
+	servers, err := s.repo.GetByIDs(ctx, serverIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	found := make(map[string]bool, len(servers))
+	for _, server := range servers {
+		found[server.ServerID] = true
+	}
+	notFound := make([]string, 0)
+	for _, serverID := range serverIDs {
+		if !found[serverID] {
+			notFound = append(notFound, serverID)
+		}
+	}
+	return servers, notFound, nil
+}
+
+func (s *serverUseCase) applyBulkOperation(ctx context.Context, serverIDs []string, params dto.BulkOperationParams) (int64, error) {
+	switch params.Operation {
+	case dto.BulkOperationDelete:
+		return s.repo.BulkDelete(ctx, serverIDs)
+	case dto.BulkOperationPatch:
+		return s.repo.BulkPatch(ctx, serverIDs, *params.Patch)
+	default:
+		return s.repo.BulkUpdateStatus(ctx, serverIDs, *params.Status)
+	}
+}
+
+// publishBulk reports the servers changed by a committed bulk operation, as
+// they were before it. Status changes also reach the notifier, like those
+// consumed from Kafka.
+func (s *serverUseCase) publishBulk(ctx context.Context, params dto.BulkOperationParams, servers []*entity.Server) {
+	if (s.notifier == nil && s.events == nil) || len(servers) == 0 {
+		return
+	}
+
+	switch params.Operation {
+	case dto.BulkOperationDelete:
+		for _, server := range servers {
+			s.publish(ctx, dto.NewServerEvent(dto.ServerEventDeleted, server))
+		}
+	case dto.BulkOperationPatch:
+		if s.events == nil {
+			return
+		}
+		// Labels are merged by the database, so the new state is read back.
+		serverIDs := make([]string, len(servers))
+		for i, server := range servers {
+			serverIDs[i] = server.ServerID
+		}
+		patched, err := s.repo.GetByIDs(ctx, serverIDs)
+		if err != nil {
+			log.LoggerWithContext(ctx, s.logger).Warn("failed to reload patched servers for events", zap.Error(err))
+			return
+		}
+		for _, server := range patched {
+			s.publish(ctx, dto.NewServerEvent(dto.ServerEventUpdated, server))
+		}
+	default:
+		changedAt := time.Now()
+		for _, server := range servers {
+			if server.Status == *params.Status {
+				continue
+			}
+			updated := *server
+			updated.Status = *params.Status
+			if s.notifier != nil {
+				s.notifier.StatusChanged(ctx, dto.NewStatusChangedEvent(&updated, server.Status, changedAt))
+			}
+			event := dto.NewServerEvent(dto.ServerEventStatusChanged, &updated)
+			event.PreviousStatus = server.Status
+			event.OccurredAt = changedAt
+			s.publish(ctx, event)
+		}
+	}
+}
+
+// applyBulkPatch returns the server as it would look after a patch, so the
+// caller's constraints can be checked against the new values.
+func applyBulkPatch(server entity.Server, params dto.BulkOperationParams) *entity.Server {
+	if params.Operation != dto.BulkOperationPatch || params.Patch == nil {
+		return &server
+	}
+	if params.Patch.Location != nil {
+		server.Location = *params.Patch.Location
+	}
+	if params.Patch.OS != nil {
+		server.OS = *params.Patch.OS
+	}
+	return &server
+}
+
+func isEmptyPatch(patch *dto.BulkPatch) bool {
+	return patch == nil || (patch.Location == nil && patch.OS == nil && patch.IntervalTime == nil && len(patch.Labels) == 0)
+}
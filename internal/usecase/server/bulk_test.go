@@ -0,0 +1,147 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/authz"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/metrics"
+	"go.uber.org/zap"
+)
+
+func bulkResults(resp *dto.BulkOperationResponse) map[string]dto.BulkResult {
+	results := make(map[string]dto.BulkResult, len(resp.Results))
+	for _, r := range resp.Results {
+		results[r.ServerID] = r.Result
+	}
+	return results
+}
+
+func TestBulkOperation_ByIDs(t *testing.T) {
+	var deleted []string
+	r := &mockRepo{
+		getByIDsFn: func(ctx context.Context, ids []string) ([]*entity.Server, error) {
+			return []*entity.Server{{ServerID: "a", Location: "HN"}, {ServerID: "b", Location: "HCM"}}, nil
+		},
+		bulkDeleteFn: func(ctx context.Context, ids []string) (int64, error) {
+			deleted = ids
+			return int64(len(ids)), nil
+		},
+	}
+	ctx := authz.NewContext(context.Background(), &authz.Principal{
+		Constraints: authz.Constraints{authz.ConstraintLocation: {"HN"}},
+	})
+
+	resp, err := newUseCase(r, &mockXLSX{}).BulkOperation(ctx, dto.BulkOperationParams{
+		ServerIDs: []string{"a", "b", "c", "a"},
+		Operation: dto.BulkOperationDelete,
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	results := bulkResults(resp)
+	if results["a"] != dto.BulkResultApplied || results["b"] != dto.BulkResultForbidden || results["c"] != dto.BulkResultNotFound {
+		t.Fatalf("unexpected results: %+v", resp.Results)
+	}
+	if len(deleted) != 1 || deleted[0] != "a" || resp.Applied != 1 {
+		t.Fatalf("want only a deleted, got %v (applied %d)", deleted, resp.Applied)
+	}
+}
+
+func TestBulkOperation_DryRunByFilter(t *testing.T) {
+	r := &mockRepo{
+		getServersFn: func(ctx context.Context, f dto.ServerFilterOptions, p dto.ServerPaginationOptions) ([]*entity.Server, int, error) {
+			return []*entity.Server{{ServerID: "a"}, {ServerID: "b"}}, 2, nil
+		},
+		bulkUpdateStatFn: func(ctx context.Context, ids []string, st entity.ServerStatus) (int64, error) {
+			t.Fatalf("dry run must not write")
+			return 0, nil
+		},
+	}
+	status := entity.ServerStatusOffline
+	resp, err := newUseCase(r, &mockXLSX{}).BulkOperation(context.Background(), dto.BulkOperationParams{
+		Filter:    &dto.ServerFilterOptions{},
+		Operation: dto.BulkOperationSetStatus,
+		Status:    &status,
+		DryRun:    true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if resp.Matched != 2 || resp.Applied != 0 || bulkResults(resp)["b"] != dto.BulkResultWouldApply {
+		t.Fatalf("unexpected dry run: %+v", resp)
+	}
+}
+
+func TestBulkOperation_SetStatusNotifies(t *testing.T) {
+	r := &mockRepo{
+		getByIDsFn: func(ctx context.Context, ids []string) ([]*entity.Server, error) {
+			return []*entity.Server{
+				{ServerID: "a", Status: entity.ServerStatusOnline},
+				{ServerID: "b", Status: entity.ServerStatusOffline},
+			}, nil
+		},
+	}
+	notifier := &recordingNotifier{}
+	publisher := &recordingPublisher{}
+	uc := NewServerUseCase(r, &mockXLSX{}, notifier, publisher, metrics.NewMetrics(), zap.NewNop())
+
+	offline := entity.ServerStatusOffline
+	if _, err := uc.BulkOperation(context.Background(), dto.BulkOperationParams{
+		ServerIDs: []string{"a", "b"},
+		Operation: dto.BulkOperationSetStatus,
+		Status:    &offline,
+	}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	// b was already offline, so only a transitioned.
+	if len(notifier.events) != 1 {
+		t.Fatalf("expected one notification, got %d", len(notifier.events))
+	}
+	event := notifier.events[0]
+	if event.ServerID != "a" || event.Previous != entity.ServerStatusOnline || event.Current != entity.ServerStatusOffline || event.ChangedAt.IsZero() {
+		t.Fatalf("unexpected event %+v", event)
+	}
+	if len(publisher.events) != 1 || publisher.events[0].PreviousStatus != entity.ServerStatusOnline {
+		t.Fatalf("unexpected published events %+v", publisher.events)
+	}
+}
+
+func TestBulkOperation_Errors(t *testing.T) {
+	uc := newUseCase(&mockRepo{}, &mockXLSX{})
+
+	tooMany := make([]string, BULK_MAX_SIZE+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprint(i)
+	}
+	if _, err := uc.BulkOperation(context.Background(), dto.BulkOperationParams{ServerIDs: tooMany, Operation: dto.BulkOperationDelete}); !errors.Is(err, domain.ErrBulkLimitExceeded) {
+		t.Fatalf("want limit exceeded, got %v", err)
+	}
+
+	if _, err := uc.BulkOperation(context.Background(), dto.BulkOperationParams{ServerIDs: []string{"a"}, Operation: dto.BulkOperationPatch, Patch: &dto.BulkPatch{}}); !errors.Is(err, domain.ErrEmptyPatch) {
+		t.Fatalf("want empty patch, got %v", err)
+	}
+
+	r := &mockRepo{getServersFn: func(ctx context.Context, f dto.ServerFilterOptions, p dto.ServerPaginationOptions) ([]*entity.Server, int, error) {
+		return nil, BULK_MAX_SIZE + 1, nil
+	}}
+	if _, err := newUseCase(r, &mockXLSX{}).BulkOperation(context.Background(), dto.BulkOperationParams{Filter: &dto.ServerFilterOptions{}, Operation: dto.BulkOperationDelete}); !errors.Is(err, domain.ErrBulkLimitExceeded) {
+		t.Fatalf("want limit exceeded, got %v", err)
+	}
+
+	r2 := &mockRepo{
+		getByIDsFn: func(ctx context.Context, ids []string) ([]*entity.Server, error) {
+			return []*entity.Server{{ServerID: "a"}}, nil
+		},
+		bulkDeleteFn: func(ctx context.Context, ids []string) (int64, error) { return 0, fmt.Errorf("boom") },
+	}
+	if _, err := newUseCase(r2, &mockXLSX{}).BulkOperation(context.Background(), dto.BulkOperationParams{ServerIDs: []string{"a"}, Operation: dto.BulkOperationDelete}); !errors.Is(err, domain.ErrDatabase) {
+		t.Fatalf("want database error, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"time"
 
 	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
 )
@@ -9,11 +10,21 @@ import (
 type UseCase interface {
 	CreateServer(ctx context.Context, serverCreateRequest dto.CreateServerParams) (*dto.ServerResponse, error)
 	UpdateServer(ctx context.Context, serverID string, update dto.UpdateServerParams) (*dto.ServerResponse, error)
+	PatchServer(ctx context.Context, serverID string, patch dto.PatchServerParams) (*dto.ServerResponse, error)
 	DeleteServer(ctx context.Context, serverID string) error
+	RestoreServer(ctx context.Context, serverID string) (*dto.ServerResponse, error)
+	PurgeDeletedServers(ctx context.Context, retention time.Duration) (int64, error)
 	ViewServer(ctx context.Context, filter dto.ServerFilterOptions, pagination dto.ServerPaginationOptions) ([]*dto.ServerResponse, int, error)
 
+	BulkOperation(ctx context.Context, params dto.BulkOperationParams) (*dto.BulkOperationResponse, error)
+
 	ImportServer(ctx context.Context, filePath string) (*dto.ImportServerResponse, error)
+	// ImportTemplate returns an XLSX workbook laid out the way ImportServer expects.
+	ImportTemplate(ctx context.Context) ([]byte, error)
 	ExportServer(ctx context.Context, filter dto.ServerFilterOptions, pagination dto.ServerPaginationOptions) (string, error)
 
 	UpdateStatus(ctx context.Context, updateStatus dto.UpdateStatusMessage) error
+
+	// Drain blocks until in-flight imports have finished or ctx expires.
+	Drain(ctx context.Context) error
 }
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	"gorm.io/gorm"
+)
+
+func decodePatch(t *testing.T, body string) dto.PatchServerParams {
+	t.Helper()
+	var patch dto.PatchServerParams
+	if err := json.Unmarshal([]byte(body), &patch); err != nil {
+		t.Fatalf("decode patch: %v", err)
+	}
+	return patch
+}
+
+func TestPatchServer_MergeSemantics(t *testing.T) {
+	var saved *entity.Server
+	r := &mockRepo{
+		getByFieldFn: func(ctx context.Context, f string, v interface{}) (*entity.Server, error) {
+			if f == "server_id" {
+				return &entity.Server{
+					ServerID: "x", ServerName: "old", IPv4: "1.1.1.1", Location: "HN", OS: "linux", IntervalTime: 5,
+					Labels: entity.Labels{"env": "prod", "team": "sms"},
+				}, nil
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+		updateFn: func(ctx context.Context, s *entity.Server) error { saved = s; return nil },
+	}
+
+	patch := decodePatch(t, `{"location":null,"interval_time":10,"labels":{"team":null,"tier":"gold"}}`)
+	got, err := newUseCase(r, &mockXLSX{}).PatchServer(context.Background(), "x", patch)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if got.Location != "" || got.OS != "linux" || got.IntervalTime != 10 || got.ServerName != "old" {
+		t.Fatalf("unexpected patch result: %+v", got)
+	}
+	if len(saved.Labels) != 2 || saved.Labels["env"] != "prod" || saved.Labels["tier"] != "gold" {
+		t.Fatalf("labels not merged: %+v", saved.Labels)
+	}
+}
+
+func TestPatchServer_Validation(t *testing.T) {
+	uc := newUseCase(&mockRepo{}, &mockXLSX{})
+
+	patch := decodePatch(t, `{"server_name":"","ipv4":"300.1.1.1","interval_time":0}`)
+	_, err := uc.PatchServer(context.Background(), "x", patch)
+	var validationErr *domain.Error
+	if !errors.As(err, &validationErr) || !errors.Is(err, domain.ErrValidation) {
+		t.Fatalf("want validation error, got %v", err)
+	}
+	if len(validationErr.Fields) != 3 {
+		t.Fatalf("want 3 field errors, got %+v", validationErr.Fields)
+	}
+
+	var typed dto.PatchServerParams
+	if err := json.Unmarshal([]byte(`{"interval_time":"abc","os":"linux"}`), &typed); !errors.As(err, &validationErr) || validationErr.Fields[0].Field != "interval_time" {
+		t.Fatalf("want interval_time type error, got %v", err)
+	}
+
+	patch = decodePatch(t, `{"ipv4":null}`)
+	if _, err := uc.PatchServer(context.Background(), "x", patch); !errors.As(err, &validationErr) {
+		t.Fatalf("want validation error for null ipv4, got %v", err)
+	}
+}
+
+func TestPatchServer_IPv4Conflict(t *testing.T) {
+	r := &mockRepo{getByFieldFn: func(ctx context.Context, f string, v interface{}) (*entity.Server, error) {
+		if f == "server_id" {
+			return &entity.Server{ServerID: "x", IPv4: "1.1.1.1"}, nil
+		}
+		return &entity.Server{ServerID: "y"}, nil
+	}}
+	uc := newUseCase(r, &mockXLSX{})
+
+	if _, err := uc.PatchServer(context.Background(), "x", decodePatch(t, `{"ipv4":"2.2.2.2"}`)); !errors.Is(err, domain.ErrServerExist) {
+		t.Fatalf("want exist, got %v", err)
+	}
+
+	taken := "2.2.2.2"
+	if _, err := uc.UpdateServer(context.Background(), "x", dto.UpdateServerParams{IPv4: &taken}); !errors.Is(err, domain.ErrServerExist) {
+		t.Fatalf("want exist on PUT, got %v", err)
+	}
+}
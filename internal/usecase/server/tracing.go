@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/th1enq/ViettelSMS_ServerService/internal/usecase/server")
+
+type tracedUseCase struct {
+	next UseCase
+}
+
+// NewTracedUseCase wraps every use case method in a span named after it.
+func NewTracedUseCase(next UseCase) UseCase {
+	return &tracedUseCase{next: next}
+}
+
+func startSpan(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, attribute.String("tenant.id", tenant.FromContext(ctx)))
+	return tracer.Start(ctx, "ServerUseCase."+method, trace.WithAttributes(attrs...))
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (t *tracedUseCase) CreateServer(ctx context.Context, serverCreateRequest dto.CreateServerParams) (*dto.ServerResponse, error) {
+	ctx, span := startSpan(ctx, "CreateServer", attribute.String("server.id", serverCreateRequest.ServerID))
+	resp, err := t.next.CreateServer(ctx, serverCreateRequest)
+	endSpan(span, err)
+	return resp, err
+}
+
+func (t *tracedUseCase) UpdateServer(ctx context.Context, serverID string, update dto.UpdateServerParams) (*dto.ServerResponse, error) {
+	ctx, span := startSpan(ctx, "UpdateServer", attribute.String("server.id", serverID))
+	resp, err := t.next.UpdateServer(ctx, serverID, update)
+	endSpan(span, err)
+	return resp, err
+}
+
+func (t *tracedUseCase) PatchServer(ctx context.Context, serverID string, patch dto.PatchServerParams) (*dto.ServerResponse, error) {
+	ctx, span := startSpan(ctx, "PatchServer", attribute.String("server.id", serverID))
+	resp, err := t.next.PatchServer(ctx, serverID, patch)
+	endSpan(span, err)
+	return resp, err
+}
+
+func (t *tracedUseCase) DeleteServer(ctx context.Context, serverID string) error {
+	ctx, span := startSpan(ctx, "DeleteServer", attribute.String("server.id", serverID))
+	err := t.next.DeleteServer(ctx, serverID)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedUseCase) RestoreServer(ctx context.Context, serverID string) (*dto.ServerResponse, error) {
+	ctx, span := startSpan(ctx, "RestoreServer", attribute.String("server.id", serverID))
+	resp, err := t.next.RestoreServer(ctx, serverID)
+	endSpan(span, err)
+	return resp, err
+}
+
+func (t *tracedUseCase) PurgeDeletedServers(ctx context.Context, retention time.Duration) (int64, error) {
+	ctx, span := startSpan(ctx, "PurgeDeletedServers", attribute.String("retention", retention.String()))
+	purged, err := t.next.PurgeDeletedServers(ctx, retention)
+	span.SetAttributes(attribute.Int64("purged", purged))
+	endSpan(span, err)
+	return purged, err
+}
+
+func (t *tracedUseCase) ViewServer(ctx context.Context, filter dto.ServerFilterOptions, pagination dto.ServerPaginationOptions) ([]*dto.ServerResponse, int, error) {
+	ctx, span := startSpan(ctx, "ViewServer",
+		attribute.Int("page", pagination.Page),
+		attribute.Int("page_size", pagination.PageSize))
+	servers, total, err := t.next.ViewServer(ctx, filter, pagination)
+	span.SetAttributes(attribute.Int("total", total))
+	endSpan(span, err)
+	return servers, total, err
+}
+
+func (t *tracedUseCase) BulkOperation(ctx context.Context, params dto.BulkOperationParams) (*dto.BulkOperationResponse, error) {
+	ctx, span := startSpan(ctx, "BulkOperation",
+		attribute.String("operation", string(params.Operation)),
+		attribute.Bool("dry_run", params.DryRun))
+	resp, err := t.next.BulkOperation(ctx, params)
+	if resp != nil {
+		span.SetAttributes(attribute.Int("matched", resp.Matched), attribute.Int("applied", resp.Applied))
+	}
+	endSpan(span, err)
+	return resp, err
+}
+
+func (t *tracedUseCase) ImportServer(ctx context.Context, filePath string) (*dto.ImportServerResponse, error) {
+	ctx, span := startSpan(ctx, "ImportServer")
+	resp, err := t.next.ImportServer(ctx, filePath)
+	if resp != nil {
+		span.SetAttributes(attribute.Int("success_count", resp.SuccessCount), attribute.Int("failed_count", resp.FailedCount))
+	}
+	endSpan(span, err)
+	return resp, err
+}
+
+func (t *tracedUseCase) ImportTemplate(ctx context.Context) ([]byte, error) {
+	ctx, span := startSpan(ctx, "ImportTemplate")
+	content, err := t.next.ImportTemplate(ctx)
+	endSpan(span, err)
+	return content, err
+}
+
+func (t *tracedUseCase) ExportServer(ctx context.Context, filter dto.ServerFilterOptions, pagination dto.ServerPaginationOptions) (string, error) {
+	ctx, span := startSpan(ctx, "ExportServer")
+	filePath, err := t.next.ExportServer(ctx, filter, pagination)
+	endSpan(span, err)
+	return filePath, err
+}
+
+func (t *tracedUseCase) UpdateStatus(ctx context.Context, updateStatus dto.UpdateStatusMessage) error {
+	ctx, span := startSpan(ctx, "UpdateStatus",
+		attribute.String("server.id", updateStatus.ServerID),
+		attribute.String("server.status", string(updateStatus.Status)))
+	err := t.next.UpdateStatus(ctx, updateStatus)
+	endSpan(span, err)
+	return err
+}
+
+func (t *tracedUseCase) Drain(ctx context.Context) error {
+	return t.next.Drain(ctx)
+}
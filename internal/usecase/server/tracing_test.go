@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var (
+	spanExporter     = tracetest.NewInMemoryExporter()
+	installProviders sync.Once
+)
+
+// recordSpans routes the package tracer to an in-memory exporter. The global
+// provider can only be delegated to once, so it is installed once and reset
+// per test.
+func recordSpans(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	installProviders.Do(func() {
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(spanExporter)))
+	})
+	spanExporter.Reset()
+	return spanExporter
+}
+
+func spanAttributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value, len(span.Attributes))
+	for _, kv := range span.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestTracedUseCase_Spans(t *testing.T) {
+	exporter := recordSpans(t)
+	r := &mockRepo{
+		existByNameOrIDFn: func(ctx context.Context, id, name string) (bool, error) { return id == "taken", nil },
+	}
+	uc := NewTracedUseCase(newUseCase(r, &mockXLSX{}))
+	ctx := tenant.NewContext(context.Background(), "acme")
+
+	if _, err := uc.CreateServer(ctx, dto.CreateServerParams{ServerID: "s1", ServerName: "srv", IPv4: "1.1.1.1", IntervalTime: 5}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := uc.CreateServer(ctx, dto.CreateServerParams{ServerID: "taken", ServerName: "srv", IPv4: "1.1.1.1", IntervalTime: 5}); !errors.Is(err, domain.ErrServerExist) {
+		t.Fatalf("want exist, got %v", err)
+	}
+	if _, _, err := uc.ViewServer(ctx, dto.ServerFilterOptions{}, dto.ServerPaginationOptions{Page: 2, PageSize: 10}); err != nil {
+		t.Fatalf("view: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want 3", len(spans))
+	}
+
+	created := spans[0]
+	attrs := spanAttributes(created)
+	if created.Name != "ServerUseCase.CreateServer" || attrs["server.id"].AsString() != "s1" || attrs["tenant.id"].AsString() != "acme" {
+		t.Fatalf("unexpected create span %q %v", created.Name, attrs)
+	}
+	if created.Status.Code != codes.Unset {
+		t.Fatalf("got status %v for a successful call", created.Status)
+	}
+
+	failed := spans[1]
+	if failed.Status.Code != codes.Error || failed.Status.Description != domain.ErrServerExist.Error() {
+		t.Fatalf("got status %+v, want the catalogued error", failed.Status)
+	}
+	if len(failed.Events) != 1 || failed.Events[0].Name != "exception" {
+		t.Fatalf("want the error recorded as an exception event, got %+v", failed.Events)
+	}
+
+	viewed := spanAttributes(spans[2])
+	if spans[2].Name != "ServerUseCase.ViewServer" || viewed["page"].AsInt64() != 2 || viewed["page_size"].AsInt64() != 10 {
+		t.Fatalf("unexpected view span %q %v", spans[2].Name, viewed)
+	}
+	if _, ok := viewed["total"]; !ok {
+		t.Fatalf("want the total on the view span, got %v", viewed)
+	}
+}
@@ -9,12 +9,17 @@ import (
 	"time"
 
 	"github.com/gammazero/workerpool"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/authz"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
 	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
 	repo "github.com/th1enq/ViettelSMS_ServerService/internal/domain/repository"
 	srv "github.com/th1enq/ViettelSMS_ServerService/internal/domain/service"
-	"github.com/xuri/excelize/v2"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -22,36 +27,44 @@ import (
 const (
 	NUMBER_OF_WORKERS = 15
 	BATCH_SIZE        = 150
+	BULK_MAX_SIZE     = 500
 )
 
 type serverUseCase struct {
 	repo     repo.ServerRepository
 	excelSrv srv.XLSXService
+	notifier srv.StatusNotifier
+	events   srv.ServerEventPublisher
+	metrics  *metrics.Metrics
 	logger   *zap.Logger
+
+	// imports tracks running imports so shutdown can wait for them.
+	imports sync.WaitGroup
 }
 
+// NewServerUseCase builds the server usecase. notifier is told about status
+// transitions and events about every change; both may be nil.
 func NewServerUseCase(
 	repo repo.ServerRepository,
 	excelSrv srv.XLSXService,
+	notifier srv.StatusNotifier,
+	events srv.ServerEventPublisher,
+	metrics *metrics.Metrics,
 	logger *zap.Logger,
 ) UseCase {
 	return &serverUseCase{
 		repo:     repo,
 		excelSrv: excelSrv,
+		notifier: notifier,
+		events:   events,
+		metrics:  metrics,
 		logger:   logger,
 	}
 }
 
 func (s *serverUseCase) CreateServer(ctx context.Context, serverCreateRequest dto.CreateServerParams) (*dto.ServerResponse, error) {
-	s.logger.Info("CreateServer called", zap.Any("request", serverCreateRequest))
-
-	if exist, err := s.repo.ExistByNameOrID(ctx, serverCreateRequest.ServerID, serverCreateRequest.ServerName); err != nil {
-		s.logger.Error("failed to check server existence", zap.Error(err))
-		return nil, domain.ErrInternalServer
-	} else if exist {
-		s.logger.Warn("Server already exists", zap.String("server_id", serverCreateRequest.ServerID), zap.String("server_name", serverCreateRequest.ServerName))
-		return nil, domain.ErrServerExist
-	}
+	logger := log.LoggerWithContext(ctx, s.logger)
+	logger.Info("CreateServer called", zap.Any("request", serverCreateRequest))
 
 	server := &entity.Server{
 		ServerID:     serverCreateRequest.ServerID,
@@ -65,103 +78,204 @@ func (s *serverUseCase) CreateServer(ctx context.Context, serverCreateRequest dt
 	if serverCreateRequest.OS != nil {
 		server.OS = *serverCreateRequest.OS
 	}
+	if serverCreateRequest.Group != nil {
+		server.Group = *serverCreateRequest.Group
+	}
+	if serverCreateRequest.Labels != nil {
+		server.Labels = serverCreateRequest.Labels
+	}
+
+	if !authz.FromContext(ctx).CanAccess(server) {
+		logger.Warn("Server is outside caller constraints", zap.String("server_id", server.ServerID))
+		return nil, domain.ErrForbidden
+	}
+
+	if exist, err := s.repo.ExistByNameOrID(ctx, serverCreateRequest.ServerID, serverCreateRequest.ServerName); err != nil {
+		logger.Error("failed to check server existence", zap.Error(err))
+		return nil, domain.ErrDatabase
+	} else if exist {
+		logger.Warn("Server already exists", zap.String("server_id", serverCreateRequest.ServerID), zap.String("server_name", serverCreateRequest.ServerName))
+		return nil, domain.ErrServerExist
+	}
+	if err := s.ensureUnique(ctx, server.ServerID, "ipv4", server.IPv4); err != nil {
+		return nil, err
+	}
 
 	if err := s.repo.Create(ctx, server); err != nil {
-		s.logger.Error("failed to create server", zap.Error(err))
-		return nil, domain.ErrInternalServer
+		// A concurrent create can still win the unique index after the checks.
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			logger.Warn("Server already exists", zap.String("server_id", server.ServerID), zap.Error(err))
+			return nil, domain.ErrServerExist
+		}
+		logger.Error("failed to create server", zap.Error(err))
+		return nil, domain.ErrDatabase
 	}
-	s.logger.Info("Server created successfully", zap.Any("server", server))
+	logger.Info("Server created successfully", zap.Any("server", server))
+	s.publish(ctx, dto.NewServerEvent(dto.ServerEventCreated, server))
 	return dto.ToServerResponse(server), nil
 }
 
 func (s *serverUseCase) DeleteServer(ctx context.Context, serverID string) error {
-	s.logger.Info("DeleteServer called", zap.Any("server_id", serverID))
+	logger := log.LoggerWithContext(ctx, s.logger)
+	logger.Info("DeleteServer called", zap.Any("server_id", serverID))
 
-	if _, err := s.repo.GetByField(ctx, "server_id", serverID); err != nil {
+	server, err := s.repo.GetByField(ctx, "server_id", serverID)
+	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			s.logger.Warn("Server not found", zap.String("server_id", serverID))
+			logger.Warn("Server not found", zap.String("server_id", serverID))
 			return domain.ErrServerNotFound
 		}
-		s.logger.Error("failed to get server by ID", zap.String("server_id", serverID), zap.Error(err))
-		return domain.ErrInternalServer
+		logger.Error("failed to get server by ID", zap.String("server_id", serverID), zap.Error(err))
+		return domain.ErrDatabase
+	}
+
+	if !authz.FromContext(ctx).CanAccess(server) {
+		logger.Warn("Server is outside caller constraints", zap.String("server_id", serverID))
+		return domain.ErrForbidden
 	}
 
 	if err := s.repo.Delete(ctx, serverID); err != nil {
-		s.logger.Error("failed to delete server", zap.String("server_id", serverID))
-		return domain.ErrInternalServer
+		logger.Error("failed to delete server", zap.String("server_id", serverID))
+		return domain.ErrDatabase
 	}
 
-	s.logger.Info("Server delete successfully", zap.String("server_id", serverID))
+	logger.Info("Server delete successfully", zap.String("server_id", serverID))
+	s.publish(ctx, dto.NewServerEvent(dto.ServerEventDeleted, server))
 	return nil
 }
 
-func (s *serverUseCase) ExportServer(ctx context.Context, filter dto.ServerFilterOptions, pagination dto.ServerPaginationOptions) (string, error) {
-	s.logger.Info("ViewServer called", zap.Any("filter", filter), zap.Any("pagination", pagination))
+func (s *serverUseCase) RestoreServer(ctx context.Context, serverID string) (*dto.ServerResponse, error) {
+	logger := log.LoggerWithContext(ctx, s.logger)
+	logger.Info("RestoreServer called", zap.String("server_id", serverID))
 
-	servers, _, err := s.ViewServer(ctx, filter, pagination)
+	server, err := s.repo.GetDeletedByID(ctx, serverID)
 	if err != nil {
-		s.logger.Error("failed to get servers", zap.Error(err))
-		return "", err
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Warn("Deleted server not found", zap.String("server_id", serverID))
+			return nil, domain.ErrServerNotFound
+		}
+		logger.Error("failed to get deleted server by ID", zap.String("server_id", serverID), zap.Error(err))
+		return nil, domain.ErrDatabase
 	}
 
-	file := excelize.NewFile()
-	streamWriter, err := file.NewStreamWriter("Sheet1")
-	if err != nil {
-		s.logger.Error("failed to create stream writer for export", zap.Error(err))
-		return "", domain.ErrInternalServer
+	if !authz.FromContext(ctx).CanAccess(server) {
+		logger.Warn("Server is outside caller constraints", zap.String("server_id", serverID))
+		return nil, domain.ErrForbidden
 	}
 
-	streamWriter.SetRow("A1", []interface{}{
-		"server_id", "server_name", "IPv4", "status", "location", "os", "interval_time",
-	})
-
-	for rowIndex, server := range servers {
-		cell, _ := excelize.CoordinatesToCellName(1, rowIndex+2)
-		if err := streamWriter.SetRow(cell, []interface{}{
-			server.ServerID,
-			server.ServerName,
-			server.IPv4,
-			server.Status,
-			server.Location,
-			server.OS,
-			server.IntervalTime,
-		}); err != nil {
-			s.logger.Error("failed to write server data to export file", zap.Any("server", server), zap.Error(err))
-			return "", domain.ErrInternalServer
+	// The name and address may have been reused by a live server since the delete.
+	for field, value := range map[string]string{"server_name": server.ServerName, "ipv4": server.IPv4} {
+		if _, err := s.repo.GetByField(ctx, field, value); err == nil {
+			logger.Warn("Live server already uses restored value", zap.String("field", field), zap.String("value", value))
+			return nil, domain.ErrServerExist
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error("failed to check server existence", zap.String("field", field), zap.Error(err))
+			return nil, domain.ErrDatabase
 		}
 	}
 
-	if err := streamWriter.Flush(); err != nil {
-		s.logger.Error("failed to flush stream writer for export", zap.Error(err))
+	if err := s.repo.Restore(ctx, serverID); err != nil {
+		// A concurrent create can still take the values after the checks.
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			logger.Warn("Live server already uses restored value", zap.String("server_id", serverID), zap.Error(err))
+			return nil, domain.ErrServerExist
+		}
+		logger.Error("failed to restore server", zap.String("server_id", serverID), zap.Error(err))
+		return nil, domain.ErrDatabase
+	}
+
+	server.DeletedAt = gorm.DeletedAt{}
+	logger.Info("Server restored successfully", zap.String("server_id", serverID))
+	s.publish(ctx, dto.NewServerEvent(dto.ServerEventRestored, server))
+	return dto.ToServerResponse(server), nil
+}
+
+func (s *serverUseCase) PurgeDeletedServers(ctx context.Context, retention time.Duration) (int64, error) {
+	logger := log.LoggerWithContext(ctx, s.logger)
+	before := time.Now().Add(-retention)
+	logger.Info("PurgeDeletedServers called", zap.Time("before", before))
+
+	purged, err := s.repo.PurgeDeleted(ctx, before)
+	if err != nil {
+		logger.Error("failed to purge deleted servers", zap.Error(err))
+		return 0, domain.ErrDatabase
+	}
+
+	logger.Info("Purged deleted servers", zap.Int64("purged", purged))
+	return purged, nil
+}
+
+func (s *serverUseCase) ExportServer(ctx context.Context, filter dto.ServerFilterOptions, pagination dto.ServerPaginationOptions) (string, error) {
+	logger := log.LoggerWithContext(ctx, s.logger)
+	logger.Info("ViewServer called", zap.Any("filter", filter), zap.Any("pagination", pagination))
+
+	servers, _, err := s.ViewServer(ctx, filter, pagination)
+	if err != nil {
+		logger.Error("failed to get servers", zap.Error(err))
+		return "", err
+	}
+
+	content, err := s.excelSrv.Export(servers)
+	if err != nil {
+		logger.Error("failed to build export workbook", zap.Error(err))
 		return "", domain.ErrInternalServer
 	}
 
 	_ = os.MkdirAll("./exports", 0755)
 
 	filePath := fmt.Sprintf("./exports/servers_%d.xlsx", time.Now().Unix())
-	if err := file.SaveAs(filePath); err != nil {
-		s.logger.Error("failed to save export file", zap.String("file_path", filePath), zap.Error(err))
+	if err := os.WriteFile(filePath, content, 0644); err != nil {
+		logger.Error("failed to save export file", zap.String("file_path", filePath), zap.Error(err))
 		return "", domain.ErrInternalServer
 	}
 
-	s.logger.Info("Export file successfully", zap.String("file_path", filePath), zap.Int("total_server", len(servers)))
+	logger.Info("Export file successfully", zap.String("file_path", filePath), zap.Int("total_server", len(servers)))
 	return filePath, nil
 }
 
+func (s *serverUseCase) ImportTemplate(ctx context.Context) ([]byte, error) {
+	logger := log.LoggerWithContext(ctx, s.logger)
+	logger.Info("ImportTemplate called")
+
+	content, err := s.excelSrv.Template()
+	if err != nil {
+		logger.Error("failed to build import template", zap.Error(err))
+		return nil, domain.ErrInternalServer
+	}
+	return content, nil
+}
+
 func (s *serverUseCase) ImportServer(ctx context.Context, filePath string) (*dto.ImportServerResponse, error) {
-	s.logger.Info("ImportServer called", zap.String("filePath", filePath))
+	logger := log.LoggerWithContext(ctx, s.logger)
+	logger.Info("ImportServer called", zap.String("filePath", filePath))
+
+	s.imports.Add(1)
+	defer s.imports.Done()
 
+	_, readSpan := tracer.Start(ctx, "ImportServer.read")
 	rows, err := s.excelSrv.GetRows(filePath)
+	readSpan.SetAttributes(attribute.Int("rows", len(rows)))
+	endSpan(readSpan, err)
 	if err != nil {
 		if errors.Is(err, domain.ErrInvalidFile) {
-			s.logger.Warn("invalid file format or content", zap.String("filePath", filePath))
+			logger.Warn("invalid file format or content", zap.String("filePath", filePath))
 			return nil, domain.ErrInvalidFile
 		}
 		return nil, domain.ErrInternalServer
 	}
 
-	if len(rows) <= 2 || s.excelSrv.Validate(rows[0]) != nil {
-		s.logger.Warn("file import must contain at least 2 rows (header + data)")
+	if len(rows) > 0 {
+		// The header error names the offending column for the caller.
+		if err := s.excelSrv.Validate(rows[0]); err != nil {
+			logger.Warn("invalid import header", zap.Error(err))
+			if errors.Is(err, domain.ErrInvalidFile) {
+				return nil, err
+			}
+			return nil, domain.ErrInvalidFile
+		}
+	}
+	if len(rows) <= 2 {
+		logger.Warn("file import must contain at least 2 rows (header + data)")
 		return nil, domain.ErrInvalidFile
 	}
 
@@ -173,6 +287,8 @@ func (s *serverUseCase) ImportServer(ctx context.Context, filePath string) (*dto
 	}
 
 	allServers := make([]*entity.Server, 0)
+	principal := authz.FromContext(ctx)
+	_, parseSpan := tracer.Start(ctx, "ImportServer.parse")
 
 	for i := 1; i < len(rows); i++ {
 		row := rows[i]
@@ -183,13 +299,24 @@ func (s *serverUseCase) ImportServer(ctx context.Context, filePath string) (*dto
 			result.FailedServers = append(result.FailedServers, fmt.Sprintf("Row %d: %v", i+1, err))
 			continue
 		}
+		if !principal.CanAccess(server) {
+			result.FailedCount++
+			result.FailedServers = append(result.FailedServers, fmt.Sprintf("Row %d: %v", i+1, domain.ErrForbidden))
+			continue
+		}
 		allServers = append(allServers, server)
 	}
+	parseSpan.SetAttributes(attribute.Int("parsed", len(allServers)), attribute.Int("rejected", result.FailedCount))
+	parseSpan.End()
 
 	workerPool := workerpool.New(NUMBER_OF_WORKERS)
 	var mu sync.Mutex
 	successID := make(map[string]bool)
 
+	// Batches are not cancelled with the request: an import interrupted by a
+	// client disconnect or by shutdown would otherwise be left half applied.
+	batchCtx := context.WithoutCancel(ctx)
+
 	for i := 0; i < len(allServers); i += BATCH_SIZE {
 		end := i + BATCH_SIZE
 		if end > len(allServers) {
@@ -199,10 +326,16 @@ func (s *serverUseCase) ImportServer(ctx context.Context, filePath string) (*dto
 		batches := allServers[i:end]
 		batchCopy := batches
 
-		s.logger.Info("Processing batch", zap.Int("start", i), zap.Int("end", end))
+		logger.Info("Processing batch", zap.Int("start", i), zap.Int("end", end))
 
+		start := i
 		workerPool.Submit(func() {
-			if successIDs, err := s.repo.BatchCreate(ctx, batchCopy); err == nil {
+			ctx, batchSpan := tracer.Start(batchCtx, "ImportServer.batch",
+				trace.WithAttributes(attribute.Int("start", start), attribute.Int("size", len(batchCopy))))
+			successIDs, err := s.repo.BatchCreate(ctx, batchCopy)
+			batchSpan.SetAttributes(attribute.Int("created", len(successIDs)))
+			endSpan(batchSpan, err)
+			if err == nil {
 				mu.Lock()
 				result.SuccessCount += len(successIDs)
 				for _, id := range successIDs {
@@ -219,39 +352,47 @@ func (s *serverUseCase) ImportServer(ctx context.Context, filePath string) (*dto
 	for _, server := range allServers {
 		if !successID[server.ServerID] {
 			result.FailedServers = append(result.FailedServers, fmt.Sprintf("Existing Server ID: %s, Name: %s", server.ServerID, server.ServerName))
+			continue
 		}
+		s.publish(ctx, dto.NewServerEvent(dto.ServerEventCreated, server))
 	}
 
-	s.logger.Info("ImportServer completed", zap.Int("successCount", result.SuccessCount), zap.Int("failedCount", result.FailedCount))
+	s.metrics.ImportRows.WithLabelValues("success").Add(float64(result.SuccessCount))
+	s.metrics.ImportRows.WithLabelValues("failed").Add(float64(result.FailedCount))
+	logger.Info("ImportServer completed", zap.Int("successCount", result.SuccessCount), zap.Int("failedCount", result.FailedCount))
 	return &result, nil
 }
 
 func (s *serverUseCase) UpdateServer(ctx context.Context, serverID string, update dto.UpdateServerParams) (*dto.ServerResponse, error) {
-	s.logger.Info("UpdateServer called", zap.String("server_id", serverID), zap.Any("update", update))
+	logger := log.LoggerWithContext(ctx, s.logger)
+	logger.Info("UpdateServer called", zap.String("server_id", serverID), zap.Any("update", update))
 
 	server, err := s.repo.GetByField(ctx, "server_id", serverID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			s.logger.Warn("Server not found", zap.String("server_id", serverID))
+			logger.Warn("Server not found", zap.String("server_id", serverID))
 			return nil, domain.ErrServerNotFound
 		}
-		s.logger.Error("failed to get server by ID", zap.String("server_id", serverID), zap.Error(err))
-		return nil, domain.ErrInternalServer
+		logger.Error("failed to get server by ID", zap.String("server_id", serverID), zap.Error(err))
+		return nil, domain.ErrDatabase
+	}
+
+	principal := authz.FromContext(ctx)
+	if !principal.CanAccess(server) {
+		logger.Warn("Server is outside caller constraints", zap.String("server_id", serverID))
+		return nil, domain.ErrForbidden
 	}
 
 	if update.ServerName != nil {
-		exists, err := s.repo.GetByField(ctx, "server_name", update.ServerName)
-		if err == nil && exists != nil && exists.ServerID != server.ServerID {
-			s.logger.Warn("Server with the same name already exists", zap.String("server_name", *update.ServerName))
-			return nil, domain.ErrServerExist
-		}
-		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-			s.logger.Error("failed to check server existence by server_name", zap.String("server_name", *update.ServerName), zap.Error(err))
-			return nil, domain.ErrInternalServer
+		if err := s.ensureUnique(ctx, server.ServerID, "server_name", *update.ServerName); err != nil {
+			return nil, err
 		}
 		server.ServerName = *update.ServerName
 	}
 	if update.IPv4 != nil {
+		if err := s.ensureUnique(ctx, server.ServerID, "ipv4", *update.IPv4); err != nil {
+			return nil, err
+		}
 		server.IPv4 = *update.IPv4
 	}
 	if update.Location != nil {
@@ -260,35 +401,198 @@ func (s *serverUseCase) UpdateServer(ctx context.Context, serverID string, updat
 	if update.OS != nil {
 		server.OS = *update.OS
 	}
+	if update.Group != nil {
+		server.Group = *update.Group
+	}
+	if update.Labels != nil {
+		server.Labels = update.Labels
+	}
 	if update.IntervalTime != nil {
 		server.IntervalTime = *update.IntervalTime
 	}
 
+	if !principal.CanAccess(server) {
+		logger.Warn("Updated server would be outside caller constraints", zap.String("server_id", serverID))
+		return nil, domain.ErrForbidden
+	}
+
 	if err := s.repo.Update(ctx, server); err != nil {
-		s.logger.Error("failed to update server", zap.Any("server", server), zap.Error(err))
-		return nil, domain.ErrInternalServer
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			logger.Warn("Server with the same value already exists", zap.String("server_id", server.ServerID), zap.Error(err))
+			return nil, domain.ErrServerExist
+		}
+		logger.Error("failed to update server", zap.Any("server", server), zap.Error(err))
+		return nil, domain.ErrDatabase
+	}
+	logger.Info("Update server successfully", zap.Any("server", server))
+	s.publish(ctx, dto.NewServerEvent(dto.ServerEventUpdated, server))
+	return dto.ToServerResponse(server), nil
+}
+
+func (s *serverUseCase) PatchServer(ctx context.Context, serverID string, patch dto.PatchServerParams) (*dto.ServerResponse, error) {
+	logger := log.LoggerWithContext(ctx, s.logger)
+	logger.Info("PatchServer called", zap.String("server_id", serverID), zap.Any("patch", patch))
+
+	if err := patch.Validate(); err != nil {
+		logger.Warn("Invalid server patch", zap.String("server_id", serverID), zap.Error(err))
+		return nil, err
+	}
+
+	server, err := s.repo.GetByField(ctx, "server_id", serverID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Warn("Server not found", zap.String("server_id", serverID))
+			return nil, domain.ErrServerNotFound
+		}
+		logger.Error("failed to get server by ID", zap.String("server_id", serverID), zap.Error(err))
+		return nil, domain.ErrDatabase
+	}
+
+	principal := authz.FromContext(ctx)
+	if !principal.CanAccess(server) {
+		logger.Warn("Server is outside caller constraints", zap.String("server_id", serverID))
+		return nil, domain.ErrForbidden
+	}
+
+	if patch.ServerName.Set && patch.ServerName.Value != server.ServerName {
+		if err := s.ensureUnique(ctx, server.ServerID, "server_name", patch.ServerName.Value); err != nil {
+			return nil, err
+		}
+	}
+	if patch.IPv4.Set && patch.IPv4.Value != server.IPv4 {
+		if err := s.ensureUnique(ctx, server.ServerID, "ipv4", patch.IPv4.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	patch.Apply(server)
+
+	if !principal.CanAccess(server) {
+		logger.Warn("Patched server would be outside caller constraints", zap.String("server_id", serverID))
+		return nil, domain.ErrForbidden
 	}
-	s.logger.Info("Update server successfully", zap.Any("server", server))
+
+	if err := s.repo.Update(ctx, server); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			logger.Warn("Server with the same value already exists", zap.String("server_id", server.ServerID), zap.Error(err))
+			return nil, domain.ErrServerExist
+		}
+		logger.Error("failed to patch server", zap.Any("server", server), zap.Error(err))
+		return nil, domain.ErrDatabase
+	}
+	logger.Info("Patch server successfully", zap.Any("server", server))
+	s.publish(ctx, dto.NewServerEvent(dto.ServerEventUpdated, server))
 	return dto.ToServerResponse(server), nil
 }
 
+// ensureUnique fails with ErrServerExist when another live server already uses the value.
+func (s *serverUseCase) ensureUnique(ctx context.Context, serverID string, field string, value string) error {
+	logger := log.LoggerWithContext(ctx, s.logger)
+	exists, err := s.repo.GetByField(ctx, field, value)
+	if err == nil && exists != nil && exists.ServerID != serverID {
+		logger.Warn("Server with the same value already exists", zap.String("field", field), zap.String("value", value))
+		return domain.ErrServerExist
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Error("failed to check server existence", zap.String("field", field), zap.String("value", value), zap.Error(err))
+		return domain.ErrDatabase
+	}
+	return nil
+}
+
 func (s *serverUseCase) ViewServer(ctx context.Context, filter dto.ServerFilterOptions, pagination dto.ServerPaginationOptions) ([]*dto.ServerResponse, int, error) {
-	s.logger.Info("ViewServer called", zap.Any("filter", filter), zap.Any("pagination", pagination))
+	logger := log.LoggerWithContext(ctx, s.logger)
+	logger.Info("ViewServer called", zap.Any("filter", filter), zap.Any("pagination", pagination))
 
 	servers, total, err := s.repo.GetServers(ctx, filter, pagination)
 	if err != nil {
-		s.logger.Error("failed to get servers", zap.Error(err))
-		return nil, 0, domain.ErrInternalServer
+		logger.Error("failed to get servers", zap.Error(err))
+		return nil, 0, domain.ErrDatabase
 	}
-	s.logger.Info("Servers retrieved successfully", zap.Int("total", total), zap.Any("servers", servers))
+	logger.Info("Servers retrieved successfully", zap.Int("total", total), zap.Any("servers", servers))
 	return dto.ToServersResponse(servers), total, nil
 }
 
 func (s *serverUseCase) UpdateStatus(ctx context.Context, updateStatus dto.UpdateStatusMessage) error {
-	s.logger.Info("UpdateStatus called", zap.Any("update_status", updateStatus))
-	if err := s.repo.UpdateStatus(ctx, updateStatus.ServerID, entity.ServerStatus(updateStatus.Status)); err != nil {
-		s.logger.Error("failed to update status", zap.Error(err))
-		return domain.ErrInternalServer
+	logger := log.LoggerWithContext(ctx, s.logger)
+	logger.Info("UpdateStatus called", zap.Any("update_status", updateStatus))
+	previous, err := s.repo.UpdateStatus(ctx, updateStatus.ServerID, entity.ServerStatus(updateStatus.Status))
+	if err == nil {
+		if previous != updateStatus.Status {
+			s.statusChanged(ctx, updateStatus, previous)
+		}
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Error("failed to update status", zap.Error(err))
+		return domain.ErrDatabase
+	}
+
+	if _, err := s.repo.GetDeletedByID(ctx, updateStatus.ServerID); err == nil {
+		logger.Info("Status update for deleted server", zap.String("server_id", updateStatus.ServerID))
+		return domain.ErrServerDeleted
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Error("failed to get deleted server by ID", zap.String("server_id", updateStatus.ServerID), zap.Error(err))
+		return domain.ErrDatabase
+	}
+
+	logger.Warn("Status update for unknown server", zap.String("server_id", updateStatus.ServerID))
+	return domain.ErrServerNotFound
+}
+
+// statusChanged reports a transition to the notifier and the event publisher.
+// The server is reloaded for the attributes subscribers filter on; if that
+// fails, the event still goes out with the ID alone.
+func (s *serverUseCase) statusChanged(ctx context.Context, updateStatus dto.UpdateStatusMessage, previous entity.ServerStatus) {
+	logger := log.LoggerWithContext(ctx, s.logger)
+	logger.Info("Server status changed",
+		zap.String("server_id", updateStatus.ServerID),
+		zap.String("previous_status", string(previous)),
+		zap.String("status", string(updateStatus.Status)))
+	if s.notifier == nil && s.events == nil {
+		return
+	}
+
+	changedAt := updateStatus.Timestamp
+	if changedAt.IsZero() {
+		changedAt = time.Now()
+	}
+
+	server, err := s.repo.GetByField(ctx, "server_id", updateStatus.ServerID)
+	if err != nil {
+		logger.Warn("failed to load server for status change", zap.String("server_id", updateStatus.ServerID), zap.Error(err))
+		server = &entity.Server{TenantID: tenant.FromContext(ctx), ServerID: updateStatus.ServerID}
+	}
+	server.Status = updateStatus.Status
+	if s.notifier != nil {
+		s.notifier.StatusChanged(ctx, dto.NewStatusChangedEvent(server, previous, changedAt))
+	}
+
+	event := dto.NewServerEvent(dto.ServerEventStatusChanged, server)
+	event.PreviousStatus = previous
+	event.OccurredAt = changedAt
+	s.publish(ctx, event)
+}
+
+// publish reports a change to the event publisher, if there is one.
+func (s *serverUseCase) publish(ctx context.Context, event dto.ServerEvent) {
+	if s.events != nil {
+		s.events.ServerChanged(ctx, event)
+	}
+}
+
+// Drain waits for running imports to finish, or for ctx to expire.
+func (s *serverUseCase) Drain(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.imports.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	return nil
 }
@@ -7,16 +7,19 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/xuri/excelize/v2"
 
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/authz"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
 	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
 	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
 	repoiface "github.com/th1enq/ViettelSMS_ServerService/internal/domain/repository"
 	srv "github.com/th1enq/ViettelSMS_ServerService/internal/domain/service"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/metrics"
 	"gorm.io/gorm"
 )
 
@@ -30,7 +33,14 @@ type mockRepo struct {
 	updateFn          func(ctx context.Context, server *entity.Server) error
 	getServersFn      func(ctx context.Context, filter dto.ServerFilterOptions, pagination dto.ServerPaginationOptions) ([]*entity.Server, int, error)
 	batchCreateFn     func(ctx context.Context, servers []*entity.Server) ([]*string, error)
-	updateStatusFn    func(ctx context.Context, serverID string, status entity.ServerStatus) error
+	updateStatusFn    func(ctx context.Context, serverID string, status entity.ServerStatus) (entity.ServerStatus, error)
+	getDeletedByIDFn  func(ctx context.Context, serverID string) (*entity.Server, error)
+	restoreFn         func(ctx context.Context, serverID string) error
+	purgeDeletedFn    func(ctx context.Context, before time.Time) (int64, error)
+	getByIDsFn        func(ctx context.Context, serverIDs []string) ([]*entity.Server, error)
+	bulkDeleteFn      func(ctx context.Context, serverIDs []string) (int64, error)
+	bulkPatchFn       func(ctx context.Context, serverIDs []string, patch dto.BulkPatch) (int64, error)
+	bulkUpdateStatFn  func(ctx context.Context, serverIDs []string, status entity.ServerStatus) (int64, error)
 }
 
 func (m *mockRepo) ExistByNameOrID(ctx context.Context, serverID string, serverName string) (bool, error) {
@@ -79,12 +89,72 @@ func (m *mockRepo) BatchCreate(ctx context.Context, servers []*entity.Server) ([
 	}
 	return m.batchCreateFn(ctx, servers)
 }
-func (m *mockRepo) UpdateStatus(ctx context.Context, serverID string, status entity.ServerStatus) error {
+func (m *mockRepo) UpdateStatus(ctx context.Context, serverID string, status entity.ServerStatus) (entity.ServerStatus, error) {
 	if m.updateStatusFn == nil {
-		return nil
+		return status, nil
 	}
 	return m.updateStatusFn(ctx, serverID, status)
 }
+func (m *mockRepo) GetDeletedByID(ctx context.Context, serverID string) (*entity.Server, error) {
+	if m.getDeletedByIDFn == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return m.getDeletedByIDFn(ctx, serverID)
+}
+func (m *mockRepo) Restore(ctx context.Context, serverID string) error {
+	if m.restoreFn == nil {
+		return nil
+	}
+	return m.restoreFn(ctx, serverID)
+}
+func (m *mockRepo) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	if m.purgeDeletedFn == nil {
+		return 0, nil
+	}
+	return m.purgeDeletedFn(ctx, before)
+}
+func (m *mockRepo) CountByStatus(ctx context.Context) ([]dto.StatusCount, error) {
+	return nil, nil
+}
+func (m *mockRepo) ListActive(ctx context.Context) ([]*entity.Server, error) {
+	return nil, nil
+}
+func (m *mockRepo) ListOfflineSince(ctx context.Context, before time.Time) ([]*entity.Server, error) {
+	return nil, nil
+}
+func (m *mockRepo) CountGroupByStatus(ctx context.Context, group string) ([]dto.StatusCount, error) {
+	return nil, nil
+}
+func (m *mockRepo) GetStats(ctx context.Context, query dto.ServerStatsQuery) (*dto.ServerStats, error) {
+	return &dto.ServerStats{}, nil
+}
+func (m *mockRepo) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+func (m *mockRepo) GetByIDs(ctx context.Context, serverIDs []string) ([]*entity.Server, error) {
+	if m.getByIDsFn == nil {
+		return nil, nil
+	}
+	return m.getByIDsFn(ctx, serverIDs)
+}
+func (m *mockRepo) BulkDelete(ctx context.Context, serverIDs []string) (int64, error) {
+	if m.bulkDeleteFn == nil {
+		return int64(len(serverIDs)), nil
+	}
+	return m.bulkDeleteFn(ctx, serverIDs)
+}
+func (m *mockRepo) BulkPatch(ctx context.Context, serverIDs []string, patch dto.BulkPatch) (int64, error) {
+	if m.bulkPatchFn == nil {
+		return int64(len(serverIDs)), nil
+	}
+	return m.bulkPatchFn(ctx, serverIDs, patch)
+}
+func (m *mockRepo) BulkUpdateStatus(ctx context.Context, serverIDs []string, status entity.ServerStatus) (int64, error) {
+	if m.bulkUpdateStatFn == nil {
+		return int64(len(serverIDs)), nil
+	}
+	return m.bulkUpdateStatFn(ctx, serverIDs, status)
+}
 
 var _ repoiface.ServerRepository = (*mockRepo)(nil)
 
@@ -113,10 +183,18 @@ func (m *mockXLSX) Parse(row []string) (*entity.Server, error) {
 	return m.parseFn(row)
 }
 
+func (m *mockXLSX) Template() ([]byte, error) {
+	return []byte("template"), nil
+}
+
+func (m *mockXLSX) Export(servers []*dto.ServerResponse) ([]byte, error) {
+	return []byte("export"), nil
+}
+
 var _ srv.XLSXService = (*mockXLSX)(nil)
 
 func newUseCase(r repoiface.ServerRepository, x srv.XLSXService) UseCase {
-	return NewServerUseCase(r, x, zap.NewNop())
+	return NewServerUseCase(r, x, nil, nil, metrics.NewMetrics(), zap.NewNop())
 }
 
 // --- Tests ---
@@ -146,8 +224,8 @@ func TestCreateServer_ExistOrRepoErr(t *testing.T) {
 
 	r2 := &mockRepo{existByNameOrIDFn: func(ctx context.Context, id, name string) (bool, error) { return false, fmt.Errorf("boom") }}
 	uc2 := newUseCase(r2, &mockXLSX{})
-	if _, err := uc2.CreateServer(context.Background(), dto.CreateServerParams{ServerID: "a", ServerName: "b", IPv4: "1.1.1.1", IntervalTime: 1}); !errors.Is(err, domain.ErrInternalServer) {
-		t.Fatalf("want ErrInternalServer got %v", err)
+	if _, err := uc2.CreateServer(context.Background(), dto.CreateServerParams{ServerID: "a", ServerName: "b", IPv4: "1.1.1.1", IntervalTime: 1}); !errors.Is(err, domain.ErrDatabase) {
+		t.Fatalf("want ErrDatabase got %v", err)
 	}
 }
 
@@ -157,8 +235,32 @@ func TestCreateServer_RepoCreateError(t *testing.T) {
 		createFn:          func(ctx context.Context, server *entity.Server) error { return fmt.Errorf("boom") },
 	}
 	uc := newUseCase(r, &mockXLSX{})
-	if _, err := uc.CreateServer(context.Background(), dto.CreateServerParams{ServerID: "s1", ServerName: "srv", IPv4: "1.1.1.1", IntervalTime: 5}); !errors.Is(err, domain.ErrInternalServer) {
-		t.Fatalf("want internal, got %v", err)
+	if _, err := uc.CreateServer(context.Background(), dto.CreateServerParams{ServerID: "s1", ServerName: "srv", IPv4: "1.1.1.1", IntervalTime: 5}); !errors.Is(err, domain.ErrDatabase) {
+		t.Fatalf("want database error, got %v", err)
+	}
+}
+
+func TestCreateServer_IPv4Conflict(t *testing.T) {
+	r := &mockRepo{
+		existByNameOrIDFn: func(ctx context.Context, id, name string) (bool, error) { return false, nil },
+		getByFieldFn: func(ctx context.Context, field string, value interface{}) (*entity.Server, error) {
+			if field == "ipv4" {
+				return &entity.Server{ServerID: "other", IPv4: "1.1.1.1"}, nil
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+	uc := newUseCase(r, &mockXLSX{})
+	req := dto.CreateServerParams{ServerID: "s1", ServerName: "srv", IPv4: "1.1.1.1", IntervalTime: 5}
+	if _, err := uc.CreateServer(context.Background(), req); !errors.Is(err, domain.ErrServerExist) {
+		t.Fatalf("want ErrServerExist for a taken ipv4, got %v", err)
+	}
+
+	// A create racing past the checks hits the unique index instead.
+	r.getByFieldFn = nil
+	r.createFn = func(ctx context.Context, server *entity.Server) error { return gorm.ErrDuplicatedKey }
+	if _, err := uc.CreateServer(context.Background(), req); !errors.Is(err, domain.ErrServerExist) {
+		t.Fatalf("want ErrServerExist for a unique violation, got %v", err)
 	}
 }
 
@@ -176,8 +278,8 @@ func TestDeleteServer(t *testing.T) {
 		return nil, fmt.Errorf("boom")
 	}}
 	uc2 := newUseCase(r2, &mockXLSX{})
-	if err := uc2.DeleteServer(context.Background(), "x"); !errors.Is(err, domain.ErrInternalServer) {
-		t.Fatalf("want internal, got %v", err)
+	if err := uc2.DeleteServer(context.Background(), "x"); !errors.Is(err, domain.ErrDatabase) {
+		t.Fatalf("want database error, got %v", err)
 	}
 	// success delete
 	r3 := &mockRepo{getByFieldFn: func(ctx context.Context, f string, v interface{}) (*entity.Server, error) {
@@ -193,8 +295,8 @@ func TestDeleteServer(t *testing.T) {
 		return &entity.Server{ServerID: "x"}, nil
 	}, deleteFn: func(ctx context.Context, id string) error { return fmt.Errorf("boom") }}
 	uc4 := newUseCase(r4, &mockXLSX{})
-	if err := uc4.DeleteServer(context.Background(), "x"); !errors.Is(err, domain.ErrInternalServer) {
-		t.Fatalf("want internal, got %v", err)
+	if err := uc4.DeleteServer(context.Background(), "x"); !errors.Is(err, domain.ErrDatabase) {
+		t.Fatalf("want database error, got %v", err)
 	}
 }
 
@@ -231,13 +333,13 @@ func TestViewServer_ErrorAndExport_ErrorFromView(t *testing.T) {
 		return nil, 0, fmt.Errorf("boom")
 	}}
 	uc1 := newUseCase(r1, &mockXLSX{})
-	if _, _, err := uc1.ViewServer(context.Background(), dto.ServerFilterOptions{}, dto.ServerPaginationOptions{}); !errors.Is(err, domain.ErrInternalServer) {
-		t.Fatalf("want internal, got %v", err)
+	if _, _, err := uc1.ViewServer(context.Background(), dto.ServerFilterOptions{}, dto.ServerPaginationOptions{}); !errors.Is(err, domain.ErrDatabase) {
+		t.Fatalf("want database error, got %v", err)
 	}
 
 	// Export surfaces error from ViewServer
-	if _, err := uc1.ExportServer(context.Background(), dto.ServerFilterOptions{}, dto.ServerPaginationOptions{}); !errors.Is(err, domain.ErrInternalServer) {
-		t.Fatalf("want internal, got %v", err)
+	if _, err := uc1.ExportServer(context.Background(), dto.ServerFilterOptions{}, dto.ServerPaginationOptions{}); !errors.Is(err, domain.ErrDatabase) {
+		t.Fatalf("want database error, got %v", err)
 	}
 }
 
@@ -313,8 +415,8 @@ func TestUpdateServer(t *testing.T) {
 		return nil, fmt.Errorf("boom")
 	}}
 	uc2 := newUseCase(r2, &mockXLSX{})
-	if _, err := uc2.UpdateServer(context.Background(), "x", dto.UpdateServerParams{}); !errors.Is(err, domain.ErrInternalServer) {
-		t.Fatalf("want internal, got %v", err)
+	if _, err := uc2.UpdateServer(context.Background(), "x", dto.UpdateServerParams{}); !errors.Is(err, domain.ErrDatabase) {
+		t.Fatalf("want database error, got %v", err)
 	}
 
 	// name exists on other
@@ -342,8 +444,8 @@ func TestUpdateServer(t *testing.T) {
 		},
 	}
 	uc4 := newUseCase(r4, &mockXLSX{})
-	if _, err := uc4.UpdateServer(context.Background(), "x", dto.UpdateServerParams{ServerName: &newName}); !errors.Is(err, domain.ErrInternalServer) {
-		t.Fatalf("want internal, got %v", err)
+	if _, err := uc4.UpdateServer(context.Background(), "x", dto.UpdateServerParams{ServerName: &newName}); !errors.Is(err, domain.ErrDatabase) {
+		t.Fatalf("want database error, got %v", err)
 	}
 
 	// success update
@@ -369,14 +471,17 @@ func TestUpdateServer(t *testing.T) {
 		updateFn: func(ctx context.Context, s *entity.Server) error { return fmt.Errorf("boom") },
 	}
 	uc6 := newUseCase(r6, &mockXLSX{})
-	if _, err := uc6.UpdateServer(context.Background(), "x", dto.UpdateServerParams{ServerName: &newName}); !errors.Is(err, domain.ErrInternalServer) {
-		t.Fatalf("want internal, got %v", err)
+	if _, err := uc6.UpdateServer(context.Background(), "x", dto.UpdateServerParams{ServerName: &newName}); !errors.Is(err, domain.ErrDatabase) {
+		t.Fatalf("want database error, got %v", err)
 	}
 }
 
 func TestUpdateStatus(t *testing.T) {
 	called := false
-	r := &mockRepo{updateStatusFn: func(ctx context.Context, id string, st entity.ServerStatus) error { called = true; return nil }}
+	r := &mockRepo{updateStatusFn: func(ctx context.Context, id string, st entity.ServerStatus) (entity.ServerStatus, error) {
+		called = true
+		return st, nil
+	}}
 	uc := newUseCase(r, &mockXLSX{})
 	if err := uc.UpdateStatus(context.Background(), dto.UpdateStatusMessage{ServerID: "x", Status: entity.ServerStatusOnline}); err != nil {
 		t.Fatalf("unexpected: %v", err)
@@ -385,10 +490,211 @@ func TestUpdateStatus(t *testing.T) {
 		t.Fatalf("update not called")
 	}
 
-	r2 := &mockRepo{updateStatusFn: func(ctx context.Context, id string, st entity.ServerStatus) error { return fmt.Errorf("boom") }}
+	r2 := &mockRepo{updateStatusFn: func(ctx context.Context, id string, st entity.ServerStatus) (entity.ServerStatus, error) {
+		return "", fmt.Errorf("boom")
+	}}
+	uc2 := newUseCase(r2, &mockXLSX{})
+	if err := uc2.UpdateStatus(context.Background(), dto.UpdateStatusMessage{ServerID: "x", Status: entity.ServerStatusOnline}); !errors.Is(err, domain.ErrDatabase) {
+		t.Fatalf("want database error, got %v", err)
+	}
+}
+
+type recordingNotifier struct {
+	events []dto.StatusChangedEvent
+}
+
+func (n *recordingNotifier) StatusChanged(ctx context.Context, event dto.StatusChangedEvent) {
+	n.events = append(n.events, event)
+}
+
+type recordingPublisher struct {
+	events []dto.ServerEvent
+}
+
+func (p *recordingPublisher) ServerChanged(ctx context.Context, event dto.ServerEvent) {
+	p.events = append(p.events, event)
+}
+
+func TestUpdateStatus_NotifiesTransitions(t *testing.T) {
+	previous := entity.ServerStatusOnline
+	r := &mockRepo{
+		updateStatusFn: func(ctx context.Context, id string, st entity.ServerStatus) (entity.ServerStatus, error) {
+			replaced := previous
+			previous = st
+			return replaced, nil
+		},
+		getByFieldFn: func(ctx context.Context, field string, value interface{}) (*entity.Server, error) {
+			return &entity.Server{ServerID: "x", Group: "web", Status: entity.ServerStatusOnline}, nil
+		},
+	}
+	notifier := &recordingNotifier{}
+	publisher := &recordingPublisher{}
+	uc := NewServerUseCase(r, &mockXLSX{}, notifier, publisher, metrics.NewMetrics(), zap.NewNop())
+
+	for _, status := range []entity.ServerStatus{entity.ServerStatusOnline, entity.ServerStatusOffline, entity.ServerStatusOffline} {
+		if err := uc.UpdateStatus(context.Background(), dto.UpdateStatusMessage{ServerID: "x", Status: status}); err != nil {
+			t.Fatalf("unexpected: %v", err)
+		}
+	}
+
+	if len(notifier.events) != 1 {
+		t.Fatalf("expected one transition, got %d", len(notifier.events))
+	}
+	event := notifier.events[0]
+	if event.Previous != entity.ServerStatusOnline || event.Current != entity.ServerStatusOffline || event.Group != "web" || event.ChangedAt.IsZero() {
+		t.Fatalf("unexpected event %+v", event)
+	}
+
+	if len(publisher.events) != 1 {
+		t.Fatalf("expected one published event, got %d", len(publisher.events))
+	}
+	published := publisher.events[0]
+	if published.Type != dto.ServerEventStatusChanged || published.PreviousStatus != entity.ServerStatusOnline || published.Server.Status != entity.ServerStatusOffline {
+		t.Fatalf("unexpected published event %+v", published)
+	}
+}
+
+func TestUpdateStatus_MissingServer(t *testing.T) {
+	notFound := func(ctx context.Context, id string, st entity.ServerStatus) (entity.ServerStatus, error) {
+		return "", gorm.ErrRecordNotFound
+	}
+
+	// soft-deleted server
+	r1 := &mockRepo{
+		updateStatusFn: notFound,
+		getDeletedByIDFn: func(ctx context.Context, id string) (*entity.Server, error) {
+			return &entity.Server{ServerID: id}, nil
+		},
+	}
+	if err := newUseCase(r1, &mockXLSX{}).UpdateStatus(context.Background(), dto.UpdateStatusMessage{ServerID: "x"}); !errors.Is(err, domain.ErrServerDeleted) {
+		t.Fatalf("want deleted, got %v", err)
+	}
+
+	// unknown server
+	r2 := &mockRepo{updateStatusFn: notFound}
+	if err := newUseCase(r2, &mockXLSX{}).UpdateStatus(context.Background(), dto.UpdateStatusMessage{ServerID: "x"}); !errors.Is(err, domain.ErrServerNotFound) {
+		t.Fatalf("want not found, got %v", err)
+	}
+}
+
+func TestRestoreServer(t *testing.T) {
+	deleted := func(ctx context.Context, id string) (*entity.Server, error) {
+		return &entity.Server{ServerID: id, ServerName: "srv", IPv4: "1.1.1.1", DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}}, nil
+	}
+
+	// nothing to restore
+	if _, err := newUseCase(&mockRepo{}, &mockXLSX{}).RestoreServer(context.Background(), "x"); !errors.Is(err, domain.ErrServerNotFound) {
+		t.Fatalf("want not found, got %v", err)
+	}
+
+	// name reused by a live server
+	r2 := &mockRepo{
+		getDeletedByIDFn: deleted,
+		getByFieldFn: func(ctx context.Context, f string, v interface{}) (*entity.Server, error) {
+			return &entity.Server{ServerID: "other"}, nil
+		},
+	}
+	if _, err := newUseCase(r2, &mockXLSX{}).RestoreServer(context.Background(), "x"); !errors.Is(err, domain.ErrServerExist) {
+		t.Fatalf("want exist, got %v", err)
+	}
+
+	// values taken by a concurrent create after the checks
+	r3 := &mockRepo{
+		getDeletedByIDFn: deleted,
+		restoreFn:        func(ctx context.Context, id string) error { return gorm.ErrDuplicatedKey },
+	}
+	if _, err := newUseCase(r3, &mockXLSX{}).RestoreServer(context.Background(), "x"); !errors.Is(err, domain.ErrServerExist) {
+		t.Fatalf("want exist, got %v", err)
+	}
+
+	// success
+	restored := false
+	r4 := &mockRepo{
+		getDeletedByIDFn: deleted,
+		restoreFn:        func(ctx context.Context, id string) error { restored = true; return nil },
+	}
+	got, err := newUseCase(r4, &mockXLSX{}).RestoreServer(context.Background(), "x")
+	if err != nil || !restored {
+		t.Fatalf("unexpected restore result: restored=%v err=%v", restored, err)
+	}
+	if got.DeletedAt != nil {
+		t.Fatalf("restored server still marked deleted: %+v", got)
+	}
+}
+
+func TestPurgeDeletedServers(t *testing.T) {
+	var cutoff time.Time
+	r := &mockRepo{purgeDeletedFn: func(ctx context.Context, before time.Time) (int64, error) {
+		cutoff = before
+		return 3, nil
+	}}
+	purged, err := newUseCase(r, &mockXLSX{}).PurgeDeletedServers(context.Background(), 24*time.Hour)
+	if err != nil || purged != 3 {
+		t.Fatalf("unexpected purge result: purged=%d err=%v", purged, err)
+	}
+	if time.Since(cutoff) < 24*time.Hour {
+		t.Fatalf("cutoff not applied: %v", cutoff)
+	}
+
+	r2 := &mockRepo{purgeDeletedFn: func(ctx context.Context, before time.Time) (int64, error) { return 0, fmt.Errorf("boom") }}
+	if _, err := newUseCase(r2, &mockXLSX{}).PurgeDeletedServers(context.Background(), time.Hour); !errors.Is(err, domain.ErrDatabase) {
+		t.Fatalf("want database error, got %v", err)
+	}
+}
+
+func TestConstraints_EnforcedOnMutations(t *testing.T) {
+	hn, hcm := "HN", "HCM"
+	ctx := authz.NewContext(context.Background(), &authz.Principal{
+		Scopes:      []string{"server:*"},
+		Constraints: authz.Constraints{authz.ConstraintLocation: {hn}},
+	})
+
+	// create outside allowed location
+	uc1 := newUseCase(&mockRepo{}, &mockXLSX{})
+	if _, err := uc1.CreateServer(ctx, dto.CreateServerParams{ServerID: "a", ServerName: "b", IPv4: "1.1.1.1", Location: &hcm, IntervalTime: 1}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("want forbidden, got %v", err)
+	}
+	// create inside allowed location
+	if _, err := uc1.CreateServer(ctx, dto.CreateServerParams{ServerID: "a", ServerName: "b", IPv4: "1.1.1.1", Location: &hn, IntervalTime: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// delete / update a server in another location
+	r2 := &mockRepo{getByFieldFn: func(ctx context.Context, f string, v interface{}) (*entity.Server, error) {
+		return &entity.Server{ServerID: "x", Location: hcm}, nil
+	}}
 	uc2 := newUseCase(r2, &mockXLSX{})
-	if err := uc2.UpdateStatus(context.Background(), dto.UpdateStatusMessage{ServerID: "x", Status: entity.ServerStatusOnline}); !errors.Is(err, domain.ErrInternalServer) {
-		t.Fatalf("want internal, got %v", err)
+	if err := uc2.DeleteServer(ctx, "x"); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("want forbidden, got %v", err)
+	}
+	if _, err := uc2.UpdateServer(ctx, "x", dto.UpdateServerParams{}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("want forbidden, got %v", err)
+	}
+
+	// moving an allowed server out of the allowed location
+	r3 := &mockRepo{getByFieldFn: func(ctx context.Context, f string, v interface{}) (*entity.Server, error) {
+		return &entity.Server{ServerID: "x", Location: hn}, nil
+	}}
+	uc3 := newUseCase(r3, &mockXLSX{})
+	if _, err := uc3.UpdateServer(ctx, "x", dto.UpdateServerParams{Location: &hcm}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("want forbidden, got %v", err)
+	}
+
+	// import skips rows outside the allowed location
+	x4 := &mockXLSX{
+		getRowsFn: func(file string) ([][]string, error) {
+			return [][]string{{"header"}, {"a", "HN"}, {"b", "HCM"}}, nil
+		},
+		parseFn: func(row []string) (*entity.Server, error) {
+			return &entity.Server{ServerID: row[0], ServerName: row[0], IPv4: "1.1.1.1", Location: row[1], IntervalTime: 5}, nil
+		},
+	}
+	resp, err := newUseCase(&mockRepo{}, x4).ImportServer(ctx, "file.xlsx")
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if resp.SuccessCount != 1 || resp.FailedCount != 1 {
+		t.Fatalf("want 1 success and 1 failure, got %+v", resp)
 	}
 }
 
@@ -402,3 +708,28 @@ func TestExcelizeNewFile(t *testing.T) {
 		t.Fatalf("excelize save: %v", err)
 	}
 }
+
+func TestDrain_WaitsForRunningImport(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	x := &mockXLSX{getRowsFn: func(filePath string) ([][]string, error) {
+		close(started)
+		<-release
+		return nil, domain.ErrInvalidFile
+	}}
+	uc := newUseCase(&mockRepo{}, x)
+
+	go uc.ImportServer(context.Background(), "file.xlsx")
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := uc.Drain(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded while import runs, got %v", err)
+	}
+
+	close(release)
+	if err := uc.Drain(context.Background()); err != nil {
+		t.Fatalf("unexpected error after import finished: %v", err)
+	}
+}
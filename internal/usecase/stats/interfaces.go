@@ -0,0 +1,13 @@
+package stats
+
+import (
+	"context"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+)
+
+type UseCase interface {
+	// ViewStats summarises the servers visible to the caller. Results are
+	// cached per tenant and constraints for a short while.
+	ViewStats(ctx context.Context) (*dto.ServerStats, error)
+}
@@ -0,0 +1,119 @@
+package stats
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/authz"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	repo "github.com/th1enq/ViettelSMS_ServerService/internal/domain/repository"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	"go.uber.org/zap"
+)
+
+// CHANGED_WINDOW is the period covered by changed_last_hour.
+const CHANGED_WINDOW = time.Hour
+
+type statsUseCase struct {
+	repo   repo.ServerRepository
+	config config.Stats
+	logger *zap.Logger
+
+	mu    sync.Mutex
+	cache map[string]cachedStats
+}
+
+type cachedStats struct {
+	stats   *dto.ServerStats
+	expires time.Time
+}
+
+func NewStatsUseCase(
+	repo repo.ServerRepository,
+	config *config.Config,
+	logger *zap.Logger,
+) UseCase {
+	return &statsUseCase{
+		repo:   repo,
+		config: config.Stats,
+		logger: logger,
+		cache:  make(map[string]cachedStats),
+	}
+}
+
+func (u *statsUseCase) ViewStats(ctx context.Context) (*dto.ServerStats, error) {
+	logger := log.LoggerWithContext(ctx, u.logger)
+	logger.Info("ViewStats called")
+
+	key := cacheKey(ctx)
+	now := time.Now()
+	if stats, ok := u.cached(key, now); ok {
+		logger.Debug("Serving cached server stats", zap.Time("generated_at", stats.GeneratedAt))
+		return stats, nil
+	}
+
+	stats, err := u.repo.GetStats(ctx, dto.ServerStatsQuery{
+		CreatedSince:  now.AddDate(0, 0, 1-u.config.CreatedDays).Truncate(24 * time.Hour),
+		ChangedSince:  now.Add(-CHANGED_WINDOW),
+		FlappingSince: now.Add(-u.config.FlappingWindow),
+		FlappingLimit: u.config.TopFlapping,
+	})
+	if err != nil {
+		logger.Error("failed to get server stats", zap.Error(err))
+		return nil, domain.ErrDatabase
+	}
+
+	u.store(key, stats, now)
+	logger.Info("Server stats computed", zap.Int64("total", stats.Total))
+	return stats, nil
+}
+
+func (u *statsUseCase) cached(key string, now time.Time) (*dto.ServerStats, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	entry, ok := u.cache[key]
+	if !ok || !now.Before(entry.expires) {
+		return nil, false
+	}
+	return entry.stats, true
+}
+
+func (u *statsUseCase) store(key string, stats *dto.ServerStats, now time.Time) {
+	if u.config.CacheTTL <= 0 {
+		return
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for k, entry := range u.cache {
+		if !now.Before(entry.expires) {
+			delete(u.cache, k)
+		}
+	}
+	u.cache[key] = cachedStats{stats: stats, expires: now.Add(u.config.CacheTTL)}
+}
+
+// cacheKey identifies what the caller can see: the tenant and the
+// constraints of the principal, in a stable order.
+func cacheKey(ctx context.Context) string {
+	parts := []string{tenant.FromContext(ctx)}
+	if principal := authz.FromContext(ctx); principal != nil {
+		constraints := make([]string, 0, len(principal.Constraints))
+		for key, allowed := range principal.Constraints {
+			values := slices.Clone(allowed)
+			slices.Sort(values)
+			constraints = append(constraints, key+"="+strings.Join(values, ","))
+		}
+		slices.Sort(constraints)
+		parts = append(parts, constraints...)
+	}
+	return strings.Join(parts, ";")
+}
@@ -0,0 +1,85 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/authz"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	repoiface "github.com/th1enq/ViettelSMS_ServerService/internal/domain/repository"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	"go.uber.org/zap"
+)
+
+type mockRepo struct {
+	repoiface.ServerRepository
+	calls   int
+	queries []dto.ServerStatsQuery
+	err     error
+}
+
+func (m *mockRepo) GetStats(ctx context.Context, query dto.ServerStatsQuery) (*dto.ServerStats, error) {
+	m.calls++
+	m.queries = append(m.queries, query)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &dto.ServerStats{Total: int64(m.calls), GeneratedAt: time.Now()}, nil
+}
+
+func newUseCase(r repoiface.ServerRepository, ttl time.Duration) UseCase {
+	return NewStatsUseCase(r, &config.Config{Stats: config.Stats{
+		CacheTTL:       ttl,
+		CreatedDays:    30,
+		FlappingWindow: 24 * time.Hour,
+		TopFlapping:    10,
+	}}, zap.NewNop())
+}
+
+func TestViewStats_CachesPerTenantAndConstraints(t *testing.T) {
+	r := &mockRepo{}
+	u := newUseCase(r, time.Minute)
+
+	acme := tenant.NewContext(context.Background(), "acme")
+	hanoi := authz.NewContext(acme, &authz.Principal{Constraints: authz.Constraints{authz.ConstraintLocation: {"HN"}}})
+	globex := tenant.NewContext(context.Background(), "globex")
+
+	for _, ctx := range []context.Context{acme, acme, hanoi, hanoi, globex} {
+		if _, err := u.ViewStats(ctx); err != nil {
+			t.Fatalf("unexpected: %v", err)
+		}
+	}
+	if r.calls != 3 {
+		t.Fatalf("expected one query per tenant and constraints, got %d", r.calls)
+	}
+
+	query := r.queries[0]
+	if query.FlappingLimit != 10 || time.Since(query.ChangedSince) < CHANGED_WINDOW || time.Since(query.CreatedSince) < 29*24*time.Hour {
+		t.Fatalf("unexpected query %+v", query)
+	}
+}
+
+func TestViewStats_ExpiresCache(t *testing.T) {
+	r := &mockRepo{}
+	u := newUseCase(r, 0)
+
+	for i := 0; i < 2; i++ {
+		if _, err := u.ViewStats(context.Background()); err != nil {
+			t.Fatalf("unexpected: %v", err)
+		}
+	}
+	if r.calls != 2 {
+		t.Fatalf("expected no caching without a TTL, got %d queries", r.calls)
+	}
+}
+
+func TestViewStats_RepositoryError(t *testing.T) {
+	u := newUseCase(&mockRepo{err: errors.New("boom")}, time.Minute)
+	if _, err := u.ViewStats(context.Background()); !errors.Is(err, domain.ErrDatabase) {
+		t.Fatalf("want database, got %v", err)
+	}
+}
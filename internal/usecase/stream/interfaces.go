@@ -0,0 +1,24 @@
+package stream
+
+import (
+	"context"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+)
+
+type UseCase interface {
+	// ServerChanged publishes the event to every replica and returns
+	// immediately. Events are published in the order they are reported.
+	ServerChanged(ctx context.Context, event dto.ServerEvent)
+	// Broadcast hands an event received from another replica, or from this
+	// one, to the local subscribers allowed to see it.
+	Broadcast(ctx context.Context, event dto.ServerEvent)
+	// Subscribe registers a subscriber restricted to the tenant and the
+	// constraints of the principal in ctx.
+	Subscribe(ctx context.Context, filter dto.ServerFilterOptions) (*Subscription, error)
+
+	// Close ends every subscription and refuses new ones.
+	Close()
+	// Drain waits for queued events to be published until ctx expires.
+	Drain(ctx context.Context) error
+}
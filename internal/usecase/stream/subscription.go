@@ -0,0 +1,62 @@
+package stream
+
+import (
+	"sync"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/authz"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+)
+
+// Subscription receives the events matching its filter. Events is closed when
+// the subscription ends: on Unsubscribe, on shutdown, or when the subscriber
+// falls too far behind, in which case Lagged reports true.
+type Subscription struct {
+	Events <-chan dto.ServerEvent
+
+	events    chan dto.ServerEvent
+	tenantID  string
+	principal *authz.Principal
+	filter    dto.ServerFilterOptions
+
+	once   sync.Once
+	lagged bool
+	remove func(*Subscription)
+}
+
+func (s *Subscription) Unsubscribe() {
+	s.remove(s)
+}
+
+// Lagged reports whether the subscription was ended for not keeping up.
+func (s *Subscription) Lagged() bool {
+	return s.lagged
+}
+
+// matches reports whether the subscriber may see the event and asked for it.
+// A status change also matches when the server matched before it, so the
+// subscriber learns that the server left its view.
+func (s *Subscription) matches(event dto.ServerEvent) bool {
+	if event.TenantID != s.tenantID {
+		return false
+	}
+	server := event.Entity()
+	if !s.principal.CanAccess(server) {
+		return false
+	}
+	if s.filter.Matches(server) {
+		return true
+	}
+	if event.Type == dto.ServerEventStatusChanged && event.PreviousStatus != "" {
+		server.Status = event.PreviousStatus
+		return s.filter.Matches(server)
+	}
+	return false
+}
+
+// close ends the subscription once; callers hold the registry lock.
+func (s *Subscription) close(lagged bool) {
+	s.once.Do(func() {
+		s.lagged = lagged
+		close(s.events)
+	})
+}
@@ -0,0 +1,149 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/authz"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/mq"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/queue"
+	"go.uber.org/zap"
+)
+
+type streamUseCase struct {
+	broker mq.Publisher
+	config config.Stream
+	logger *zap.Logger
+
+	// publisher runs on a single worker so that events leave in order.
+	publisher *queue.Queue
+
+	subMu       sync.Mutex
+	subsClosed  bool
+	subscribers map[*Subscription]struct{}
+}
+
+// NewStreamUseCase builds the stream usecase. Without a broker, events are
+// only broadcast to the subscribers of this replica.
+func NewStreamUseCase(
+	broker mq.Publisher,
+	config *config.Config,
+	logger *zap.Logger,
+) UseCase {
+	return &streamUseCase{
+		broker:      broker,
+		config:      config.Stream,
+		logger:      logger,
+		publisher:   queue.New(1),
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+func (u *streamUseCase) ServerChanged(ctx context.Context, event dto.ServerEvent) {
+	ctx = context.WithoutCancel(ctx)
+	if event.ID == "" {
+		event.ID = uuid.NewString()
+	}
+	if u.broker == nil {
+		u.Broadcast(ctx, event)
+		return
+	}
+
+	if !u.publisher.Submit(func() { u.publish(ctx, event) }) {
+		log.LoggerWithContext(ctx, u.logger).Warn("Stream draining, server event not published",
+			zap.String("event_id", event.ID), zap.String("type", string(event.Type)))
+	}
+}
+
+func (u *streamUseCase) Broadcast(ctx context.Context, event dto.ServerEvent) {
+	u.subMu.Lock()
+	defer u.subMu.Unlock()
+
+	for subscription := range u.subscribers {
+		if !subscription.matches(event) {
+			continue
+		}
+		select {
+		case subscription.events <- event:
+		default:
+			log.LoggerWithContext(ctx, u.logger).Warn("Stream subscriber lagging, disconnecting",
+				zap.String("tenant_id", subscription.tenantID))
+			delete(u.subscribers, subscription)
+			subscription.close(true)
+		}
+	}
+}
+
+func (u *streamUseCase) Subscribe(ctx context.Context, filter dto.ServerFilterOptions) (*Subscription, error) {
+	events := make(chan dto.ServerEvent, u.config.BufferSize)
+	subscription := &Subscription{
+		Events:    events,
+		events:    events,
+		tenantID:  tenant.FromContext(ctx),
+		principal: authz.FromContext(ctx),
+		filter:    filter,
+		remove:    u.unsubscribe,
+	}
+
+	u.subMu.Lock()
+	defer u.subMu.Unlock()
+	if u.subsClosed {
+		return nil, domain.ErrShuttingDown
+	}
+	u.subscribers[subscription] = struct{}{}
+	return subscription, nil
+}
+
+func (u *streamUseCase) Close() {
+	u.subMu.Lock()
+	defer u.subMu.Unlock()
+
+	u.subsClosed = true
+	for subscription := range u.subscribers {
+		delete(u.subscribers, subscription)
+		subscription.close(false)
+	}
+}
+
+func (u *streamUseCase) Drain(ctx context.Context) error {
+	return u.publisher.Drain(ctx)
+}
+
+func (u *streamUseCase) unsubscribe(subscription *Subscription) {
+	u.subMu.Lock()
+	defer u.subMu.Unlock()
+
+	delete(u.subscribers, subscription)
+	subscription.close(false)
+}
+
+// publish sends the event to the stream topic, keyed by server so that the
+// events of one server stay in order across partitions.
+func (u *streamUseCase) publish(ctx context.Context, event dto.ServerEvent) {
+	logger := log.LoggerWithContext(ctx, u.logger)
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		logger.Error("failed to marshal server event", zap.Error(err))
+		return
+	}
+
+	key := event.TenantID
+	if event.Server != nil {
+		key += "/" + event.Server.ServerID
+	}
+	if err := u.broker.Send(tenant.NewContext(ctx, event.TenantID), mq.Message{
+		Topic: u.config.Topic,
+		Key:   key,
+		Body:  body,
+	}); err != nil {
+		logger.Error("failed to publish server event", zap.String("event_id", event.ID), zap.Error(err))
+	}
+}
@@ -0,0 +1,136 @@
+package stream
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/authz"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	"go.uber.org/zap"
+)
+
+func newUseCase(bufferSize int) UseCase {
+	return NewStreamUseCase(nil, &config.Config{Stream: config.Stream{BufferSize: bufferSize}}, zap.NewNop())
+}
+
+func subscribe(t *testing.T, u UseCase, tenantID string, principal *authz.Principal, filter dto.ServerFilterOptions) *Subscription {
+	t.Helper()
+	ctx := authz.NewContext(tenant.NewContext(context.Background(), tenantID), principal)
+	subscription, err := u.Subscribe(ctx, filter)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	t.Cleanup(subscription.Unsubscribe)
+	return subscription
+}
+
+func serverEvent(eventType dto.ServerEventType, tenantID, serverID, location string, status entity.ServerStatus) dto.ServerEvent {
+	return dto.NewServerEvent(eventType, &entity.Server{
+		TenantID:   tenantID,
+		ServerID:   serverID,
+		ServerName: "web-" + serverID,
+		Location:   location,
+		Status:     status,
+	})
+}
+
+// received returns the IDs of the servers whose events are waiting.
+func received(subscription *Subscription) []string {
+	var ids []string
+	for {
+		select {
+		case event, ok := <-subscription.Events:
+			if !ok {
+				return ids
+			}
+			ids = append(ids, event.Server.ServerID)
+		case <-time.After(10 * time.Millisecond):
+			return ids
+		}
+	}
+}
+
+func TestBroadcast_FiltersByTenantConstraintsAndFilter(t *testing.T) {
+	u := newUseCase(10)
+	offline := entity.ServerStatusOffline
+	name := "web-1"
+
+	all := subscribe(t, u, "acme", nil, dto.ServerFilterOptions{})
+	hanoi := subscribe(t, u, "acme", &authz.Principal{Constraints: authz.Constraints{authz.ConstraintLocation: {"HN"}}}, dto.ServerFilterOptions{})
+	byStatus := subscribe(t, u, "acme", nil, dto.ServerFilterOptions{Status: &offline})
+	byName := subscribe(t, u, "acme", nil, dto.ServerFilterOptions{ServerName: &name})
+	other := subscribe(t, u, "globex", nil, dto.ServerFilterOptions{})
+
+	ctx := context.Background()
+	u.ServerChanged(ctx, serverEvent(dto.ServerEventCreated, "acme", "1", "HN", entity.ServerStatusOnline))
+	u.ServerChanged(ctx, serverEvent(dto.ServerEventUpdated, "acme", "2", "HCM", entity.ServerStatusOffline))
+	u.ServerChanged(ctx, serverEvent(dto.ServerEventDeleted, "acme", "10", "HN", entity.ServerStatusOffline))
+
+	cases := map[string]struct {
+		subscription *Subscription
+		want         []string
+	}{
+		"whole tenant":     {all, []string{"1", "2", "10"}},
+		"constraints":      {hanoi, []string{"1", "10"}},
+		"status filter":    {byStatus, []string{"2", "10"}},
+		"name filter":      {byName, []string{"1", "10"}},
+		"different tenant": {other, nil},
+	}
+	for name, tc := range cases {
+		if got := received(tc.subscription); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: received %v, want %v", name, got, tc.want)
+		}
+	}
+}
+
+func TestBroadcast_StatusChangeLeavingFilter(t *testing.T) {
+	u := newUseCase(10)
+	online := entity.ServerStatusOnline
+	subscription := subscribe(t, u, "acme", nil, dto.ServerFilterOptions{Status: &online})
+
+	event := serverEvent(dto.ServerEventStatusChanged, "acme", "1", "HN", entity.ServerStatusOffline)
+	event.PreviousStatus = entity.ServerStatusOnline
+	u.ServerChanged(context.Background(), event)
+
+	if got := received(subscription); !slices.Equal(got, []string{"1"}) {
+		t.Errorf("received %v, want the server that went offline", got)
+	}
+}
+
+func TestBroadcast_DisconnectsLaggingSubscriber(t *testing.T) {
+	u := newUseCase(1)
+	slow := subscribe(t, u, "acme", nil, dto.ServerFilterOptions{})
+
+	u.ServerChanged(context.Background(), serverEvent(dto.ServerEventCreated, "acme", "1", "HN", entity.ServerStatusOnline))
+	u.ServerChanged(context.Background(), serverEvent(dto.ServerEventCreated, "acme", "2", "HN", entity.ServerStatusOnline))
+
+	if got := received(slow); !slices.Equal(got, []string{"1"}) {
+		t.Errorf("received %v, want the buffered event before the disconnect", got)
+	}
+	if !slow.Lagged() {
+		t.Error("Lagged() = false, want true")
+	}
+}
+
+func TestClose_EndsSubscriptionsAndRefusesNewOnes(t *testing.T) {
+	u := newUseCase(10)
+	subscription := subscribe(t, u, "acme", nil, dto.ServerFilterOptions{})
+
+	u.Close()
+
+	if _, ok := <-subscription.Events; ok {
+		t.Error("Events still open after Close")
+	}
+	if subscription.Lagged() {
+		t.Error("Lagged() = true after Close, want false")
+	}
+	if _, err := u.Subscribe(context.Background(), dto.ServerFilterOptions{}); err != domain.ErrShuttingDown {
+		t.Errorf("Subscribe() error = %v, want ErrShuttingDown", err)
+	}
+}
@@ -0,0 +1,24 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+)
+
+type UseCase interface {
+	CreateWebhook(ctx context.Context, params dto.CreateWebhookParams) (*dto.WebhookResponse, error)
+	GetWebhook(ctx context.Context, id uint) (*dto.WebhookResponse, error)
+	ListWebhooks(ctx context.Context) ([]*dto.WebhookResponse, error)
+	UpdateWebhook(ctx context.Context, id uint, params dto.UpdateWebhookParams) (*dto.WebhookResponse, error)
+	DeleteWebhook(ctx context.Context, id uint) error
+	// ListDeliveries returns the most recent delivery attempts of a webhook.
+	ListDeliveries(ctx context.Context, id uint) ([]*dto.WebhookDeliveryResponse, error)
+
+	// StatusChanged queues the event for every matching webhook of its tenant
+	// and returns immediately.
+	StatusChanged(ctx context.Context, event dto.StatusChangedEvent)
+	// Drain stops accepting events and waits for queued deliveries until ctx
+	// expires. Deliveries waiting for a retry are abandoned.
+	Drain(ctx context.Context) error
+}
@@ -0,0 +1,349 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/authz"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	repo "github.com/th1enq/ViettelSMS_ServerService/internal/domain/repository"
+	srv "github.com/th1enq/ViettelSMS_ServerService/internal/domain/service"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	log "github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/logger"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/queue"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// DELIVERY_LOG_LIMIT is the number of attempts returned by ListDeliveries.
+	DELIVERY_LOG_LIMIT = 100
+	SECRET_BYTES       = 32
+)
+
+type webhookUseCase struct {
+	repo   repo.WebhookRepository
+	sender srv.WebhookSender
+	config config.Webhook
+	logger *zap.Logger
+
+	deliveries *queue.Queue
+}
+
+func NewWebhookUseCase(
+	repo repo.WebhookRepository,
+	sender srv.WebhookSender,
+	config *config.Config,
+	logger *zap.Logger,
+) UseCase {
+	return &webhookUseCase{
+		repo:       repo,
+		sender:     sender,
+		config:     config.Webhook,
+		logger:     logger,
+		deliveries: queue.New(config.Webhook.Workers),
+	}
+}
+
+func (u *webhookUseCase) CreateWebhook(ctx context.Context, params dto.CreateWebhookParams) (*dto.WebhookResponse, error) {
+	logger := log.LoggerWithContext(ctx, u.logger)
+	logger.Info("CreateWebhook called", zap.String("url", params.URL))
+
+	if err := u.checkURL(ctx, params.URL); err != nil {
+		return nil, err
+	}
+
+	webhook := &entity.Webhook{
+		URL:         params.URL,
+		ServerIDs:   params.ServerIDs,
+		Groups:      params.Groups,
+		Labels:      params.Labels,
+		Constraints: callerConstraints(ctx),
+		Enabled:     true,
+	}
+	if params.Secret != nil {
+		webhook.Secret = *params.Secret
+	} else {
+		secret, err := generateSecret()
+		if err != nil {
+			logger.Error("failed to generate webhook secret", zap.Error(err))
+			return nil, domain.ErrInternalServer
+		}
+		webhook.Secret = secret
+	}
+
+	if err := u.repo.Create(ctx, webhook); err != nil {
+		logger.Error("failed to create webhook", zap.Error(err))
+		return nil, domain.ErrDatabase
+	}
+
+	logger.Info("Webhook created successfully", zap.Uint("webhook_id", webhook.ID))
+	response := dto.ToWebhookResponse(webhook)
+	response.Secret = webhook.Secret
+	return response, nil
+}
+
+func (u *webhookUseCase) GetWebhook(ctx context.Context, id uint) (*dto.WebhookResponse, error) {
+	webhook, err := u.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToWebhookResponse(webhook), nil
+}
+
+func (u *webhookUseCase) ListWebhooks(ctx context.Context) ([]*dto.WebhookResponse, error) {
+	webhooks, err := u.repo.List(ctx)
+	if err != nil {
+		log.LoggerWithContext(ctx, u.logger).Error("failed to list webhooks", zap.Error(err))
+		return nil, domain.ErrDatabase
+	}
+	return dto.ToWebhooksResponse(webhooks), nil
+}
+
+func (u *webhookUseCase) UpdateWebhook(ctx context.Context, id uint, params dto.UpdateWebhookParams) (*dto.WebhookResponse, error) {
+	logger := log.LoggerWithContext(ctx, u.logger)
+	logger.Info("UpdateWebhook called", zap.Uint("webhook_id", id))
+
+	webhook, err := u.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.URL != nil {
+		if err := u.checkURL(ctx, *params.URL); err != nil {
+			return nil, err
+		}
+		webhook.URL = *params.URL
+	}
+	// The webhook now acts for whoever last changed it.
+	webhook.Constraints = callerConstraints(ctx)
+	if params.Secret != nil {
+		webhook.Secret = *params.Secret
+	}
+	if params.ServerIDs != nil {
+		webhook.ServerIDs = params.ServerIDs
+	}
+	if params.Groups != nil {
+		webhook.Groups = params.Groups
+	}
+	if params.Labels != nil {
+		webhook.Labels = params.Labels
+	}
+	if params.Enabled != nil {
+		if *params.Enabled && !webhook.Enabled {
+			webhook.ConsecutiveFailures = 0
+			webhook.DisabledAt = nil
+		}
+		webhook.Enabled = *params.Enabled
+	}
+
+	if err := u.repo.Update(ctx, webhook); err != nil {
+		logger.Error("failed to update webhook", zap.Uint("webhook_id", id), zap.Error(err))
+		return nil, domain.ErrDatabase
+	}
+
+	logger.Info("Webhook updated successfully", zap.Uint("webhook_id", id))
+	return dto.ToWebhookResponse(webhook), nil
+}
+
+func (u *webhookUseCase) DeleteWebhook(ctx context.Context, id uint) error {
+	logger := log.LoggerWithContext(ctx, u.logger)
+	logger.Info("DeleteWebhook called", zap.Uint("webhook_id", id))
+
+	if err := u.repo.Delete(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Warn("Webhook not found", zap.Uint("webhook_id", id))
+			return domain.ErrWebhookNotFound
+		}
+		logger.Error("failed to delete webhook", zap.Uint("webhook_id", id), zap.Error(err))
+		return domain.ErrDatabase
+	}
+
+	logger.Info("Webhook deleted successfully", zap.Uint("webhook_id", id))
+	return nil
+}
+
+func (u *webhookUseCase) ListDeliveries(ctx context.Context, id uint) ([]*dto.WebhookDeliveryResponse, error) {
+	if _, err := u.get(ctx, id); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := u.repo.ListDeliveries(ctx, id, DELIVERY_LOG_LIMIT)
+	if err != nil {
+		log.LoggerWithContext(ctx, u.logger).Error("failed to list webhook deliveries", zap.Uint("webhook_id", id), zap.Error(err))
+		return nil, domain.ErrDatabase
+	}
+	return dto.ToWebhookDeliveriesResponse(deliveries), nil
+}
+
+func (u *webhookUseCase) StatusChanged(ctx context.Context, event dto.StatusChangedEvent) {
+	ctx = tenant.NewContext(context.WithoutCancel(ctx), event.TenantID)
+	logger := log.LoggerWithContext(ctx, u.logger)
+
+	if !u.deliveries.Submit(func() { u.dispatch(ctx, event) }) {
+		logger.Warn("Webhooks draining, status change not delivered", zap.String("server_id", event.ServerID))
+	}
+}
+
+func (u *webhookUseCase) Drain(ctx context.Context) error {
+	return u.deliveries.Drain(ctx)
+}
+
+// dispatch delivers the event to every enabled webhook whose filters match.
+func (u *webhookUseCase) dispatch(ctx context.Context, event dto.StatusChangedEvent) {
+	logger := log.LoggerWithContext(ctx, u.logger)
+
+	webhooks, err := u.repo.ListEnabled(ctx)
+	if err != nil {
+		logger.Error("failed to list webhooks", zap.Error(err))
+		return
+	}
+
+	payload := dto.WebhookPayload{
+		ID:        uuid.NewString(),
+		Event:     entity.WebhookEventStatusChanged,
+		CreatedAt: time.Now(),
+		Data:      event,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		logger.Error("failed to marshal webhook payload", zap.Error(err))
+		return
+	}
+
+	server := event.Server()
+	var wg sync.WaitGroup
+	for _, webhook := range webhooks {
+		owner := &authz.Principal{Constraints: authz.Constraints(webhook.Constraints)}
+		if !webhook.Matches(server) || !owner.CanAccess(server) {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			u.deliver(ctx, webhook, payload, body)
+		}()
+	}
+	wg.Wait()
+}
+
+// deliver sends the payload until it is accepted or MaxAttempts is reached,
+// logging every attempt. A delivery that exhausts its attempts counts towards
+// disabling the webhook.
+func (u *webhookUseCase) deliver(ctx context.Context, webhook *entity.Webhook, payload dto.WebhookPayload, body []byte) {
+	logger := log.LoggerWithContext(ctx, u.logger).With(
+		zap.Uint("webhook_id", webhook.ID),
+		zap.String("event_id", payload.ID),
+		zap.String("server_id", payload.Data.ServerID))
+
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		statusCode, err := u.sender.Send(ctx, webhook.URL, webhook.Secret, payload.Event, payload.ID, body)
+
+		delivery := &entity.WebhookDelivery{
+			WebhookID:  webhook.ID,
+			EventID:    payload.ID,
+			Event:      payload.Event,
+			ServerID:   payload.Data.ServerID,
+			Attempt:    attempt,
+			StatusCode: statusCode,
+			Success:    err == nil,
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		if err != nil {
+			delivery.Error = err.Error()
+		}
+		if recordErr := u.repo.RecordDelivery(ctx, delivery); recordErr != nil {
+			logger.Error("failed to record webhook delivery", zap.Error(recordErr))
+		}
+
+		if err == nil {
+			if markErr := u.repo.MarkDelivered(ctx, webhook.ID); markErr != nil {
+				logger.Error("failed to reset webhook failures", zap.Error(markErr))
+			}
+			logger.Info("Webhook delivered", zap.Int("attempt", attempt), zap.Int("status_code", statusCode))
+			return
+		}
+
+		logger.Warn("Webhook delivery attempt failed", zap.Int("attempt", attempt), zap.Int("status_code", statusCode), zap.Error(err))
+		if attempt >= u.config.MaxAttempts {
+			break
+		}
+		select {
+		case <-u.deliveries.Closing():
+			logger.Warn("Webhook retry abandoned by shutdown", zap.Int("attempt", attempt))
+			return
+		case <-time.After(u.backoff(attempt)):
+		}
+	}
+
+	disabled, err := u.repo.MarkFailed(ctx, webhook.ID, u.config.DisableAfter)
+	if err != nil {
+		logger.Error("failed to count webhook failure", zap.Error(err))
+		return
+	}
+	if disabled {
+		logger.Warn("Webhook disabled after repeated failures", zap.Int("disable_after", u.config.DisableAfter))
+	}
+}
+
+// backoff doubles the wait after every attempt, up to MaxBackoff.
+func (u *webhookUseCase) backoff(attempt int) time.Duration {
+	wait := u.config.InitialBackoff
+	for i := 1; i < attempt && wait < u.config.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, u.config.MaxBackoff)
+}
+
+func (u *webhookUseCase) get(ctx context.Context, id uint) (*entity.Webhook, error) {
+	webhook, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.LoggerWithContext(ctx, u.logger).Warn("Webhook not found", zap.Uint("webhook_id", id))
+			return nil, domain.ErrWebhookNotFound
+		}
+		log.LoggerWithContext(ctx, u.logger).Error("failed to get webhook", zap.Uint("webhook_id", id), zap.Error(err))
+		return nil, domain.ErrDatabase
+	}
+	return webhook, nil
+}
+
+// checkURL refuses URLs that resolve to addresses deliveries may not reach.
+func (u *webhookUseCase) checkURL(ctx context.Context, url string) error {
+	if err := u.sender.CheckURL(ctx, url); err != nil {
+		log.LoggerWithContext(ctx, u.logger).Warn("Webhook URL not allowed", zap.String("url", url), zap.Error(err))
+		// The cause may name internal resolvers, so it is only logged.
+		return domain.ErrWebhookURLNotAllowed.WithFields(domain.FieldError{Field: "url", Message: "must resolve to a public address"}).Wrap(err)
+	}
+	return nil
+}
+
+// callerConstraints copies the constraints of the caller, so the webhook only
+// ever receives the servers that caller may see.
+func callerConstraints(ctx context.Context) entity.StringListMap {
+	constraints := entity.StringListMap{}
+	if principal := authz.FromContext(ctx); principal != nil {
+		for key, allowed := range principal.Constraints {
+			constraints[key] = append([]string(nil), allowed...)
+		}
+	}
+	return constraints
+}
+
+func generateSecret() (string, error) {
+	secret := make([]byte, SECRET_BYTES)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(secret), nil
+}
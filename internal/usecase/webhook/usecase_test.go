@@ -0,0 +1,391 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/th1enq/ViettelSMS_ServerService/internal/config"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/authz"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/dto"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/entity"
+	domain "github.com/th1enq/ViettelSMS_ServerService/internal/domain/errors"
+	repoiface "github.com/th1enq/ViettelSMS_ServerService/internal/domain/repository"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/domain/tenant"
+	"github.com/th1enq/ViettelSMS_ServerService/internal/infrastucture/service"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// memoryRepo keeps webhooks and deliveries in memory, ignoring tenants except
+// for ListEnabled.
+type memoryRepo struct {
+	mu         sync.Mutex
+	webhooks   map[uint]*entity.Webhook
+	deliveries []*entity.WebhookDelivery
+}
+
+func newMemoryRepo(webhooks ...*entity.Webhook) *memoryRepo {
+	r := &memoryRepo{webhooks: make(map[uint]*entity.Webhook)}
+	for i, webhook := range webhooks {
+		webhook.ID = uint(i + 1)
+		r.webhooks[webhook.ID] = webhook
+	}
+	return r
+}
+
+func (r *memoryRepo) Create(ctx context.Context, webhook *entity.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	webhook.ID = uint(len(r.webhooks) + 1)
+	webhook.TenantID = tenant.FromContext(ctx)
+	r.webhooks[webhook.ID] = webhook
+	return nil
+}
+func (r *memoryRepo) GetByID(ctx context.Context, id uint) (*entity.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	webhook, ok := r.webhooks[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *webhook
+	return &copied, nil
+}
+func (r *memoryRepo) List(ctx context.Context) ([]*entity.Webhook, error) {
+	return r.ListEnabled(ctx)
+}
+func (r *memoryRepo) ListEnabled(ctx context.Context) ([]*entity.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var webhooks []*entity.Webhook
+	for _, webhook := range r.webhooks {
+		if webhook.Enabled && webhook.TenantID == tenant.FromContext(ctx) {
+			copied := *webhook
+			webhooks = append(webhooks, &copied)
+		}
+	}
+	return webhooks, nil
+}
+func (r *memoryRepo) Update(ctx context.Context, webhook *entity.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.webhooks[webhook.ID] = webhook
+	return nil
+}
+func (r *memoryRepo) Delete(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.webhooks, id)
+	return nil
+}
+func (r *memoryRepo) RecordDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries = append(r.deliveries, delivery)
+	return nil
+}
+func (r *memoryRepo) ListDeliveries(ctx context.Context, webhookID uint, limit int) ([]*entity.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deliveries []*entity.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.WebhookID == webhookID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
+func (r *memoryRepo) MarkDelivered(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.webhooks[id].ConsecutiveFailures = 0
+	return nil
+}
+func (r *memoryRepo) MarkFailed(ctx context.Context, id uint, disableAfter int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	webhook := r.webhooks[id]
+	webhook.ConsecutiveFailures++
+	if webhook.ConsecutiveFailures >= disableAfter {
+		webhook.Enabled = false
+	}
+	return !webhook.Enabled, nil
+}
+
+var _ repoiface.WebhookRepository = (*memoryRepo)(nil)
+
+// newUseCase allows loopback, where the test receivers listen.
+func newUseCase(r repoiface.WebhookRepository) UseCase {
+	return newUseCaseAllowing(r, "127.0.0.0/8", "::1/128")
+}
+
+func newUseCaseAllowing(r repoiface.WebhookRepository, allowedNetworks ...string) UseCase {
+	cfg := &config.Config{
+		Webhook: config.Webhook{
+			Workers:         2,
+			Timeout:         time.Second,
+			MaxAttempts:     3,
+			InitialBackoff:  time.Millisecond,
+			MaxBackoff:      5 * time.Millisecond,
+			DisableAfter:    2,
+			AllowedNetworks: allowedNetworks,
+		},
+	}
+	sender, err := service.NewWebhookSender(cfg.Webhook)
+	if err != nil {
+		panic(err)
+	}
+	return NewWebhookUseCase(r, sender, cfg, zap.NewNop())
+}
+
+func statusChanged(serverID, group string) dto.StatusChangedEvent {
+	return dto.StatusChangedEvent{
+		TenantID: "acme",
+		ServerID: serverID,
+		Group:    group,
+		Previous: entity.ServerStatusOnline,
+		Current:  entity.ServerStatusOffline,
+	}
+}
+
+// waitDeliveries waits for n attempts to be logged, since Drain abandons
+// pending retries.
+func waitDeliveries(t *testing.T, r *memoryRepo, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		r.mu.Lock()
+		logged := len(r.deliveries)
+		r.mu.Unlock()
+		if logged >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d delivery attempts", n)
+}
+
+func drain(t *testing.T, uc UseCase) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := uc.Drain(ctx); err != nil {
+		t.Fatalf("drain: %v", err)
+	}
+}
+
+func TestStatusChanged_DeliversSignedPayloadToMatchingWebhooks(t *testing.T) {
+	const secret = "0123456789abcdef"
+	var (
+		mu       sync.Mutex
+		received []dto.WebhookPayload
+	)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		want := service.SignWebhook(secret, r.Header.Get(service.WebhookTimestampHeader), body)
+		if r.Header.Get(service.WebhookSignatureHeader) != want {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var payload dto.WebhookPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		received = append(received, payload)
+		mu.Unlock()
+	}))
+	defer receiver.Close()
+
+	r := newMemoryRepo(
+		&entity.Webhook{TenantID: "acme", URL: receiver.URL, Secret: secret, Enabled: true, Groups: entity.StringList{"web"}},
+		&entity.Webhook{TenantID: "acme", URL: receiver.URL, Secret: secret, Enabled: true, ServerIDs: entity.StringList{"other"}},
+		&entity.Webhook{TenantID: "globex", URL: receiver.URL, Secret: secret, Enabled: true},
+	)
+	uc := newUseCase(r)
+
+	uc.StatusChanged(context.Background(), statusChanged("s1", "web"))
+	drain(t, uc)
+
+	if len(received) != 1 {
+		t.Fatalf("expected one delivery, got %d", len(received))
+	}
+	if got := received[0]; got.Event != entity.WebhookEventStatusChanged || got.Data.ServerID != "s1" || got.Data.Current != entity.ServerStatusOffline {
+		t.Fatalf("unexpected payload %+v", got)
+	}
+	if len(r.deliveries) != 1 || !r.deliveries[0].Success || r.deliveries[0].WebhookID != 1 {
+		t.Fatalf("unexpected delivery log %+v", r.deliveries)
+	}
+}
+
+func TestStatusChanged_RetriesWithBackoff(t *testing.T) {
+	var calls atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer receiver.Close()
+
+	r := newMemoryRepo(&entity.Webhook{TenantID: "acme", URL: receiver.URL, Secret: "secret", Enabled: true, ConsecutiveFailures: 1})
+	uc := newUseCase(r)
+
+	uc.StatusChanged(context.Background(), statusChanged("s1", ""))
+	waitDeliveries(t, r, 3)
+	drain(t, uc)
+
+	if len(r.deliveries) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(r.deliveries))
+	}
+	if first := r.deliveries[0]; first.Success || first.StatusCode != http.StatusServiceUnavailable || first.Attempt != 1 {
+		t.Fatalf("unexpected first attempt %+v", first)
+	}
+	if last := r.deliveries[2]; !last.Success || last.Attempt != 3 {
+		t.Fatalf("unexpected last attempt %+v", last)
+	}
+	if r.webhooks[1].ConsecutiveFailures != 0 {
+		t.Fatalf("expected failures reset, got %d", r.webhooks[1].ConsecutiveFailures)
+	}
+}
+
+func TestStatusChanged_DisablesFailingWebhook(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	r := newMemoryRepo(&entity.Webhook{TenantID: "acme", URL: receiver.URL, Secret: "secret", Enabled: true})
+	uc := newUseCase(r)
+
+	uc.StatusChanged(context.Background(), statusChanged("s1", ""))
+	uc.StatusChanged(context.Background(), statusChanged("s2", ""))
+	waitDeliveries(t, r, 6)
+	drain(t, uc)
+
+	if r.webhooks[1].Enabled {
+		t.Fatalf("expected webhook disabled after 2 failed deliveries")
+	}
+	if len(r.deliveries) != 6 {
+		t.Fatalf("expected 3 attempts per delivery, got %d", len(r.deliveries))
+	}
+
+	// Drained: later events are dropped.
+	uc.StatusChanged(context.Background(), statusChanged("s3", ""))
+}
+
+func TestWebhookURL_RefusesInternalAddresses(t *testing.T) {
+	r := newMemoryRepo()
+	uc := newUseCaseAllowing(r)
+	ctx := tenant.NewContext(context.Background(), "acme")
+
+	for _, url := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://[::1]/hook",
+		"http://10.1.2.3/hook",
+		"http://192.168.0.10/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://0.0.0.0/hook",
+		"http://[::ffff:10.0.0.1]/hook",
+	} {
+		if _, err := uc.CreateWebhook(ctx, dto.CreateWebhookParams{URL: url}); !errors.Is(err, domain.ErrWebhookURLNotAllowed) {
+			t.Fatalf("create with %s: want ErrWebhookURLNotAllowed, got %v", url, err)
+		}
+	}
+
+	created, err := uc.CreateWebhook(ctx, dto.CreateWebhookParams{URL: "https://203.0.113.10/hook"})
+	if err != nil {
+		t.Fatalf("create with a public address: %v", err)
+	}
+	internal := "http://172.16.0.1/hook"
+	if _, err := uc.UpdateWebhook(ctx, created.ID, dto.UpdateWebhookParams{URL: &internal}); !errors.Is(err, domain.ErrWebhookURLNotAllowed) {
+		t.Fatalf("update: want ErrWebhookURLNotAllowed, got %v", err)
+	}
+
+	// An allow-listed network is accepted.
+	allowing := newUseCaseAllowing(r, "10.0.0.0/8")
+	if _, err := allowing.CreateWebhook(ctx, dto.CreateWebhookParams{URL: "http://10.1.2.3/hook"}); err != nil {
+		t.Fatalf("create with an allowed network: %v", err)
+	}
+}
+
+func TestStatusChanged_RefusesInternalAddressAtDial(t *testing.T) {
+	var calls atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer receiver.Close()
+
+	// Stored before the policy, or pointed at loopback by DNS since.
+	r := newMemoryRepo(&entity.Webhook{TenantID: "acme", URL: receiver.URL, Secret: "secret", Enabled: true})
+	uc := newUseCaseAllowing(r)
+
+	uc.StatusChanged(context.Background(), statusChanged("s1", ""))
+	waitDeliveries(t, r, 3)
+	drain(t, uc)
+
+	if calls.Load() != 0 {
+		t.Fatalf("receiver on loopback was called %d times", calls.Load())
+	}
+	if first := r.deliveries[0]; first.Success || !strings.Contains(first.Error, "not allowed") {
+		t.Fatalf("unexpected first attempt %+v", first)
+	}
+}
+
+func TestStatusChanged_RespectsOwnerConstraints(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []string
+	)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received = append(received, r.URL.Path)
+		mu.Unlock()
+	}))
+	defer receiver.Close()
+
+	r := newMemoryRepo()
+	uc := newUseCase(r)
+	ctx := tenant.NewContext(context.Background(), "acme")
+	restricted := authz.NewContext(ctx, &authz.Principal{Constraints: authz.Constraints{authz.ConstraintLocation: {"HN"}}})
+
+	created, err := uc.CreateWebhook(restricted, dto.CreateWebhookParams{URL: receiver.URL + "/restricted"})
+	if err != nil {
+		t.Fatalf("create restricted: %v", err)
+	}
+	if got := r.webhooks[created.ID].Constraints; len(got) != 1 || got[authz.ConstraintLocation][0] != "HN" {
+		t.Fatalf("expected the caller's constraints stored, got %v", got)
+	}
+	if _, err := uc.CreateWebhook(ctx, dto.CreateWebhookParams{URL: receiver.URL + "/open"}); err != nil {
+		t.Fatalf("create open: %v", err)
+	}
+
+	event := statusChanged("s1", "")
+	event.Location = "HCM"
+	uc.StatusChanged(ctx, event)
+	event = statusChanged("s2", "")
+	event.Location = "HN"
+	uc.StatusChanged(ctx, event)
+	drain(t, uc)
+
+	mu.Lock()
+	defer mu.Unlock()
+	restrictedCalls := 0
+	for _, path := range received {
+		if path == "/restricted" {
+			restrictedCalls++
+		}
+	}
+	if len(received) != 3 || restrictedCalls != 1 {
+		t.Fatalf("want the HCM server withheld from the restricted webhook, got %v", received)
+	}
+}
@@ -0,0 +1,10 @@
+-- +goose Up
+ALTER TABLE servers ADD COLUMN group_name VARCHAR(64);
+
+CREATE INDEX idx_servers_location ON servers (location);
+CREATE INDEX idx_servers_group_name ON servers (group_name);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_servers_group_name;
+DROP INDEX IF EXISTS idx_servers_location;
+ALTER TABLE servers DROP COLUMN IF EXISTS group_name;
@@ -0,0 +1,21 @@
+-- +goose Up
+ALTER TABLE servers ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+
+ALTER TABLE servers DROP CONSTRAINT servers_pkey;
+ALTER TABLE servers DROP CONSTRAINT servers_server_name_key;
+ALTER TABLE servers DROP CONSTRAINT servers_ipv4_key;
+
+ALTER TABLE servers ADD PRIMARY KEY (tenant_id, server_id);
+CREATE UNIQUE INDEX idx_servers_tenant_name ON servers (tenant_id, server_name);
+CREATE UNIQUE INDEX idx_servers_tenant_ipv4 ON servers (tenant_id, ipv4);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_servers_tenant_ipv4;
+DROP INDEX IF EXISTS idx_servers_tenant_name;
+ALTER TABLE servers DROP CONSTRAINT servers_pkey;
+
+ALTER TABLE servers ADD PRIMARY KEY (server_id);
+ALTER TABLE servers ADD CONSTRAINT servers_server_name_key UNIQUE (server_name);
+ALTER TABLE servers ADD CONSTRAINT servers_ipv4_key UNIQUE (ipv4);
+
+ALTER TABLE servers DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,21 @@
+-- +goose Up
+ALTER TABLE servers ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX idx_servers_deleted_at ON servers (deleted_at);
+
+-- Deleted servers keep their row, so names and addresses only need to be unique among live servers.
+DROP INDEX IF EXISTS idx_servers_tenant_name;
+DROP INDEX IF EXISTS idx_servers_tenant_ipv4;
+CREATE UNIQUE INDEX idx_servers_tenant_name ON servers (tenant_id, server_name) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_servers_tenant_ipv4 ON servers (tenant_id, ipv4) WHERE deleted_at IS NULL;
+
+-- +goose Down
+DELETE FROM servers WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_servers_tenant_name;
+DROP INDEX IF EXISTS idx_servers_tenant_ipv4;
+CREATE UNIQUE INDEX idx_servers_tenant_name ON servers (tenant_id, server_name);
+CREATE UNIQUE INDEX idx_servers_tenant_ipv4 ON servers (tenant_id, ipv4);
+
+DROP INDEX IF EXISTS idx_servers_deleted_at;
+ALTER TABLE servers DROP COLUMN IF EXISTS deleted_at;
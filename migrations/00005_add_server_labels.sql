@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE servers ADD COLUMN labels JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_servers_labels ON servers USING GIN (labels);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_servers_labels;
+ALTER TABLE servers DROP COLUMN IF EXISTS labels;
@@ -0,0 +1,59 @@
+-- +goose Up
+ALTER TABLE servers ADD COLUMN status_changed_at TIMESTAMP;
+-- The last update is the best estimate for servers that already exist;
+-- without it offline servers would never raise an offline alert.
+UPDATE servers SET status_changed_at = updated_at;
+
+CREATE INDEX idx_servers_status_changed ON servers (status, status_changed_at);
+
+CREATE TABLE server_status_transitions (
+    id BIGSERIAL PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    server_id VARCHAR(32) NOT NULL,
+    previous_status server_status NOT NULL,
+    status server_status NOT NULL,
+    changed_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_status_transitions_server ON server_status_transitions (tenant_id, server_id, changed_at);
+
+CREATE TABLE alerts (
+    id BIGSERIAL PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    rule VARCHAR(32) NOT NULL,
+    subject VARCHAR(128) NOT NULL,
+    state VARCHAR(16) NOT NULL,
+    message TEXT NOT NULL,
+    value DOUBLE PRECISION NOT NULL DEFAULT 0,
+    started_at TIMESTAMP NOT NULL,
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- At most one firing alert per rule and subject; replicas evaluating the same
+-- rule race on this index instead of creating duplicates.
+CREATE UNIQUE INDEX idx_alerts_firing ON alerts (tenant_id, rule, subject) WHERE state = 'firing';
+CREATE INDEX idx_alerts_tenant_started ON alerts (tenant_id, started_at DESC);
+
+CREATE TABLE maintenance_windows (
+    id BIGSERIAL PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    server_id VARCHAR(32) NOT NULL DEFAULT '',
+    group_name VARCHAR(64) NOT NULL DEFAULT '',
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_at > starts_at),
+    CHECK ((server_id = '') <> (group_name = ''))
+);
+
+CREATE INDEX idx_maintenance_windows_tenant ON maintenance_windows (tenant_id, ends_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS maintenance_windows;
+DROP TABLE IF EXISTS alerts;
+DROP TABLE IF EXISTS server_status_transitions;
+DROP INDEX IF EXISTS idx_servers_status_changed;
+ALTER TABLE servers DROP COLUMN IF EXISTS status_changed_at;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Webhooks only receive the servers their creator may see. Existing webhooks
+-- were created without constraints and keep receiving every server.
+ALTER TABLE webhooks ADD COLUMN constraints JSONB NOT NULL DEFAULT '{}';
+
+-- +goose Down
+ALTER TABLE webhooks DROP COLUMN IF EXISTS constraints;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- One row per tenant and scheduled run; the replica that inserts it
+-- generates the report, the others skip it.
+CREATE TABLE report_runs (
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    period_end TIMESTAMP NOT NULL,
+    claimed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant_id, period_end)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS report_runs;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- Generated reports live in the database so that every replica can list and
+-- serve the reports written by any of them.
+CREATE TABLE reports (
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    name VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    content BYTEA NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant_id, name)
+);
+
+CREATE INDEX idx_reports_tenant_created_at ON reports (tenant_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS reports;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- A reservation whose request died is reclaimed once its lock expires rather
+-- than blocking the key until the record itself expires.
+ALTER TABLE idempotency_keys ADD COLUMN locked_until TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS locked_until;